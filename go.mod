@@ -10,6 +10,7 @@ require (
 	github.com/getsentry/sentry-go v0.6.1
 	github.com/go-redis/redis v6.15.8+incompatible
 	github.com/golang/mock v1.4.3
+	github.com/golang/protobuf v1.3.1
 	github.com/gorilla/handlers v1.4.2
 	github.com/gorilla/mux v1.7.4
 	github.com/pkg/errors v0.9.1
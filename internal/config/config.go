@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -20,12 +21,40 @@ const (
 	CertKeyPath                 = "WAPI_CERT_KEY_PATH"                              // Path to certificate key file.
 	SentryDSN                   = "WAPI_SENTRY_DSN"                                 // Sentry connection string.
 	ConnectionsCheckoutDuration = "WAPI_CONNECTIONS_CHECKOUT_DURATION_MILLISECONDS" // Connections checkout durations in seconds.
+	// MediaBaseURL represents base url of stored media files, if it isn't set media is sent to webhook in base64.
+	MediaBaseURL     = "WAPI_MEDIA_BASE_URL"
+	MaxMediaSize     = "WAPI_MAX_MEDIA_SIZE_BYTES"           // Max size of media of incoming messages to be downloaded in bytes.
+	MaxUploadSize    = "WAPI_MAX_UPLOAD_SIZE_BYTES"          // Max size of media of outgoing messages in bytes.
+	QueueMaxAttempts = "WAPI_QUEUE_MAX_ATTEMPTS"             // Max attempts of sending queued message.
+	QueueRetryDelay  = "WAPI_QUEUE_RETRY_DELAY_MILLISECONDS" // Delay before first retry of sending queued message.
+	SendRate         = "WAPI_SEND_RATE_PER_MINUTE"           // Max rate of sending messages by session, 0 means unlimited.
+	SendBurst        = "WAPI_SEND_BURST"                     // Max number of messages sent by session at once.
+	SendJitter       = "WAPI_SEND_JITTER_MILLISECONDS"       // Max random delay added to retry delay of rate limited messages.
+	// IdempotencyWindow represents time in seconds during which responses of requests with idempotency key are replayed.
+	IdempotencyWindow = "WAPI_IDEMPOTENCY_WINDOW_SECONDS"
+	NumberCheckTTL    = "WAPI_NUMBER_CHECK_CACHE_SECONDS" // Time of caching results of checking numbers on WhatsApp.
+	ProfileTTL        = "WAPI_PROFILE_CACHE_SECONDS"      // Time of caching profile pictures and statuses of accounts.
+	// BackfillMessages represents number of latest messages of each chat loaded to catch up on messages received
+	// while session wasn't listening, 0 disables catching up.
+	BackfillMessages = "WAPI_BACKFILL_MESSAGES_PER_CHAT"
+	// DefaultCountryCode represents calling code of country of phone numbers given without international prefix.
+	DefaultCountryCode = "WAPI_DEFAULT_COUNTRY_CODE"
 
 	DevMode  = "dev"  // Development mode value of wapi environment.
 	ProdMode = "prod" // Production mode value of wapi environment.
 
-	DefaultConnectionsCheckoutDuration = 60 // Default timeout of establishing connection with WhatsApp service in seconds.
-	DefaultConnectionTimeout           = 20 // Default connections checkout durations in seconds.
+	DefaultConnectionsCheckoutDuration = 60               // Default timeout of establishing connection with WhatsApp service in seconds.
+	DefaultConnectionTimeout           = 20               // Default connections checkout durations in seconds.
+	DefaultMaxMediaSize                = 16 * 1024 * 1024 // Default max size of media of incoming messages in bytes.
+	DefaultMaxUploadSize               = 16 * 1024 * 1024 // Default max size of media of outgoing messages in bytes.
+	DefaultQueueMaxAttempts            = 5                // Default max attempts of sending queued message.
+	DefaultQueueRetryDelay             = 1000             // Default delay before first retry of sending queued message in milliseconds.
+	DefaultSendBurst                   = 10               // Default max number of messages sent by session at once.
+	DefaultSendJitter                  = 1000             // Default max random delay of rate limited messages in milliseconds.
+	DefaultIdempotencyWindow           = 24 * 60 * 60     // Default time of replaying responses of requests with idempotency key in seconds.
+	DefaultNumberCheckTTL              = 24 * 60 * 60     // Default time of caching results of checking numbers in seconds.
+	DefaultProfileTTL                  = 60 * 60          // Default time of caching profiles of accounts in seconds.
+	DefaultBackfillMessages            = 50               // Default number of latest messages of chat loaded to catch up.
 )
 
 // Config stores all application parameters.
@@ -38,9 +67,22 @@ type Config struct {
 	CertFilePath,
 	HTTPStaticFiles,
 	SentryDSN,
-	CertKeyPath string
+	CertKeyPath,
+	MediaBaseURL,
+	DefaultCountryCode string
 	ConnectionsCheckoutDuration,
-	ConnectionTimeout int
+	ConnectionTimeout,
+	MaxMediaSize,
+	MaxUploadSize,
+	QueueMaxAttempts,
+	QueueRetryDelay,
+	SendRate,
+	SendBurst,
+	SendJitter,
+	IdempotencyWindow,
+	NumberCheckTTL,
+	ProfileTTL,
+	BackfillMessages int
 }
 
 // New creates common config contains all application parameters.
@@ -54,6 +96,8 @@ func New() (*Config, error) {
 
 	connectionTimeout := timeout()
 
+	maxMediaSize := mediaSize()
+
 	var listenHost string
 	var ok bool
 	if listenHost, ok = os.LookupEnv(ListenHTTPHost); !ok {
@@ -75,6 +119,11 @@ func New() (*Config, error) {
 		return nil, errors.Wrap(err, "webhook param setting fail")
 	}
 
+	countryCode, err := defaultCountryCode()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		ListenHTTPHost:              listenHost,
 		ConnectionTimeout:           connectionTimeout,
@@ -85,7 +134,20 @@ func New() (*Config, error) {
 		CertFilePath:                os.Getenv(CertFilePath),
 		CertKeyPath:                 os.Getenv(CertKeyPath),
 		SentryDSN:                   os.Getenv(SentryDSN),
+		MediaBaseURL:                os.Getenv(MediaBaseURL),
+		DefaultCountryCode:          countryCode,
 		ConnectionsCheckoutDuration: checkoutDuration,
+		MaxMediaSize:                maxMediaSize,
+		MaxUploadSize:               intParam(MaxUploadSize, DefaultMaxUploadSize, 1),
+		QueueMaxAttempts:            intParam(QueueMaxAttempts, DefaultQueueMaxAttempts, 1),
+		QueueRetryDelay:             intParam(QueueRetryDelay, DefaultQueueRetryDelay, 1),
+		SendRate:                    intParam(SendRate, 0, 0),
+		SendBurst:                   intParam(SendBurst, DefaultSendBurst, 1),
+		SendJitter:                  intParam(SendJitter, DefaultSendJitter, 0),
+		IdempotencyWindow:           intParam(IdempotencyWindow, DefaultIdempotencyWindow, 1),
+		NumberCheckTTL:              intParam(NumberCheckTTL, DefaultNumberCheckTTL, 1),
+		ProfileTTL:                  intParam(ProfileTTL, DefaultProfileTTL, 1),
+		BackfillMessages:            intParam(BackfillMessages, DefaultBackfillMessages, 0),
 	}, nil
 }
 
@@ -112,6 +174,17 @@ func webHook() (string, error) {
 	return webHookURL, nil
 }
 
+func defaultCountryCode() (string, error) {
+	countryCode := strings.TrimPrefix(os.Getenv(DefaultCountryCode), "+")
+	if countryCode == "" {
+		return "", nil
+	}
+	if _, err := strconv.ParseUint(countryCode, 10, 16); err != nil || len(countryCode) > 3 {
+		return "", fmt.Errorf("`%s` param must be a calling code of 1-3 digits", DefaultCountryCode)
+	}
+	return countryCode, nil
+}
+
 func duration() int {
 	checkoutDuration, err := strconv.Atoi(os.Getenv(ConnectionsCheckoutDuration))
 	if err != nil {
@@ -127,3 +200,20 @@ func timeout() int {
 	}
 	return connectionTimeout
 }
+
+func mediaSize() int {
+	maxMediaSize, err := strconv.Atoi(os.Getenv(MaxMediaSize))
+	if err != nil || maxMediaSize < 0 {
+		return DefaultMaxMediaSize
+	}
+	return maxMediaSize
+}
+
+// intParam provides integer param, default value is used if param isn't set or less than min value.
+func intParam(param string, defaultValue, minValue int) int {
+	value, err := strconv.Atoi(os.Getenv(param))
+	if err != nil || value < minValue {
+		return defaultValue
+	}
+	return value
+}
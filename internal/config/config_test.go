@@ -20,6 +20,18 @@ var defaultEnvs = map[string]string{
 	CertKeyPath:                 "/tmp/cert.key",
 	SentryDSN:                   "dsn@sentry.io/test",
 	ConnectionsCheckoutDuration: "60",
+	MaxMediaSize:                "1024",
+	MaxUploadSize:               "2048",
+	QueueMaxAttempts:            "3",
+	QueueRetryDelay:             "500",
+	SendRate:                    "60",
+	SendBurst:                   "5",
+	SendJitter:                  "0",
+	IdempotencyWindow:           "60",
+	NumberCheckTTL:              "60",
+	ProfileTTL:                  "60",
+	BackfillMessages:            "0",
+	DefaultCountryCode:          "+375",
 }
 
 func setEnvs(customEnvs map[string]string, excludedEnvs []string) (err error) {
@@ -76,6 +88,11 @@ func TestNotValidEnvVars(t *testing.T) {
 			envVars:         map[string]string{},
 			excludedEnvVars: []string{WebHookURL},
 		},
+		{
+			name:            fmt.Sprintf("Invalid `%s` env variable", DefaultCountryCode),
+			envVars:         map[string]string{DefaultCountryCode: "+37a"},
+			excludedEnvVars: []string{},
+		},
 		{
 			name:            fmt.Sprintf("Var `%s` must contain triling slash", WebHookURL),
 			envVars:         map[string]string{WebHookURL: "/wh"},
@@ -132,3 +149,114 @@ func TestDefaultConnectionTimeoutParam(t *testing.T) {
 	}
 	assert.Equal(t, DefaultConnectionTimeout, conf.ConnectionTimeout)
 }
+
+func TestDefaultMaxMediaSizeParam(t *testing.T) {
+	err := setEnvs(map[string]string{}, []string{MaxMediaSize})
+	require.Nil(t, err)
+
+	conf, err := New()
+	require.Nil(t, err)
+
+	assert.Equal(t, DefaultMaxMediaSize, conf.MaxMediaSize)
+}
+
+func TestDefaultQueueParams(t *testing.T) {
+	err := setEnvs(map[string]string{QueueMaxAttempts: "-1"}, []string{QueueRetryDelay})
+	require.Nil(t, err)
+
+	conf, err := New()
+	require.Nil(t, err)
+
+	assert.Equal(t, DefaultQueueMaxAttempts, conf.QueueMaxAttempts)
+	assert.Equal(t, DefaultQueueRetryDelay, conf.QueueRetryDelay)
+}
+
+func TestDefaultSendRateParams(t *testing.T) {
+	err := setEnvs(map[string]string{SendBurst: "0"}, []string{SendRate, SendJitter})
+	require.Nil(t, err)
+
+	conf, err := New()
+	require.Nil(t, err)
+
+	assert.Equal(t, 0, conf.SendRate)
+	assert.Equal(t, DefaultSendBurst, conf.SendBurst)
+	assert.Equal(t, DefaultSendJitter, conf.SendJitter)
+}
+
+func TestDefaultIdempotencyWindowParam(t *testing.T) {
+	err := setEnvs(map[string]string{}, []string{IdempotencyWindow})
+	require.Nil(t, err)
+
+	conf, err := New()
+	require.Nil(t, err)
+
+	assert.Equal(t, DefaultIdempotencyWindow, conf.IdempotencyWindow)
+}
+
+func TestDefaultNumberCheckTTLParam(t *testing.T) {
+	err := setEnvs(map[string]string{}, []string{NumberCheckTTL})
+	require.Nil(t, err)
+
+	conf, err := New()
+	require.Nil(t, err)
+
+	assert.Equal(t, DefaultNumberCheckTTL, conf.NumberCheckTTL)
+}
+
+func TestDefaultProfileTTLParam(t *testing.T) {
+	err := setEnvs(map[string]string{}, []string{ProfileTTL})
+	require.Nil(t, err)
+
+	conf, err := New()
+	require.Nil(t, err)
+
+	assert.Equal(t, DefaultProfileTTL, conf.ProfileTTL)
+}
+
+func TestBackfillMessagesParam(t *testing.T) {
+	err := setEnvs(map[string]string{}, []string{})
+	require.Nil(t, err)
+
+	conf, err := New()
+	require.Nil(t, err)
+	assert.Equal(t, 0, conf.BackfillMessages)
+
+	err = setEnvs(map[string]string{}, []string{BackfillMessages})
+	require.Nil(t, err)
+
+	conf, err = New()
+	require.Nil(t, err)
+	assert.Equal(t, DefaultBackfillMessages, conf.BackfillMessages)
+}
+
+func TestDefaultMaxUploadSizeParam(t *testing.T) {
+	err := setEnvs(map[string]string{}, []string{})
+	require.Nil(t, err)
+
+	conf, err := New()
+	require.Nil(t, err)
+	assert.Equal(t, 2048, conf.MaxUploadSize)
+
+	err = setEnvs(map[string]string{MaxUploadSize: "0"}, []string{})
+	require.Nil(t, err)
+
+	conf, err = New()
+	require.Nil(t, err)
+	assert.Equal(t, DefaultMaxUploadSize, conf.MaxUploadSize)
+}
+
+func TestDefaultCountryCodeParam(t *testing.T) {
+	err := setEnvs(map[string]string{}, []string{})
+	require.Nil(t, err)
+
+	conf, err := New()
+	require.Nil(t, err)
+	assert.Equal(t, "375", conf.DefaultCountryCode)
+
+	err = setEnvs(map[string]string{}, []string{DefaultCountryCode})
+	require.Nil(t, err)
+
+	conf, err = New()
+	require.Nil(t, err)
+	assert.Empty(t, conf.DefaultCountryCode)
+}
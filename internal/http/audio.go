@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+
+	httpInfra "github.com/r-erema/wapi/internal/infrastructure/http"
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/repository"
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/Rhymen/go-whatsapp"
+)
+
+// Mime type of voice notes, WhatsApp plays only opus encoded ogg audio as voice notes.
+const voiceNoteMimeType = "audio/ogg; codecs=opus"
+
+// SendAudioHandler is responsible for sending audios.
+type SendAudioHandler struct {
+	auth   service.Authorizer
+	sender *mediaSender
+}
+
+// NewAudioHandler creates SendAudioHandler.
+func NewAudioHandler(
+	authorizer service.Authorizer,
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+	msgRepo repository.Message,
+	client httpInfra.Client,
+	marshal *jsonInfra.MarshallCallback,
+	maxUploadSize int64,
+) *SendAudioHandler {
+	return &SendAudioHandler{
+		auth:   authorizer,
+		sender: newMediaSender(connectionsSupervisor, jidNormalizer, msgRepo, client, marshal, "audio", maxUploadSize),
+	}
+}
+
+// Handle sends message with client`s audio to WhatsApp server.
+func (h *SendAudioHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	var msgReq SendAudioRequest
+	upload, appErr := h.sender.decodeRequest(w, r, &msgReq)
+	if appErr != nil {
+		return appErr
+	}
+
+	source, appErr := h.sender.source(upload, msgReq.AudioBase64, msgReq.AudioURL)
+	if appErr != nil {
+		return appErr
+	}
+
+	return h.sender.send(w, msgReq.SessionID, msgReq.ChatID, source, func(info whatsapp.MessageInfo, content *mediaContent) interface{} {
+		mimeType := detectMimeType(content.head, content.fileName, msgReq.MimeType)
+		if msgReq.Ptt && (mimeType == "application/ogg" || mimeType == "audio/ogg") {
+			mimeType = voiceNoteMimeType
+		}
+		return whatsapp.AudioMessage{
+			Info:    info,
+			Type:    mimeType,
+			Ptt:     msgReq.Ptt,
+			Content: content,
+		}
+	})
+}
+
+// SendAudioRequest is the request for sending audio to WhatsApp.
+// Ptt flag makes audio to be sent as voice note.
+type SendAudioRequest struct {
+	SessionID   string `json:"session_name"`
+	ChatID      string `json:"chat_id"`
+	AudioURL    string `json:"audio_url"`
+	AudioBase64 string `json:"audio_base64"`
+	MimeType    string `json:"mime_type"`
+	Ptt         bool   `json:"ptt"`
+}
@@ -0,0 +1,93 @@
+package http_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	httpTest "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/gavv/httpexpect"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAudioHandler(t *testing.T) {
+	handler := internalHttp.NewAudioHandler(mocks(t))
+	assert.NotNil(t, handler)
+}
+
+func TestSendAudioHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      *internalHttp.SendAudioRequest
+		expectType   string
+		expectStatus int
+	}{
+		{
+			name: "Voice note",
+			request: &internalHttp.SendAudioRequest{
+				SessionID: "_sid_",
+				ChatID:    "+000000000000",
+				AudioURL:  "https://host/voice",
+				Ptt:       true,
+			},
+			expectType:   "audio/ogg; codecs=opus",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name: "Audio with explicit mime type",
+			request: &internalHttp.SendAudioRequest{
+				SessionID: "_sid_",
+				ChatID:    "+000000000000",
+				AudioURL:  "https://host/voice",
+				MimeType:  "audio/mpeg",
+			},
+			expectType:   "audio/mpeg",
+			expectStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			wac := mock.NewMockConn(c)
+			wac.EXPECT().Info().Return(&whatsapp.Info{Wid: "wid"})
+			wac.EXPECT().Send(gomock.Any()).DoAndReturn(func(msg interface{}) (string, error) {
+				audio := msg.(whatsapp.AudioMessage)
+				assert.Equal(t, tt.expectType, audio.Type)
+				assert.Equal(t, tt.request.Ptt, audio.Ptt)
+				return "MSG_ID", nil
+			})
+			connections := mock.NewMockConnections(c)
+			connections.EXPECT().
+				AuthenticatedConnectionForSession(gomock.Any()).
+				Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+			httpClient := mock.NewMockClient(c)
+			httpClient.EXPECT().
+				Get(gomock.Any()).
+				Return(&http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewBufferString("OggS\x00"))}, nil)
+			marshal := jsonInfra.MarshallCallback(func(i interface{}) ([]byte, error) {
+				return []byte("{}"), nil
+			})
+
+			handler := internalHttp.NewAudioHandler(mock.NewMockAuthorizer(c), connections, service.NewJidNormalizer(""), sentStatusRepo(c), httpClient, &marshal, testMaxUploadSize)
+			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{"/send-audio/": handler})
+			defer server.Close()
+
+			expect := httpexpect.New(t, server.URL)
+			expect.POST("/send-audio/").
+				WithJSON(tt.request).
+				Expect().
+				Status(tt.expectStatus)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/Rhymen/go-whatsapp"
+	waProto "github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// SendBulkHandler is responsible for sending text message to a list of recipients.
+type SendBulkHandler struct {
+	bulkSender    service.BulkSender
+	templateRepo  repository.Template
+	jidNormalizer *service.JidNormalizer
+}
+
+// NewSendBulkHandler creates SendBulkHandler.
+func NewSendBulkHandler(
+	bulkSender service.BulkSender,
+	templateRepo repository.Template,
+	jidNormalizer *service.JidNormalizer,
+) *SendBulkHandler {
+	return &SendBulkHandler{bulkSender: bulkSender, templateRepo: templateRepo, jidNormalizer: jidNormalizer}
+}
+
+// Handle queues messages to recipients and writes created job to response.
+func (handler *SendBulkHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	var bulkReq SendBulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&bulkReq); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "decoding error in bulk handler"),
+			ResponseMsg: "can't decode request",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	if (bulkReq.Text == "" && bulkReq.TemplateID == "") || len(bulkReq.Recipients) == 0 {
+		return &AppError{
+			Error:       errors.New("bulk without text or recipients in bulk handler"),
+			ResponseMsg: "text or template_id and recipients are required",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	template, appErr := messageTemplate(handler.templateRepo, bulkReq.Text, bulkReq.TemplateID)
+	if appErr != nil {
+		return appErr
+	}
+
+	messages := make([]*waProto.WebMessageInfo, 0, len(bulkReq.Recipients))
+	for _, recipient := range bulkReq.Recipients {
+		chatID, err := handler.jidNormalizer.Normalize(recipient.ChatID)
+		if err != nil {
+			return &AppError{
+				Error:       errors.Wrap(err, "invalid chat id of recipient in bulk handler"),
+				ResponseMsg: "invalid chat_id of recipient: " + err.Error(),
+				Code:        http.StatusBadRequest,
+			}
+		}
+		text := bulkReq.Text
+		if template != nil {
+			locale := recipient.Locale
+			if locale == "" {
+				locale = bulkReq.Locale
+			}
+			text = template.Text(locale)
+		}
+		text, appErr = renderText(text, recipient.Vars)
+		if appErr != nil {
+			return appErr
+		}
+		msgReq := &SendMessageRequest{ChatID: chatID, Text: text}
+		messages = append(messages, newReplyMessage(whatsapp.MessageInfo{RemoteJid: chatID}, msgReq).Proto())
+	}
+
+	job, err := handler.bulkSender.Send(bulkReq.SessionID, messages)
+	if err != nil {
+		appErr := &AppError{
+			Error:       errors.Wrap(err, "sending bulk error in bulk handler"),
+			ResponseMsg: "sending bulk error",
+			Code:        http.StatusInternalServerError,
+		}
+		if job != nil {
+			appErr.ResponseMsg = fmt.Sprintf("sending bulk error, messages of job `%s` are queued partially", job.ID)
+		}
+		return appErr
+	}
+	return writeBulkJob(w, http.StatusAccepted, job)
+}
+
+// BulkJobHandler provides progress and results of bulk job.
+type BulkJobHandler struct {
+	bulkSender service.BulkSender
+}
+
+// NewBulkJobHandler creates BulkJobHandler.
+func NewBulkJobHandler(bulkSender service.BulkSender) *BulkJobHandler {
+	return &BulkJobHandler{bulkSender: bulkSender}
+}
+
+// Handle sends bulk job info.
+func (handler *BulkJobHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	jobID := mux.Vars(r)["jobID"]
+	job, err := handler.bulkSender.Job(jobID)
+	if appErr := bulkJobError(job, jobID, err); appErr != nil {
+		return appErr
+	}
+	return writeBulkJob(w, http.StatusOK, job)
+}
+
+// CancelBulkJobHandler cancels messages of bulk job which aren't sent yet.
+type CancelBulkJobHandler struct {
+	bulkSender service.BulkSender
+}
+
+// NewCancelBulkJobHandler creates CancelBulkJobHandler.
+func NewCancelBulkJobHandler(bulkSender service.BulkSender) *CancelBulkJobHandler {
+	return &CancelBulkJobHandler{bulkSender: bulkSender}
+}
+
+// Handle cancels bulk job and writes it to response.
+func (handler *CancelBulkJobHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	jobID := mux.Vars(r)["jobID"]
+	job, err := handler.bulkSender.Cancel(jobID)
+	if appErr := bulkJobError(job, jobID, err); appErr != nil {
+		return appErr
+	}
+	return writeBulkJob(w, http.StatusOK, job)
+}
+
+func bulkJobError(job *model.BulkJob, jobID string, err error) *AppError {
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "bulk job processing error in bulk job handler"),
+			ResponseMsg: "bulk job processing error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	if job == nil {
+		return &AppError{
+			Error:       errors.Errorf("bulk job `%s` not found in bulk job handler", jobID),
+			ResponseMsg: "bulk job not found",
+			Code:        http.StatusNotFound,
+		}
+	}
+	return nil
+}
+
+func writeBulkJob(w http.ResponseWriter, status int, job *model.BulkJob) *AppError {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "bulk job encoding error in bulk job handler"),
+			ResponseMsg: "can't encode bulk job",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
+
+// SendBulkRequest is the request for sending text message to a list of recipients by session,
+// message is either text or template, both are Go templates rendered with variables of each recipient.
+// Locale of template text is taken from recipient or from request if recipient has no locale.
+type SendBulkRequest struct {
+	SessionID  string                 `json:"session_name"`
+	Text       string                 `json:"text"`
+	TemplateID string                 `json:"template_id"`
+	Locale     string                 `json:"locale"`
+	Recipients []BulkRecipientRequest `json:"recipients"`
+}
+
+// BulkRecipientRequest is a recipient of bulk message along with its template variables and locale.
+type BulkRecipientRequest struct {
+	ChatID string            `json:"chat_id"`
+	Locale string            `json:"locale"`
+	Vars   map[string]string `json:"vars"`
+}
@@ -0,0 +1,218 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	testHttp "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	waProto "github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendBulkHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      *internalHttp.SendBulkRequest
+		sendErr      error
+		expectTexts  []string
+		expectStatus int
+	}{
+		{
+			name: "OK",
+			request: &internalHttp.SendBulkRequest{
+				SessionID: "_sid_",
+				Text:      "Hello, {{.name}}! Your code is {{.code}}",
+				Recipients: []internalHttp.BulkRecipientRequest{
+					{ChatID: "+000000000001", Vars: map[string]string{"name": "John", "code": "42"}},
+					{ChatID: "+000000000002", Vars: map[string]string{"name": "Jane", "code": "43"}},
+				},
+			},
+			expectTexts:  []string{"Hello, John! Your code is 42", "Hello, Jane! Your code is 43"},
+			expectStatus: http.StatusAccepted,
+		},
+		{
+			name: "Template with locales",
+			request: &internalHttp.SendBulkRequest{
+				SessionID:  "_sid_",
+				TemplateID: "order_ready",
+				Locale:     "ru",
+				Recipients: []internalHttp.BulkRecipientRequest{
+					{ChatID: "+000000000001", Vars: map[string]string{"order": "#42"}},
+					{ChatID: "+000000000002", Locale: "en-US", Vars: map[string]string{"order": "#43"}},
+				},
+			},
+			expectTexts:  []string{"Заказ #42 готов", "Order #43 is ready"},
+			expectStatus: http.StatusAccepted,
+		},
+		{
+			name: "Missing variable",
+			request: &internalHttp.SendBulkRequest{
+				SessionID: "_sid_",
+				Text:      "Hello, {{.name}}!",
+				Recipients: []internalHttp.BulkRecipientRequest{
+					{ChatID: "+000000000001", Vars: map[string]string{"code": "42"}},
+				},
+			},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Template not found",
+			request: &internalHttp.SendBulkRequest{
+				SessionID:  "_sid_",
+				TemplateID: "unknown",
+				Recipients: []internalHttp.BulkRecipientRequest{{ChatID: "+000000000001"}},
+			},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "No recipients",
+			request:      &internalHttp.SendBulkRequest{SessionID: "_sid_", Text: "Hello"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Recipient without chat id",
+			request: &internalHttp.SendBulkRequest{
+				SessionID:  "_sid_",
+				Text:       "Hello",
+				Recipients: []internalHttp.BulkRecipientRequest{{Vars: map[string]string{"name": "John"}}},
+			},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Recipient with invalid chat id",
+			request: &internalHttp.SendBulkRequest{
+				SessionID:  "_sid_",
+				Text:       "Hello",
+				Recipients: []internalHttp.BulkRecipientRequest{{ChatID: "+375 44 CALL-ME"}},
+			},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Sending error",
+			request: &internalHttp.SendBulkRequest{
+				SessionID:  "_sid_",
+				Text:       "Hello",
+				Recipients: []internalHttp.BulkRecipientRequest{{ChatID: "+000000000001"}},
+			},
+			sendErr:      errors.New("redis is down"),
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			bulkSender := mock.NewMockBulkSender(c)
+			if tt.expectStatus != http.StatusBadRequest {
+				bulkSender.EXPECT().
+					Send("_sid_", gomock.Any()).
+					DoAndReturn(func(sessionID string, messages []*waProto.WebMessageInfo) (*model.BulkJob, error) {
+						if tt.sendErr != nil {
+							return nil, tt.sendErr
+						}
+						for i, text := range tt.expectTexts {
+							assert.Equal(t, text, messages[i].GetMessage().GetConversation())
+						}
+						assert.Equal(t, "000000000002@s.whatsapp.net", messages[1].GetKey().GetRemoteJid())
+						return &model.BulkJob{ID: "_job_id_", Status: model.RunningBulkStatus}, nil
+					})
+			}
+			templateRepo := mock.NewMockTemplate(c)
+			templateRepo.EXPECT().Template("order_ready").Return(&model.Template{
+				ID:            "order_ready",
+				DefaultLocale: "en",
+				Locales:       map[string]string{"en": "Order {{.order}} is ready", "ru": "Заказ {{.order}} готов"},
+			}, nil).AnyTimes()
+			templateRepo.EXPECT().Template("unknown").Return(nil, nil).AnyTimes()
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/send-bulk/": internalHttp.NewSendBulkHandler(bulkSender, templateRepo, service.NewJidNormalizer("")),
+			})
+			defer server.Close()
+
+			response := httpexpect.New(t, server.URL).POST("/send-bulk/").
+				WithJSON(tt.request).
+				Expect().
+				Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusAccepted {
+				response.JSON().Object().
+					ValueEqual("id", "_job_id_").
+					ValueEqual("status", model.RunningBulkStatus)
+			}
+		})
+	}
+}
+
+func TestBulkJobHandlers_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		job          *model.BulkJob
+		err          error
+		expectStatus int
+	}{
+		{
+			name:         "Get OK",
+			method:       http.MethodGet,
+			job:          &model.BulkJob{ID: "_job_id_", Status: model.CompletedBulkStatus},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Get not found",
+			method:       http.MethodGet,
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "Get error",
+			method:       http.MethodGet,
+			err:          errors.New("redis is down"),
+			expectStatus: http.StatusInternalServerError,
+		},
+		{
+			name:         "Cancel OK",
+			method:       http.MethodDelete,
+			job:          &model.BulkJob{ID: "_job_id_", Status: model.CancelledBulkStatus},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Cancel not found",
+			method:       http.MethodDelete,
+			expectStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			bulkSender := mock.NewMockBulkSender(c)
+			var handler internalHttp.AppHTTPHandler
+			if tt.method == http.MethodGet {
+				bulkSender.EXPECT().Job("_job_id_").Return(tt.job, tt.err)
+				handler = internalHttp.NewBulkJobHandler(bulkSender)
+			} else {
+				bulkSender.EXPECT().Cancel("_job_id_").Return(tt.job, tt.err)
+				handler = internalHttp.NewCancelBulkJobHandler(bulkSender)
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{"/bulk-jobs/{jobID}/": handler})
+			defer server.Close()
+
+			response := httpexpect.New(t, server.URL).Request(tt.method, "/bulk-jobs/_job_id_/").
+				Expect().
+				Status(tt.expectStatus)
+			if tt.job != nil {
+				response.JSON().Object().ValueEqual("status", tt.job.Status)
+			}
+		})
+	}
+}
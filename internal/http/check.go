@@ -0,0 +1,67 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/pkg/errors"
+)
+
+// maxCheckedNumbers limits count of phone numbers checked by one request.
+const maxCheckedNumbers = 100
+
+// CheckNumbersHandler is responsible for checking whether phone numbers are registered on WhatsApp.
+type CheckNumbersHandler struct {
+	numberChecker service.NumberChecker
+}
+
+// NewCheckNumbersHandler creates CheckNumbersHandler.
+func NewCheckNumbersHandler(numberChecker service.NumberChecker) *CheckNumbersHandler {
+	return &CheckNumbersHandler{numberChecker: numberChecker}
+}
+
+// Handle checks phone numbers and writes results to response in order of requested numbers.
+func (handler *CheckNumbersHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	var checkReq CheckNumbersRequest
+	if err := json.NewDecoder(r.Body).Decode(&checkReq); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "decoding error in check numbers handler"),
+			ResponseMsg: "can't decode request",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	if len(checkReq.Phones) == 0 || len(checkReq.Phones) > maxCheckedNumbers {
+		return &AppError{
+			Error:       errors.Errorf("%d phones requested in check numbers handler", len(checkReq.Phones)),
+			ResponseMsg: "phones must contain from 1 to 100 numbers",
+			Code:        http.StatusBadRequest,
+		}
+	}
+
+	results, err := handler.numberChecker.Check(checkReq.SessionID, checkReq.Phones)
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "numbers checking error in check numbers handler"),
+			ResponseMsg: "can't check numbers, session isn't connected",
+			Code:        http.StatusBadRequest,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(results); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "results encoding error in check numbers handler"),
+			ResponseMsg: "can't encode results",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
+
+// CheckNumbersRequest is a list of phone numbers in E.164 format to check by session connection.
+type CheckNumbersRequest struct {
+	SessionID string   `json:"session_name"`
+	Phones    []string `json:"phones"`
+}
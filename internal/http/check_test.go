@@ -0,0 +1,94 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/model"
+	testHttp "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCheckNumbersHandler(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	assert.NotNil(t, internalHttp.NewCheckNumbersHandler(mock.NewMockNumberChecker(mockCtrl)))
+}
+
+func TestCheckNumbersHandler_ServeHTTP(t *testing.T) {
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("+375%09d", i)
+	}
+
+	tests := []struct {
+		name         string
+		request      interface{}
+		checkErr     error
+		expectCheck  bool
+		expectStatus int
+	}{
+		{
+			name:         "OK",
+			request:      internalHttp.CheckNumbersRequest{SessionID: "_sid_", Phones: []string{"+375000000001"}},
+			expectCheck:  true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Session isn't connected",
+			request:      internalHttp.CheckNumbersRequest{SessionID: "_sid_", Phones: []string{"+375000000001"}},
+			checkErr:     fmt.Errorf("something went wrong... "),
+			expectCheck:  true,
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "No phones",
+			request:      internalHttp.CheckNumbersRequest{SessionID: "_sid_"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Too many phones",
+			request:      internalHttp.CheckNumbersRequest{SessionID: "_sid_", Phones: tooMany},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Invalid request",
+			request:      "invalid",
+			expectStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			checker := mock.NewMockNumberChecker(mockCtrl)
+			if tt.expectCheck {
+				checker.EXPECT().
+					Check("_sid_", []string{"+375000000001"}).
+					Return([]model.NumberCheck{{Phone: "+375000000001", Jid: "375000000001@s.whatsapp.net", Exists: true}}, tt.checkErr)
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/check-numbers/": internalHttp.NewCheckNumbersHandler(checker),
+			})
+			defer server.Close()
+			expect := httpexpect.New(t, server.URL)
+
+			response := expect.POST("/check-numbers/").
+				WithJSON(tt.request).
+				Expect().
+				Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusOK {
+				result := response.JSON().Array().Element(0).Object()
+				result.ValueEqual("jid", "375000000001@s.whatsapp.net")
+				result.ValueEqual("exists", true)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/pkg/errors"
+)
+
+// SendContactHandler is responsible for sending contact cards.
+type SendContactHandler struct {
+	auth      service.Authorizer
+	sender    *messageSender
+	scheduler service.Scheduler
+}
+
+// NewContactHandler creates SendContactHandler.
+func NewContactHandler(
+	authorizer service.Authorizer,
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+	msgRepo repository.Message,
+	scheduler service.Scheduler,
+	marshal *jsonInfra.MarshallCallback,
+) *SendContactHandler {
+	return &SendContactHandler{
+		auth:      authorizer,
+		sender:    newMessageSender(connectionsSupervisor, jidNormalizer, msgRepo, marshal, "contact"),
+		scheduler: scheduler,
+	}
+}
+
+// Handle sends contact card message to WhatsApp server, vCard is generated from contact fields of request.
+// Message with sending time is scheduled.
+func (h *SendContactHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	decoder := json.NewDecoder(r.Body)
+	var msgReq SendContactRequest
+	err := decoder.Decode(&msgReq)
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "can't decode request in contact handler"),
+			ResponseMsg: "can't decode request",
+			Code:        http.StatusBadRequest,
+		}
+	}
+
+	contact := msgReq.Contact
+	if contact.FullName == "" {
+		contact.FullName = strings.TrimSpace(contact.FirstName + " " + contact.LastName)
+	}
+	if contact.FullName == "" || len(contact.Phones) == 0 {
+		return &AppError{
+			Error:       errors.New("contact without name or phones in contact handler"),
+			ResponseMsg: "contact must have name and at least one phone",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	for i, phone := range contact.Phones {
+		if phone.WaID == "" {
+			contact.Phones[i].WaID = phoneDigits(phone.Number)
+		}
+	}
+
+	vCard := contact.String()
+	if msgReq.SendAt != nil {
+		chatID, appErr := h.sender.chatJid(msgReq.ChatID)
+		if appErr != nil {
+			return appErr
+		}
+		message := newProtoMessage(chatID, &proto.Message{ContactMessage: &proto.ContactMessage{
+			DisplayName: &contact.FullName,
+			Vcard:       &vCard,
+		}})
+		return h.sender.schedule(w, h.scheduler, msgReq.SessionID, message, *msgReq.SendAt)
+	}
+
+	return h.sender.send(w, msgReq.SessionID, msgReq.ChatID, func(info whatsapp.MessageInfo) (interface{}, *AppError) {
+		return whatsapp.ContactMessage{
+			Info:        info,
+			DisplayName: contact.FullName,
+			Vcard:       vCard,
+		}, nil
+	})
+}
+
+func phoneDigits(number string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, number)
+}
+
+// SendContactRequest is the request for sending contact card to WhatsApp, contact with sending time is scheduled.
+type SendContactRequest struct {
+	SessionID string      `json:"session_name"`
+	ChatID    string      `json:"chat_id"`
+	Contact   model.VCard `json:"contact"`
+	SendAt    *time.Time  `json:"send_at"`
+}
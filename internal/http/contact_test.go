@@ -0,0 +1,140 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	httpTest "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/gavv/httpexpect"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContactHandler(t *testing.T) {
+	handler := internalHttp.NewContactHandler(mocksSchedulingHandler(t))
+	assert.NotNil(t, handler)
+}
+
+func TestSendContactHandler(t *testing.T) {
+	sendAt := time.Now().Add(time.Hour)
+	tests := []struct {
+		name         string
+		request      interface{}
+		expectStatus int
+	}{
+		{
+			name:         "OK",
+			request:      contactRequest(),
+			expectStatus: http.StatusOK,
+		},
+		{
+			name: "Scheduled contact",
+			request: func() interface{} {
+				request := contactRequest().(*internalHttp.SendContactRequest)
+				request.SendAt = &sendAt
+				return request
+			}(),
+			expectStatus: http.StatusAccepted,
+		},
+		{
+			name:         "Bad contact request",
+			request:      "",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Contact without phones",
+			request: &internalHttp.SendContactRequest{
+				SessionID: "_sid_",
+				ChatID:    "+000000000000",
+				Contact:   model.VCard{FullName: "John Doe"},
+			},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Contact without name",
+			request: &internalHttp.SendContactRequest{
+				SessionID: "_sid_",
+				ChatID:    "+000000000000",
+				Contact:   model.VCard{Phones: []model.VCardPhone{{Number: "+1 555 123 4567"}}},
+			},
+			expectStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{
+				"/send-contact/": internalHttp.NewContactHandler(mocksSchedulingHandler(t)),
+			})
+			defer server.Close()
+
+			expect := httpexpect.New(t, server.URL)
+			expect.POST("/send-contact/").
+				WithJSON(tt.request).
+				Expect().
+				Status(tt.expectStatus)
+		})
+	}
+}
+
+func TestSendContactHandler_VCard(t *testing.T) {
+	c := gomock.NewController(t)
+	var sent whatsapp.ContactMessage
+	wac := mock.NewMockConn(c)
+	wac.EXPECT().Info().Return(&whatsapp.Info{Wid: "wid"})
+	wac.EXPECT().Send(gomock.Any()).DoAndReturn(func(msg interface{}) (string, error) {
+		sent = msg.(whatsapp.ContactMessage)
+		return sent.Info.Id, nil
+	})
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession(gomock.Any()).
+		Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+	marshal := jsonInfra.MarshallCallback(json.Marshal)
+
+	server := httpTest.New(map[string]internalHttp.AppHTTPHandler{
+		"/send-contact/": internalHttp.NewContactHandler(mock.NewMockAuthorizer(c), connections, service.NewJidNormalizer(""), sentStatusRepo(c), mock.NewMockScheduler(c), &marshal),
+	})
+	defer server.Close()
+
+	expect := httpexpect.New(t, server.URL)
+	response := expect.POST("/send-contact/").
+		WithJSON(contactRequest()).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+
+	require.NotEmpty(t, sent.Info.Id)
+	response.ValueEqual("id", sent.Info.Id).
+		ValueEqual("session_name", "_sid_").
+		ValueEqual("status", model.SentDeliveryStatus)
+
+	assert.Equal(t, "John Doe", sent.DisplayName)
+	card := model.ParseVCard(sent.Vcard)
+	assert.Equal(t, "John Doe", card.FullName)
+	assert.Equal(t, "Doe", card.LastName)
+	assert.Equal(t, []model.VCardPhone{{Number: "+1 555 123 4567", Type: "cell", WaID: "15551234567"}}, card.Phones)
+}
+
+func contactRequest() interface{} {
+	return &internalHttp.SendContactRequest{
+		SessionID: "_sid_",
+		ChatID:    "+000000000000",
+		Contact: model.VCard{
+			FirstName: "John",
+			LastName:  "Doe",
+			Phones:    []model.VCardPhone{{Number: "+1 555 123 4567", Type: "CELL"}},
+		},
+	}
+}
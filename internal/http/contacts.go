@@ -0,0 +1,155 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// Limits of page size of contacts and chats lists.
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// ContactsHandler provides address book of session.
+type ContactsHandler struct {
+	contactRepo repository.Contact
+}
+
+// NewContactsHandler creates ContactsHandler.
+func NewContactsHandler(contactRepo repository.Contact) *ContactsHandler {
+	return &ContactsHandler{contactRepo: contactRepo}
+}
+
+// Handle sends page of contacts, which names or jids contain `search` query param if it's passed.
+func (handler *ContactsHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	offset, limit, appErr := pageParams(r)
+	if appErr != nil {
+		return appErr
+	}
+	contacts, err := handler.contactRepo.Contacts(mux.Vars(r)["sessionID"])
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "contacts reading error in contacts handler"),
+			ResponseMsg: "can't get contacts",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+
+	search := r.URL.Query().Get("search")
+	found := make([]model.Contact, 0, len(contacts))
+	for i := range contacts {
+		if contacts[i].Matches(search) {
+			found = append(found, contacts[i])
+		}
+	}
+	from, to := pageBounds(len(found), offset, limit)
+	return writePageJSON(w, &ContactsResponse{Total: len(found), Offset: offset, Limit: limit, Contacts: found[from:to]})
+}
+
+// ChatsHandler provides chats list of session.
+type ChatsHandler struct {
+	contactRepo repository.Contact
+}
+
+// NewChatsHandler creates ChatsHandler.
+func NewChatsHandler(contactRepo repository.Contact) *ChatsHandler {
+	return &ChatsHandler{contactRepo: contactRepo}
+}
+
+// Handle sends page of chats, which names or jids contain `search` query param if it's passed.
+func (handler *ChatsHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	offset, limit, appErr := pageParams(r)
+	if appErr != nil {
+		return appErr
+	}
+	chats, err := handler.contactRepo.Chats(mux.Vars(r)["sessionID"])
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "chats reading error in chats handler"),
+			ResponseMsg: "can't get chats",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+
+	search := r.URL.Query().Get("search")
+	found := make([]model.Chat, 0, len(chats))
+	for i := range chats {
+		if chats[i].Matches(search) {
+			found = append(found, chats[i])
+		}
+	}
+	from, to := pageBounds(len(found), offset, limit)
+	return writePageJSON(w, &ChatsResponse{Total: len(found), Offset: offset, Limit: limit, Chats: found[from:to]})
+}
+
+// ContactsResponse is a page of contacts, total is a count of contacts matching search.
+type ContactsResponse struct {
+	Total    int             `json:"total"`
+	Offset   int             `json:"offset"`
+	Limit    int             `json:"limit"`
+	Contacts []model.Contact `json:"contacts"`
+}
+
+// ChatsResponse is a page of chats, total is a count of chats matching search.
+type ChatsResponse struct {
+	Total  int          `json:"total"`
+	Offset int          `json:"offset"`
+	Limit  int          `json:"limit"`
+	Chats  []model.Chat `json:"chats"`
+}
+
+// pageParams parses `offset` and `limit` query params.
+func pageParams(r *http.Request) (offset, limit int, appErr *AppError) {
+	query := r.URL.Query()
+	offset, limit = 0, defaultPageLimit
+	var err error
+	if value := query.Get("offset"); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+			return 0, 0, &AppError{
+				Error:       errors.Errorf("invalid offset `%s`", value),
+				ResponseMsg: "offset must be a non-negative integer",
+				Code:        http.StatusBadRequest,
+			}
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxPageLimit {
+			return 0, 0, &AppError{
+				Error:       errors.Errorf("invalid limit `%s`", value),
+				ResponseMsg: "limit must be an integer from 1 to " + strconv.Itoa(maxPageLimit),
+				Code:        http.StatusBadRequest,
+			}
+		}
+	}
+	return offset, limit, nil
+}
+
+func pageBounds(total, offset, limit int) (from, to int) {
+	if offset > total {
+		return total, total
+	}
+	if offset+limit > total {
+		return offset, total
+	}
+	return offset, offset + limit
+}
+
+func writePageJSON(w http.ResponseWriter, page interface{}) *AppError {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "page encoding error"),
+			ResponseMsg: "can't encode response",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
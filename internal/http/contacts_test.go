@@ -0,0 +1,122 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/model"
+	testHttp "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/golang/mock/gomock"
+)
+
+func TestContactsHandler_ServeHTTP(t *testing.T) {
+	contacts := []model.Contact{
+		{Jid: "375000000001@s.whatsapp.net", Name: "Alice"},
+		{Jid: "375000000002@s.whatsapp.net", Name: "Bob"},
+		{Jid: "375000000003@s.whatsapp.net", PushName: "Bobby"},
+	}
+	tests := []struct {
+		name         string
+		query        map[string]interface{}
+		readErr      error
+		expectRead   bool
+		expectStatus int
+		expectTotal  int
+		expectJids   []interface{}
+	}{
+		{
+			name:         "OK",
+			expectRead:   true,
+			expectStatus: http.StatusOK,
+			expectTotal:  3,
+			expectJids:   []interface{}{"375000000001@s.whatsapp.net", "375000000002@s.whatsapp.net", "375000000003@s.whatsapp.net"},
+		},
+		{
+			name:         "Search and paging",
+			query:        map[string]interface{}{"search": "BOB", "offset": 1, "limit": 1},
+			expectRead:   true,
+			expectStatus: http.StatusOK,
+			expectTotal:  2,
+			expectJids:   []interface{}{"375000000003@s.whatsapp.net"},
+		},
+		{
+			name:         "Offset out of range",
+			query:        map[string]interface{}{"offset": 10},
+			expectRead:   true,
+			expectStatus: http.StatusOK,
+			expectTotal:  3,
+			expectJids:   []interface{}{},
+		},
+		{
+			name:         "Invalid limit",
+			query:        map[string]interface{}{"limit": 1000},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Invalid offset",
+			query:        map[string]interface{}{"offset": "first"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Reading error",
+			readErr:      errors.New("connection refused"),
+			expectRead:   true,
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			contactRepo := mock.NewMockContact(mockCtrl)
+			if tt.expectRead {
+				contactRepo.EXPECT().Contacts("_sid_").Return(contacts, tt.readErr)
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/contacts/{sessionID}/": internalHttp.NewContactsHandler(contactRepo),
+			})
+			defer server.Close()
+			expect := httpexpect.New(t, server.URL)
+
+			request := expect.GET("/contacts/_sid_/")
+			for name, value := range tt.query {
+				request = request.WithQuery(name, value)
+			}
+			response := request.Expect().Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusOK {
+				page := response.JSON().Object()
+				page.ValueEqual("total", tt.expectTotal)
+				page.Value("contacts").Array().Path("$..id").Array().Equal(tt.expectJids)
+			}
+		})
+	}
+}
+
+func TestChatsHandler_ServeHTTP(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	contactRepo := mock.NewMockContact(mockCtrl)
+	contactRepo.EXPECT().Chats("_sid_").Return([]model.Chat{
+		{Jid: "375000000001-1589212345@g.us", Name: "Family", IsGroup: true},
+		{Jid: "375000000002@s.whatsapp.net", Name: "Bob"},
+	}, nil)
+
+	server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+		"/chats/{sessionID}/": internalHttp.NewChatsHandler(contactRepo),
+	})
+	defer server.Close()
+	expect := httpexpect.New(t, server.URL)
+
+	page := expect.GET("/chats/_sid_/").WithQuery("search", "family").Expect().Status(http.StatusOK).JSON().Object()
+	page.ValueEqual("total", 1)
+	page.ValueEqual("limit", 50)
+	chat := page.Value("chats").Array().Element(0).Object()
+	chat.ValueEqual("id", "375000000001-1589212345@g.us")
+	chat.ValueEqual("is_group", true)
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// DeleteMessageHandler is responsible for deleting messages of chat.
+type DeleteMessageHandler struct {
+	resolver *chatResolver
+}
+
+// NewDeleteMessageHandler creates DeleteMessageHandler.
+func NewDeleteMessageHandler(
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+) *DeleteMessageHandler {
+	return &DeleteMessageHandler{resolver: &chatResolver{
+		connectionsSupervisor: connectionsSupervisor,
+		jidNormalizer:         jidNormalizer,
+		handlerName:           "delete message",
+	}}
+}
+
+// Handle revokes message for everyone in chat, only messages sent by session account can be revoked.
+// Deleting message for session account only isn't supported by WhatsApp Web connection,
+// so `for_everyone` query param must be true.
+func (handler *DeleteMessageHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	if forEveryone, err := strconv.ParseBool(r.URL.Query().Get("for_everyone")); err != nil || !forEveryone {
+		return &AppError{
+			Error:       errors.New("deleting message not for everyone requested in delete message handler"),
+			ResponseMsg: "only deleting for everyone is supported, for_everyone must be true",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	vars := mux.Vars(r)
+	chatJid, appErr := handler.resolver.chatJid(vars["chatID"])
+	if appErr != nil {
+		return appErr
+	}
+	wac, appErr := handler.resolver.connection(vars["sessionID"])
+	if appErr != nil {
+		return appErr
+	}
+	if err := wac.Revoke(chatJid, vars["messageID"]); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "revoking message error in delete message handler"),
+			ResponseMsg: "revoking message error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
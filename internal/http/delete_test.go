@@ -0,0 +1,79 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	testHttp "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/golang/mock/gomock"
+)
+
+func TestDeleteMessageHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		forEveryone  interface{}
+		expectRevoke bool
+		revokeErr    error
+		expectStatus int
+	}{
+		{
+			name:         "Revoke",
+			forEveryone:  true,
+			expectRevoke: true,
+			expectStatus: http.StatusNoContent,
+		},
+		{
+			name:         "Revoking error",
+			forEveryone:  true,
+			expectRevoke: true,
+			revokeErr:    errors.New("connection closed"),
+			expectStatus: http.StatusInternalServerError,
+		},
+		{
+			name:         "Deleting not for everyone",
+			forEveryone:  false,
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "No for_everyone",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Invalid for_everyone",
+			forEveryone:  "maybe",
+			expectStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			wac := mock.NewMockConn(c)
+			connections := mock.NewMockConnections(c)
+			if tt.expectRevoke {
+				wac.EXPECT().Revoke("375000000001@s.whatsapp.net", "MSG_ID").Return(tt.revokeErr)
+				connections.EXPECT().
+					AuthenticatedConnectionForSession("_sid_").
+					Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/messages/{sessionID}/{chatID}/{messageID}/": internalHttp.NewDeleteMessageHandler(connections, service.NewJidNormalizer("")),
+			})
+			defer server.Close()
+			request := httpexpect.New(t, server.URL).DELETE("/messages/_sid_/375000000001/MSG_ID/")
+			if tt.forEveryone != nil {
+				request = request.WithQuery("for_everyone", tt.forEveryone)
+			}
+			request.Expect().Status(tt.expectStatus)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/http"
+
+	httpInfra "github.com/r-erema/wapi/internal/infrastructure/http"
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/repository"
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/Rhymen/go-whatsapp"
+)
+
+// SendDocumentHandler is responsible for sending documents.
+type SendDocumentHandler struct {
+	auth   service.Authorizer
+	sender *mediaSender
+}
+
+// NewDocumentHandler creates SendDocumentHandler.
+func NewDocumentHandler(
+	authorizer service.Authorizer,
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+	msgRepo repository.Message,
+	client httpInfra.Client,
+	marshal *jsonInfra.MarshallCallback,
+	maxUploadSize int64,
+) *SendDocumentHandler {
+	return &SendDocumentHandler{
+		auth:   authorizer,
+		sender: newMediaSender(connectionsSupervisor, jidNormalizer, msgRepo, client, marshal, "document", maxUploadSize),
+	}
+}
+
+// Handle sends message with client`s document to WhatsApp server.
+func (h *SendDocumentHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	var msgReq SendDocumentRequest
+	upload, appErr := h.sender.decodeRequest(w, r, &msgReq)
+	if appErr != nil {
+		return appErr
+	}
+
+	source, appErr := h.sender.source(upload, msgReq.DocumentBase64, msgReq.DocumentURL)
+	if appErr != nil {
+		return appErr
+	}
+
+	return h.sender.send(w, msgReq.SessionID, msgReq.ChatID, source, func(info whatsapp.MessageInfo, content *mediaContent) interface{} {
+		fileName := msgReq.FileName
+		if fileName == "" {
+			fileName = content.fileName
+		}
+		return whatsapp.DocumentMessage{
+			Info:     info,
+			Type:     detectMimeType(content.head, fileName, msgReq.MimeType),
+			FileName: fileName,
+			Title:    fileName,
+			Content:  content,
+		}
+	})
+}
+
+// SendDocumentRequest is the request for sending document to WhatsApp.
+type SendDocumentRequest struct {
+	SessionID      string `json:"session_name"`
+	ChatID         string `json:"chat_id"`
+	DocumentURL    string `json:"document_url"`
+	DocumentBase64 string `json:"document_base64"`
+	FileName       string `json:"file_name"`
+	MimeType       string `json:"mime_type"`
+}
@@ -0,0 +1,237 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	httpInfra "github.com/r-erema/wapi/internal/infrastructure/http"
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+	"github.com/r-erema/wapi/internal/service"
+	httpTest "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/gavv/httpexpect"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocumentHandler(t *testing.T) {
+	handler := internalHttp.NewDocumentHandler(mocks(t))
+	assert.NotNil(t, handler)
+}
+
+func TestSendDocumentHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		mocksFactory imagesMocksFactory
+		jsonRequest  func() interface{}
+		expectStatus int
+	}{
+		{
+			name: "OK",
+			mocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+				authorizer, _, jidNormalizer, msgRepo, httpClient, marshal, maxSize := mocks(t)
+				c := gomock.NewController(t)
+				wac := mock.NewMockConn(c)
+				wac.EXPECT().Info().Return(&whatsapp.Info{Wid: "wid"})
+				wac.EXPECT().Send(gomock.Any()).DoAndReturn(func(msg interface{}) (string, error) {
+					document := msg.(whatsapp.DocumentMessage)
+					assert.Equal(t, "invoice.pdf", document.FileName)
+					assert.Equal(t, "application/pdf", document.Type)
+					return "MSG_ID", nil
+				})
+
+				connections := mock.NewMockConnections(c)
+				connections.EXPECT().
+					AuthenticatedConnectionForSession(gomock.Any()).
+					Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+				return authorizer, connections, jidNormalizer, msgRepo, httpClient, marshal, maxSize
+			},
+			jsonRequest:  documentRequest,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name: "Bad document request",
+			mocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+				return mocks(t)
+			},
+			jsonRequest: func() interface{} {
+				return ""
+			},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Connection not found",
+			mocksFactory: connectionNotFound().imagesMocksFactory,
+			jsonRequest:  documentRequest,
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Bad document url",
+			mocksFactory: badImageURL().imagesMocksFactory,
+			jsonRequest:  documentRequest,
+			expectStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "Error document sending",
+			mocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+				authorizer, _, jidNormalizer, msgRepo, httpClient, marshal, maxSize := mocks(t)
+				c := gomock.NewController(t)
+				wac := mock.NewMockConn(c)
+				wac.EXPECT().Info().Return(&whatsapp.Info{Wid: "wid"})
+				wac.EXPECT().Send(gomock.Any()).Return("", errors.New("error document sending"))
+
+				connections := mock.NewMockConnections(c)
+				connections.EXPECT().
+					AuthenticatedConnectionForSession(gomock.Any()).
+					Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+				return authorizer, connections, jidNormalizer, msgRepo, httpClient, marshal, maxSize
+			},
+			jsonRequest:  documentRequest,
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			handler := internalHttp.NewDocumentHandler(tt.mocksFactory(t))
+			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{"/send-document/": handler})
+			defer server.Close()
+
+			expect := httpexpect.New(t, server.URL)
+			expect.POST("/send-document/").
+				WithJSON(tt.jsonRequest()).
+				Expect().
+				Status(tt.expectStatus)
+		})
+	}
+}
+
+func documentRequest() interface{} {
+	return &internalHttp.SendDocumentRequest{
+		SessionID:   "_sid_",
+		ChatID:      "+000000000000",
+		DocumentURL: "https://host/files/invoice.pdf?token=secret",
+	}
+}
+
+func TestSendDocumentHandler_Upload(t *testing.T) {
+	authorizer, _, jidNormalizer, _, httpClient, marshal, maxSize := mocks(t)
+	c := gomock.NewController(t)
+	var savedStatus *model.MessageStatus
+	msgRepo := mock.NewMockMessage(c)
+	msgRepo.EXPECT().
+		UpdateMessageStatus("_sid_", "MSG_ID", gomock.Any()).
+		DoAndReturn(func(_, _ string, update func(*model.MessageStatus) *model.MessageStatus) (*model.MessageStatus, error) {
+			savedStatus = update(nil)
+			return savedStatus, nil
+		})
+	wac := mock.NewMockConn(c)
+	wac.EXPECT().Info().Return(&whatsapp.Info{Wid: "wid"})
+	wac.EXPECT().Send(gomock.Any()).DoAndReturn(func(msg interface{}) (string, error) {
+		document := msg.(whatsapp.DocumentMessage)
+		assert.Equal(t, "report.pdf", document.FileName)
+		assert.Equal(t, "application/pdf", document.Type)
+		content, err := ioutil.ReadAll(document.Content)
+		require.Nil(t, err)
+		assert.Equal(t, "%PDF-1.4 report", string(content))
+		return "MSG_ID", nil
+	})
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession("_sid_").
+		Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+
+	handler := internalHttp.NewDocumentHandler(authorizer, connections, jidNormalizer, msgRepo, httpClient, marshal, maxSize)
+	server := httpTest.New(map[string]internalHttp.AppHTTPHandler{"/send-document/": handler})
+	defer server.Close()
+
+	expect := httpexpect.New(t, server.URL)
+	expect.POST("/send-document/").
+		WithMultipart().
+		WithFormField("session_name", "_sid_").
+		WithFormField("chat_id", "+000000000000").
+		WithFile("file", "report.pdf", strings.NewReader("%PDF-1.4 report")).
+		Expect().
+		Status(http.StatusOK)
+
+	require.NotNil(t, savedStatus)
+	assert.Equal(t, model.SentDeliveryStatus, savedStatus.Status)
+	assert.Equal(t, "000000000000@s.whatsapp.net", savedStatus.ChatID)
+}
+
+func TestSendDocumentHandler_ChunkedTooLarge(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        func(t *testing.T) string
+	}{
+		{
+			name:        "JSON request",
+			contentType: "application/json",
+			body: func(t *testing.T) string {
+				body, err := json.Marshal(&internalHttp.SendDocumentRequest{
+					SessionID:      "_sid_",
+					ChatID:         "+000000000000",
+					DocumentBase64: base64.StdEncoding.EncodeToString(make([]byte, 100*1024)),
+				})
+				require.Nil(t, err)
+				return string(body)
+			},
+		},
+		{
+			name:        "Large fields before upload",
+			contentType: "multipart/form-data; boundary=_boundary_",
+			body:        func(t *testing.T) string { return uploadBody(t, 65*1024) },
+		},
+		{
+			name:        "Upload exceeding request size",
+			contentType: "multipart/form-data; boundary=_boundary_",
+			body:        func(t *testing.T) string { return uploadBody(t, 64*1024) },
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{"/send-document/": internalHttp.NewDocumentHandler(mocks(t))})
+			defer server.Close()
+
+			// Body of unknown length is sent chunked without Content-Length header.
+			body := ioutil.NopCloser(strings.NewReader(tt.body(t)))
+			response, err := http.Post(server.URL+"/send-document/", tt.contentType, body)
+			require.Nil(t, err)
+			defer response.Body.Close()
+			assert.Equal(t, http.StatusRequestEntityTooLarge, response.StatusCode)
+		})
+	}
+}
+
+// uploadBody builds multipart body of document upload with file name of given length, the file is of max upload size.
+func uploadBody(t *testing.T, fileNameLen int) string {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	require.Nil(t, form.SetBoundary("_boundary_"))
+	require.Nil(t, form.WriteField("session_name", "_sid_"))
+	require.Nil(t, form.WriteField("chat_id", "+000000000000"))
+	require.Nil(t, form.WriteField("file_name", strings.Repeat("a", fileNameLen)))
+	file, err := form.CreateFormFile("file", "report.pdf")
+	require.Nil(t, err)
+	_, err = file.Write(make([]byte, testMaxUploadSize))
+	require.Nil(t, err)
+	require.Nil(t, form.Close())
+	return body.String()
+}
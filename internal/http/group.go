@@ -0,0 +1,424 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// Actions changing participants of group.
+const (
+	AddParticipantsAction     = "add"
+	RemoveParticipantsAction  = "remove"
+	PromoteParticipantsAction = "promote"
+	DemoteParticipantsAction  = "demote"
+)
+
+// groupManager is responsible for common steps of group handlers:
+// resolving connection of session, group and participants JIDs and converting errors of WhatsApp server.
+type groupManager struct {
+	connectionsSupervisor service.Connections
+	jidNormalizer         *service.JidNormalizer
+}
+
+func (m *groupManager) connection(r *http.Request) (whatsapp.Conn, *AppError) {
+	sessConnDTO, err := m.connectionsSupervisor.AuthenticatedConnectionForSession(mux.Vars(r)["sessionID"])
+	if err != nil {
+		return nil, &AppError{
+			Error:       errors.Wrap(err, "can't find session in group handler"),
+			ResponseMsg: "session not registered",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	return sessConnDTO.Wac(), nil
+}
+
+// groupJid resolves JID of group from request path, group suffix may be omitted.
+func (m *groupManager) groupJid(r *http.Request) (string, *AppError) {
+	groupID := mux.Vars(r)["groupID"]
+	if !strings.Contains(groupID, "@") {
+		groupID += service.GroupJidSuffix
+	}
+	jid, err := m.jidNormalizer.Normalize(groupID)
+	if err == nil && !strings.HasSuffix(jid, service.GroupJidSuffix) {
+		err = errors.Errorf("JID `%s` isn't a group JID", jid)
+	}
+	if err != nil {
+		return "", &AppError{
+			Error:       errors.Wrap(err, "invalid group id in group handler"),
+			ResponseMsg: "invalid group id: " + err.Error(),
+			Code:        http.StatusBadRequest,
+		}
+	}
+	return jid, nil
+}
+
+// participantJids converts participants given as JIDs or phone numbers to user JIDs.
+func (m *groupManager) participantJids(participants []string) ([]string, *AppError) {
+	if len(participants) == 0 {
+		return nil, &AppError{
+			Error:       errors.New("no participants in group handler"),
+			ResponseMsg: "participants are required",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	jids := make([]string, 0, len(participants))
+	for _, participant := range participants {
+		jid, err := m.jidNormalizer.Normalize(participant)
+		if err == nil && !strings.HasSuffix(jid, service.UserJidSuffix) {
+			err = errors.Errorf("JID `%s` isn't a user JID", jid)
+		}
+		if err != nil {
+			return nil, &AppError{
+				Error:       errors.Wrap(err, "invalid participant in group handler"),
+				ResponseMsg: "invalid participant: " + err.Error(),
+				Code:        http.StatusBadRequest,
+			}
+		}
+		jids = append(jids, jid)
+	}
+	return jids, nil
+}
+
+// groupError converts error of group operation to response error.
+func groupError(err error, operation string) *AppError {
+	appErr := &AppError{
+		Error:       errors.Wrapf(err, "%s error in group handler", operation),
+		ResponseMsg: operation + " error",
+		Code:        http.StatusInternalServerError,
+	}
+	if statusErr, ok := err.(*whatsapp.StatusError); ok {
+		switch statusErr.Status {
+		case http.StatusNotFound:
+			appErr.ResponseMsg = "group not found"
+			appErr.Code = http.StatusNotFound
+		case http.StatusUnauthorized, http.StatusForbidden:
+			appErr.ResponseMsg = operation + " isn't permitted"
+			appErr.Code = http.StatusForbidden
+		}
+	}
+	return appErr
+}
+
+func decodeGroupRequest(r *http.Request, groupReq interface{}) *AppError {
+	if err := json.NewDecoder(r.Body).Decode(groupReq); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "decoding error in group handler"),
+			ResponseMsg: "can't decode request",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	return nil
+}
+
+func writeGroupJSON(w http.ResponseWriter, status int, body interface{}) *AppError {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "group encoding error in group handler"),
+			ResponseMsg: "can't encode group",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
+
+// CreateGroupHandler is responsible for creating groups.
+type CreateGroupHandler struct {
+	groupManager
+}
+
+// NewCreateGroupHandler creates CreateGroupHandler.
+func NewCreateGroupHandler(connectionsSupervisor service.Connections, jidNormalizer *service.JidNormalizer) *CreateGroupHandler {
+	return &CreateGroupHandler{groupManager{connectionsSupervisor: connectionsSupervisor, jidNormalizer: jidNormalizer}}
+}
+
+// Handle creates group and writes its JID and statuses of added participants to response.
+func (handler *CreateGroupHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	var groupReq CreateGroupRequest
+	if appErr := decodeGroupRequest(r, &groupReq); appErr != nil {
+		return appErr
+	}
+	if strings.TrimSpace(groupReq.Subject) == "" {
+		return &AppError{
+			Error:       errors.New("group without subject in group handler"),
+			ResponseMsg: "subject is required",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	participants, appErr := handler.participantJids(groupReq.Participants)
+	if appErr != nil {
+		return appErr
+	}
+	wac, appErr := handler.connection(r)
+	if appErr != nil {
+		return appErr
+	}
+
+	jid, statuses, err := wac.CreateGroup(groupReq.Subject, participants)
+	if err != nil {
+		return groupError(err, "group creation")
+	}
+	log.Printf("group %s created by session %s\n", jid, mux.Vars(r)["sessionID"])
+	return writeGroupJSON(w, http.StatusCreated, &GroupParticipantsResponse{Jid: jid, Participants: statuses})
+}
+
+// GroupHandler provides information about group and its participants.
+type GroupHandler struct {
+	groupManager
+}
+
+// NewGroupHandler creates GroupHandler.
+func NewGroupHandler(connectionsSupervisor service.Connections, jidNormalizer *service.JidNormalizer) *GroupHandler {
+	return &GroupHandler{groupManager{connectionsSupervisor: connectionsSupervisor, jidNormalizer: jidNormalizer}}
+}
+
+// Handle sends group metadata.
+func (handler *GroupHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	jid, appErr := handler.groupJid(r)
+	if appErr != nil {
+		return appErr
+	}
+	wac, appErr := handler.connection(r)
+	if appErr != nil {
+		return appErr
+	}
+
+	metadata, err := wac.GroupMetadata(jid)
+	if err != nil {
+		return groupError(err, "group metadata reading")
+	}
+	return writeGroupJSON(w, http.StatusOK, metadata)
+}
+
+// GroupParticipantsHandler is responsible for adding, removing, promoting and demoting participants of group.
+type GroupParticipantsHandler struct {
+	groupManager
+}
+
+// NewGroupParticipantsHandler creates GroupParticipantsHandler.
+func NewGroupParticipantsHandler(
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+) *GroupParticipantsHandler {
+	return &GroupParticipantsHandler{groupManager{connectionsSupervisor: connectionsSupervisor, jidNormalizer: jidNormalizer}}
+}
+
+// Handle performs action of request path with participants and writes their statuses to response.
+func (handler *GroupParticipantsHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	jid, appErr := handler.groupJid(r)
+	if appErr != nil {
+		return appErr
+	}
+	var groupReq GroupParticipantsRequest
+	if appErr = decodeGroupRequest(r, &groupReq); appErr != nil {
+		return appErr
+	}
+	participants, appErr := handler.participantJids(groupReq.Participants)
+	if appErr != nil {
+		return appErr
+	}
+	wac, appErr := handler.connection(r)
+	if appErr != nil {
+		return appErr
+	}
+
+	var change func(jid string, participants []string) ([]whatsapp.GroupParticipantStatus, error)
+	switch action := mux.Vars(r)["action"]; action {
+	case AddParticipantsAction:
+		change = wac.AddGroupParticipants
+	case RemoveParticipantsAction:
+		change = wac.RemoveGroupParticipants
+	case PromoteParticipantsAction:
+		change = wac.PromoteGroupParticipants
+	case DemoteParticipantsAction:
+		change = wac.DemoteGroupParticipants
+	default:
+		return &AppError{
+			Error:       errors.Errorf("unknown participants action `%s` in group handler", action),
+			ResponseMsg: "unknown participants action",
+			Code:        http.StatusNotFound,
+		}
+	}
+
+	statuses, err := change(jid, participants)
+	if err != nil {
+		return groupError(err, "participants change")
+	}
+	return writeGroupJSON(w, http.StatusOK, &GroupParticipantsResponse{Jid: jid, Participants: statuses})
+}
+
+// GroupSubjectHandler is responsible for changing subject of group.
+type GroupSubjectHandler struct {
+	groupManager
+}
+
+// NewGroupSubjectHandler creates GroupSubjectHandler.
+func NewGroupSubjectHandler(connectionsSupervisor service.Connections, jidNormalizer *service.JidNormalizer) *GroupSubjectHandler {
+	return &GroupSubjectHandler{groupManager{connectionsSupervisor: connectionsSupervisor, jidNormalizer: jidNormalizer}}
+}
+
+// Handle changes subject of group.
+func (handler *GroupSubjectHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	jid, appErr := handler.groupJid(r)
+	if appErr != nil {
+		return appErr
+	}
+	var groupReq GroupSubjectRequest
+	if appErr = decodeGroupRequest(r, &groupReq); appErr != nil {
+		return appErr
+	}
+	if strings.TrimSpace(groupReq.Subject) == "" {
+		return &AppError{
+			Error:       errors.New("empty subject in group handler"),
+			ResponseMsg: "subject is required",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	wac, appErr := handler.connection(r)
+	if appErr != nil {
+		return appErr
+	}
+
+	if err := wac.SetGroupSubject(jid, groupReq.Subject); err != nil {
+		return groupError(err, "subject change")
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// GroupDescriptionHandler is responsible for changing description of group.
+type GroupDescriptionHandler struct {
+	groupManager
+}
+
+// NewGroupDescriptionHandler creates GroupDescriptionHandler.
+func NewGroupDescriptionHandler(
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+) *GroupDescriptionHandler {
+	return &GroupDescriptionHandler{groupManager{connectionsSupervisor: connectionsSupervisor, jidNormalizer: jidNormalizer}}
+}
+
+// Handle changes description of group.
+func (handler *GroupDescriptionHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	jid, appErr := handler.groupJid(r)
+	if appErr != nil {
+		return appErr
+	}
+	var groupReq GroupDescriptionRequest
+	if appErr = decodeGroupRequest(r, &groupReq); appErr != nil {
+		return appErr
+	}
+	wac, appErr := handler.connection(r)
+	if appErr != nil {
+		return appErr
+	}
+
+	if err := wac.SetGroupDescription(jid, groupReq.Description); err != nil {
+		return groupError(err, "description change")
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// LeaveGroupHandler is responsible for leaving groups.
+type LeaveGroupHandler struct {
+	groupManager
+}
+
+// NewLeaveGroupHandler creates LeaveGroupHandler.
+func NewLeaveGroupHandler(connectionsSupervisor service.Connections, jidNormalizer *service.JidNormalizer) *LeaveGroupHandler {
+	return &LeaveGroupHandler{groupManager{connectionsSupervisor: connectionsSupervisor, jidNormalizer: jidNormalizer}}
+}
+
+// Handle leaves group.
+func (handler *LeaveGroupHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	jid, appErr := handler.groupJid(r)
+	if appErr != nil {
+		return appErr
+	}
+	wac, appErr := handler.connection(r)
+	if appErr != nil {
+		return appErr
+	}
+
+	if err := wac.LeaveGroup(jid); err != nil {
+		return groupError(err, "group leaving")
+	}
+	log.Printf("group %s left by session %s\n", jid, mux.Vars(r)["sessionID"])
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// GroupInviteLinkHandler provides link inviting to group.
+type GroupInviteLinkHandler struct {
+	groupManager
+}
+
+// NewGroupInviteLinkHandler creates GroupInviteLinkHandler.
+func NewGroupInviteLinkHandler(
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+) *GroupInviteLinkHandler {
+	return &GroupInviteLinkHandler{groupManager{connectionsSupervisor: connectionsSupervisor, jidNormalizer: jidNormalizer}}
+}
+
+// Handle sends invite link of group.
+func (handler *GroupInviteLinkHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	jid, appErr := handler.groupJid(r)
+	if appErr != nil {
+		return appErr
+	}
+	wac, appErr := handler.connection(r)
+	if appErr != nil {
+		return appErr
+	}
+
+	link, err := wac.GroupInviteLink(jid)
+	if err != nil {
+		return groupError(err, "invite link reading")
+	}
+	return writeGroupJSON(w, http.StatusOK, &GroupInviteLinkResponse{Jid: jid, InviteLink: link})
+}
+
+// CreateGroupRequest is the request for creating group, participants are JIDs or phone numbers.
+type CreateGroupRequest struct {
+	Subject      string   `json:"subject"`
+	Participants []string `json:"participants"`
+}
+
+// GroupParticipantsRequest is the request for changing participants of group.
+type GroupParticipantsRequest struct {
+	Participants []string `json:"participants"`
+}
+
+// GroupSubjectRequest is the request for changing subject of group.
+type GroupSubjectRequest struct {
+	Subject string `json:"subject"`
+}
+
+// GroupDescriptionRequest is the request for changing description of group.
+type GroupDescriptionRequest struct {
+	Description string `json:"description"`
+}
+
+// GroupParticipantsResponse contains JID of group and statuses of its changed participants.
+type GroupParticipantsResponse struct {
+	Jid          string                            `json:"id"`
+	Participants []whatsapp.GroupParticipantStatus `json:"participants"`
+}
+
+// GroupInviteLinkResponse contains link inviting to group.
+type GroupInviteLinkResponse struct {
+	Jid        string `json:"id"`
+	InviteLink string `json:"invite_link"`
+}
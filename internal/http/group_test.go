@@ -0,0 +1,369 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	testHttp "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const groupJid = "375447034810-1589212345@g.us"
+
+func groupConnections(c *gomock.Controller, wac *mock.MockConn) *mock.MockConnections {
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession("_sid_").
+		Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil).
+		AnyTimes()
+	return connections
+}
+
+func TestNewGroupHandlers(t *testing.T) {
+	c := gomock.NewController(t)
+	connections, jidNormalizer := mock.NewMockConnections(c), service.NewJidNormalizer("")
+	assert.NotNil(t, internalHttp.NewCreateGroupHandler(connections, jidNormalizer))
+	assert.NotNil(t, internalHttp.NewGroupHandler(connections, jidNormalizer))
+	assert.NotNil(t, internalHttp.NewGroupParticipantsHandler(connections, jidNormalizer))
+	assert.NotNil(t, internalHttp.NewGroupSubjectHandler(connections, jidNormalizer))
+	assert.NotNil(t, internalHttp.NewGroupDescriptionHandler(connections, jidNormalizer))
+	assert.NotNil(t, internalHttp.NewLeaveGroupHandler(connections, jidNormalizer))
+	assert.NotNil(t, internalHttp.NewGroupInviteLinkHandler(connections, jidNormalizer))
+}
+
+func TestCreateGroupHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      *internalHttp.CreateGroupRequest
+		createErr    error
+		expectCreate bool
+		expectStatus int
+	}{
+		{
+			name:         "OK",
+			request:      &internalHttp.CreateGroupRequest{Subject: "Support", Participants: []string{"+375 44 703-48-11"}},
+			expectCreate: true,
+			expectStatus: http.StatusCreated,
+		},
+		{
+			name:         "Without subject",
+			request:      &internalHttp.CreateGroupRequest{Participants: []string{"+375447034811"}},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Without participants",
+			request:      &internalHttp.CreateGroupRequest{Subject: "Support"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Group as participant",
+			request:      &internalHttp.CreateGroupRequest{Subject: "Support", Participants: []string{groupJid}},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Creation error",
+			request:      &internalHttp.CreateGroupRequest{Subject: "Support", Participants: []string{"+375447034811"}},
+			createErr:    errors.New("create group query timed out"),
+			expectCreate: true,
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			wac := mock.NewMockConn(c)
+			if tt.expectCreate {
+				wac.EXPECT().
+					CreateGroup("Support", []string{"375447034811@s.whatsapp.net"}).
+					Return(groupJid, []whatsapp.GroupParticipantStatus{{Jid: "375447034811@s.whatsapp.net", Code: 200}}, tt.createErr)
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/groups/{sessionID}/": internalHttp.NewCreateGroupHandler(groupConnections(c, wac), service.NewJidNormalizer("")),
+			})
+			defer server.Close()
+
+			response := httpexpect.New(t, server.URL).POST("/groups/_sid_/").
+				WithJSON(tt.request).
+				Expect().
+				Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusCreated {
+				response.JSON().Object().
+					ValueEqual("id", groupJid).
+					Value("participants").Array().Element(0).Object().ValueEqual("code", 200)
+			}
+		})
+	}
+}
+
+func TestGroupHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		groupID      string
+		metadataErr  error
+		expectStatus int
+	}{
+		{name: "OK", groupID: groupJid, expectStatus: http.StatusOK},
+		{name: "Group id without suffix", groupID: "375447034810-1589212345", expectStatus: http.StatusOK},
+		{name: "User JID", groupID: "375447034810@s.whatsapp.net", expectStatus: http.StatusBadRequest},
+		{
+			name:         "Group not found",
+			groupID:      groupJid,
+			metadataErr:  &whatsapp.StatusError{Query: "group metadata", Status: http.StatusNotFound},
+			expectStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			wac := mock.NewMockConn(c)
+			if tt.expectStatus != http.StatusBadRequest {
+				metadata := &whatsapp.GroupMetadata{Jid: groupJid, Subject: "Support"}
+				wac.EXPECT().GroupMetadata(groupJid).Return(metadata, tt.metadataErr)
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/groups/{sessionID}/{groupID}/": internalHttp.NewGroupHandler(groupConnections(c, wac), service.NewJidNormalizer("")),
+			})
+			defer server.Close()
+
+			response := httpexpect.New(t, server.URL).GET("/groups/_sid_/" + tt.groupID + "/").
+				Expect().
+				Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusOK {
+				response.JSON().Object().ValueEqual("subject", "Support")
+			}
+		})
+	}
+}
+
+func TestGroupParticipantsHandler_ServeHTTP(t *testing.T) {
+	participants := []string{"375447034811@s.whatsapp.net"}
+	statuses := []whatsapp.GroupParticipantStatus{{Jid: "375447034811@s.whatsapp.net", Code: 200}}
+	tests := []struct {
+		name         string
+		action       string
+		expect       func(wac *mock.MockConn)
+		expectStatus int
+	}{
+		{
+			name:   "Add",
+			action: internalHttp.AddParticipantsAction,
+			expect: func(wac *mock.MockConn) {
+				wac.EXPECT().AddGroupParticipants(groupJid, participants).Return(statuses, nil)
+			},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:   "Remove",
+			action: internalHttp.RemoveParticipantsAction,
+			expect: func(wac *mock.MockConn) {
+				wac.EXPECT().RemoveGroupParticipants(groupJid, participants).Return(statuses, nil)
+			},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:   "Promote",
+			action: internalHttp.PromoteParticipantsAction,
+			expect: func(wac *mock.MockConn) {
+				wac.EXPECT().PromoteGroupParticipants(groupJid, participants).Return(statuses, nil)
+			},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:   "Demote is not permitted",
+			action: internalHttp.DemoteParticipantsAction,
+			expect: func(wac *mock.MockConn) {
+				wac.EXPECT().
+					DemoteGroupParticipants(groupJid, participants).
+					Return(nil, &whatsapp.StatusError{Query: "group participants", Status: http.StatusUnauthorized})
+			},
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			name:         "Unknown action",
+			action:       "ban",
+			expect:       func(wac *mock.MockConn) {},
+			expectStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			wac := mock.NewMockConn(c)
+			tt.expect(wac)
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/groups/{sessionID}/{groupID}/participants/{action}/": internalHttp.NewGroupParticipantsHandler(
+					groupConnections(c, wac),
+					service.NewJidNormalizer(""),
+				),
+			})
+			defer server.Close()
+
+			response := httpexpect.New(t, server.URL).POST("/groups/_sid_/" + groupJid + "/participants/" + tt.action + "/").
+				WithJSON(&internalHttp.GroupParticipantsRequest{Participants: []string{"+375447034811"}}).
+				Expect().
+				Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusOK {
+				response.JSON().Object().Value("participants").Array().Length().Equal(1)
+			}
+		})
+	}
+}
+
+func TestGroupSettingsHandlers_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		request      interface{}
+		handler      func(connections service.Connections, jidNormalizer *service.JidNormalizer) internalHttp.AppHTTPHandler
+		expect       func(wac *mock.MockConn)
+		expectStatus int
+	}{
+		{
+			name:    "Subject",
+			method:  http.MethodPut,
+			path:    "subject/",
+			request: &internalHttp.GroupSubjectRequest{Subject: "VIP support"},
+			handler: func(connections service.Connections, jidNormalizer *service.JidNormalizer) internalHttp.AppHTTPHandler {
+				return internalHttp.NewGroupSubjectHandler(connections, jidNormalizer)
+			},
+			expect: func(wac *mock.MockConn) {
+				wac.EXPECT().SetGroupSubject(groupJid, "VIP support")
+			},
+			expectStatus: http.StatusNoContent,
+		},
+		{
+			name:    "Empty subject",
+			method:  http.MethodPut,
+			path:    "subject/",
+			request: &internalHttp.GroupSubjectRequest{},
+			handler: func(connections service.Connections, jidNormalizer *service.JidNormalizer) internalHttp.AppHTTPHandler {
+				return internalHttp.NewGroupSubjectHandler(connections, jidNormalizer)
+			},
+			expect:       func(wac *mock.MockConn) {},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "Description",
+			method:  http.MethodPut,
+			path:    "description/",
+			request: &internalHttp.GroupDescriptionRequest{Description: "Support of customer"},
+			handler: func(connections service.Connections, jidNormalizer *service.JidNormalizer) internalHttp.AppHTTPHandler {
+				return internalHttp.NewGroupDescriptionHandler(connections, jidNormalizer)
+			},
+			expect: func(wac *mock.MockConn) {
+				wac.EXPECT().SetGroupDescription(groupJid, "Support of customer")
+			},
+			expectStatus: http.StatusNoContent,
+		},
+		{
+			name:    "Description by not admin",
+			method:  http.MethodPut,
+			path:    "description/",
+			request: &internalHttp.GroupDescriptionRequest{Description: "Support of customer"},
+			handler: func(connections service.Connections, jidNormalizer *service.JidNormalizer) internalHttp.AppHTTPHandler {
+				return internalHttp.NewGroupDescriptionHandler(connections, jidNormalizer)
+			},
+			expect: func(wac *mock.MockConn) {
+				wac.EXPECT().
+					SetGroupDescription(groupJid, "Support of customer").
+					Return(&whatsapp.StatusError{Query: "group description", Status: http.StatusForbidden})
+			},
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Leave",
+			method: http.MethodPost,
+			path:   "leave/",
+			handler: func(connections service.Connections, jidNormalizer *service.JidNormalizer) internalHttp.AppHTTPHandler {
+				return internalHttp.NewLeaveGroupHandler(connections, jidNormalizer)
+			},
+			expect: func(wac *mock.MockConn) {
+				wac.EXPECT().LeaveGroup(groupJid)
+			},
+			expectStatus: http.StatusNoContent,
+		},
+		{
+			name:   "Invite link",
+			method: http.MethodGet,
+			path:   "invite-link/",
+			handler: func(connections service.Connections, jidNormalizer *service.JidNormalizer) internalHttp.AppHTTPHandler {
+				return internalHttp.NewGroupInviteLinkHandler(connections, jidNormalizer)
+			},
+			expect: func(wac *mock.MockConn) {
+				wac.EXPECT().GroupInviteLink(groupJid).Return(whatsapp.InviteLinkPrefix+"CODE", nil)
+			},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:   "Invite link error",
+			method: http.MethodGet,
+			path:   "invite-link/",
+			handler: func(connections service.Connections, jidNormalizer *service.JidNormalizer) internalHttp.AppHTTPHandler {
+				return internalHttp.NewGroupInviteLinkHandler(connections, jidNormalizer)
+			},
+			expect: func(wac *mock.MockConn) {
+				wac.EXPECT().GroupInviteLink(groupJid).Return("", errors.New("request timed out"))
+			},
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			wac := mock.NewMockConn(c)
+			tt.expect(wac)
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/groups/{sessionID}/{groupID}/" + tt.path: tt.handler(groupConnections(c, wac), service.NewJidNormalizer("")),
+			})
+			defer server.Close()
+
+			request := httpexpect.New(t, server.URL).Request(tt.method, "/groups/_sid_/"+groupJid+"/"+tt.path)
+			if tt.request != nil {
+				request = request.WithJSON(tt.request)
+			}
+			response := request.Expect().Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusOK {
+				response.JSON().Object().ValueEqual("invite_link", "https://chat.whatsapp.com/CODE")
+			}
+		})
+	}
+}
+
+func TestGroupHandler_SessionNotRegistered(t *testing.T) {
+	c := gomock.NewController(t)
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession("_sid_").
+		Return(nil, &service.NotFoundError{SessionID: "_sid_"})
+
+	server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+		"/groups/{sessionID}/{groupID}/": internalHttp.NewGroupHandler(connections, service.NewJidNormalizer("")),
+	})
+	defer server.Close()
+
+	httpexpect.New(t, server.URL).GET("/groups/_sid_/" + groupJid + "/").
+		Expect().
+		Status(http.StatusBadRequest)
+}
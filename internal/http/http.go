@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/r-erema/wapi/internal/config"
 	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
@@ -45,10 +46,19 @@ func (fn AppHandlerRunner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func Router(
 	conf *config.Config,
 	sessRepo repository.Session,
+	msgRepo repository.Message,
 	connSupervisor service.Connections,
 	authorizer service.Authorizer,
 	qrFileResolver service.QRFileResolver,
 	listener service.Listener,
+	queueRepo repository.Queue,
+	queue service.Enqueuer,
+	bulkSender service.BulkSender,
+	scheduler service.Scheduler,
+	templateRepo repository.Template,
+	numberChecker service.NumberChecker,
+	contactRepo repository.Contact,
+	profileProvider service.ProfileProvider,
 	fs os.FileSystem,
 ) (*mux.Router, error) {
 	if conf.Env == config.DevMode {
@@ -63,14 +73,55 @@ func Router(
 		return nil, err
 	}
 	marshal := jsonInfra.MarshallCallback(json.Marshal)
-	sendMessageHandler := NewTextHandler(authorizer, connSupervisor, &marshal)
-	sendImageHandler := NewImageHandler(authorizer, connSupervisor, &http.Client{}, &marshal)
+	jidNormalizer := service.NewJidNormalizer(conf.DefaultCountryCode)
+	maxUploadSize := int64(conf.MaxUploadSize)
+	sendMessageHandler := NewTextHandler(authorizer, connSupervisor, jidNormalizer, msgRepo, queue, scheduler, templateRepo, &marshal)
+	sendImageHandler := NewImageHandler(authorizer, connSupervisor, jidNormalizer, msgRepo, &http.Client{}, &marshal, maxUploadSize)
+	sendDocumentHandler := NewDocumentHandler(authorizer, connSupervisor, jidNormalizer, msgRepo, &http.Client{}, &marshal, maxUploadSize)
+	sendAudioHandler := NewAudioHandler(authorizer, connSupervisor, jidNormalizer, msgRepo, &http.Client{}, &marshal, maxUploadSize)
+	sendVideoHandler := NewVideoHandler(authorizer, connSupervisor, jidNormalizer, msgRepo, &http.Client{}, &marshal, maxUploadSize)
+	sendLocationHandler := NewLocationHandler(authorizer, connSupervisor, jidNormalizer, msgRepo, scheduler, &marshal)
+	sendContactHandler := NewContactHandler(authorizer, connSupervisor, jidNormalizer, msgRepo, scheduler, &marshal)
+	markReadHandler := NewMarkReadHandler(connSupervisor, jidNormalizer)
+	presenceHandler := NewPresenceHandler(connSupervisor, jidNormalizer)
+	subscribePresenceHandler := NewSubscribePresenceHandler(connSupervisor, jidNormalizer)
+	deleteMessageHandler := NewDeleteMessageHandler(connSupervisor, jidNormalizer)
+	sendBulkHandler := NewSendBulkHandler(bulkSender, templateRepo, jidNormalizer)
 	getQRImageHandler := NewQR(fs, qrFileResolver)
+	getMediaHandler := NewMediaHandler(fs, conf.FileSystemRootPath+"/media")
 	getSessionInfoHandler := NewSessInfoHandler(sessRepo)
 	getActiveConnectionInfoHandler := NewInfo(connSupervisor)
+	getQueuedMessageHandler := NewQueuedMessageHandler(queueRepo)
+	getMessageStatusHandler := NewMessageStatusHandler(msgRepo)
+	getBulkJobHandler := NewBulkJobHandler(bulkSender)
+	cancelBulkJobHandler := NewCancelBulkJobHandler(bulkSender)
+	getScheduledMessageHandler := NewScheduledMessageHandler(scheduler)
+	cancelScheduledMessageHandler := NewCancelScheduledMessageHandler(scheduler)
+	createTemplateHandler := NewCreateTemplateHandler(templateRepo)
+	updateTemplateHandler := NewUpdateTemplateHandler(templateRepo)
+	getTemplateHandler := NewTemplateHandler(templateRepo)
+	getTemplatesHandler := NewTemplatesHandler(templateRepo)
+	removeTemplateHandler := NewRemoveTemplateHandler(templateRepo)
+	checkNumbersHandler := NewCheckNumbersHandler(numberChecker)
+	getContactsHandler := NewContactsHandler(contactRepo)
+	getChatsHandler := NewChatsHandler(contactRepo)
+	getProfileHandler := NewProfileHandler(profileProvider, jidNormalizer)
+	getProfilePictureHandler := NewProfilePictureHandler(profileProvider, jidNormalizer, &http.Client{})
+	createGroupHandler := NewCreateGroupHandler(connSupervisor, jidNormalizer)
+	getGroupHandler := NewGroupHandler(connSupervisor, jidNormalizer)
+	groupParticipantsHandler := NewGroupParticipantsHandler(connSupervisor, jidNormalizer)
+	groupSubjectHandler := NewGroupSubjectHandler(connSupervisor, jidNormalizer)
+	groupDescriptionHandler := NewGroupDescriptionHandler(connSupervisor, jidNormalizer)
+	leaveGroupHandler := NewLeaveGroupHandler(connSupervisor, jidNormalizer)
+	getGroupInviteLinkHandler := NewGroupInviteLinkHandler(connSupervisor, jidNormalizer)
+
+	idempotent := func(handler AppHTTPHandler) AppHTTPHandler {
+		return NewIdempotentHandler(handler, msgRepo, time.Duration(conf.IdempotencyWindow)*time.Second)
+	}
 
 	cors := handlers.CORS(
-		handlers.AllowedHeaders([]string{"Content-type"}),
+		handlers.AllowedHeaders([]string{"Content-type", IdempotencyKeyHeader}),
+		handlers.AllowedMethods([]string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodDelete}),
 		handlers.AllowedOrigins([]string{"*"}),
 		handlers.AllowCredentials(),
 	)
@@ -78,11 +129,45 @@ func Router(
 	router.Use(cors)
 
 	router.Handle("/register-session/", AppHandlerRunner{H: registerHandler}).Methods(http.MethodPost)
-	router.Handle("/send-message/", AppHandlerRunner{H: sendMessageHandler}).Methods(http.MethodPost)
-	router.Handle("/send-image/", AppHandlerRunner{H: sendImageHandler}).Methods(http.MethodPost)
+	router.Handle("/send-message/", AppHandlerRunner{H: idempotent(sendMessageHandler)}).Methods(http.MethodPost)
+	router.Handle("/send-image/", AppHandlerRunner{H: idempotent(sendImageHandler)}).Methods(http.MethodPost)
+	router.Handle("/send-document/", AppHandlerRunner{H: idempotent(sendDocumentHandler)}).Methods(http.MethodPost)
+	router.Handle("/send-audio/", AppHandlerRunner{H: idempotent(sendAudioHandler)}).Methods(http.MethodPost)
+	router.Handle("/send-video/", AppHandlerRunner{H: idempotent(sendVideoHandler)}).Methods(http.MethodPost)
+	router.Handle("/send-location/", AppHandlerRunner{H: idempotent(sendLocationHandler)}).Methods(http.MethodPost)
+	router.Handle("/send-contact/", AppHandlerRunner{H: idempotent(sendContactHandler)}).Methods(http.MethodPost)
+	router.Handle("/send-bulk/", AppHandlerRunner{H: idempotent(sendBulkHandler)}).Methods(http.MethodPost)
+	router.Handle("/mark-read/", AppHandlerRunner{H: markReadHandler}).Methods(http.MethodPost)
+	router.Handle("/presence/", AppHandlerRunner{H: presenceHandler}).Methods(http.MethodPost)
+	router.Handle("/presence/{sessionID}/subscribe/", AppHandlerRunner{H: subscribePresenceHandler}).Methods(http.MethodPost)
+	router.Handle("/check-numbers/", AppHandlerRunner{H: checkNumbersHandler}).Methods(http.MethodPost)
+	router.Handle("/contacts/{sessionID}/", AppHandlerRunner{H: getContactsHandler}).Methods(http.MethodGet)
+	router.Handle("/chats/{sessionID}/", AppHandlerRunner{H: getChatsHandler}).Methods(http.MethodGet)
+	router.Handle("/profile/{sessionID}/{jid}/", AppHandlerRunner{H: getProfileHandler}).Methods(http.MethodGet)
+	router.Handle("/profile/{sessionID}/{jid}/picture/", AppHandlerRunner{H: getProfilePictureHandler}).Methods(http.MethodGet)
 	router.Handle("/get-qr-code/{sessionID}/", AppHandlerRunner{H: getQRImageHandler}).Methods(http.MethodGet)
+	router.Handle("/get-media/{fileName}/", AppHandlerRunner{H: getMediaHandler}).Methods(http.MethodGet)
 	router.Handle("/get-session-info/{sessionID}/", AppHandlerRunner{H: getSessionInfoHandler}).Methods(http.MethodGet)
 	router.Handle("/get-active-connection-info/{sessionID}/", AppHandlerRunner{H: getActiveConnectionInfoHandler}).Methods(http.MethodGet)
+	router.Handle("/get-queued-message/{messageID}/", AppHandlerRunner{H: getQueuedMessageHandler}).Methods(http.MethodGet)
+	router.Handle("/messages/{sessionID}/{messageID}/status/", AppHandlerRunner{H: getMessageStatusHandler}).Methods(http.MethodGet)
+	router.Handle("/messages/{sessionID}/{chatID}/{messageID}/", AppHandlerRunner{H: deleteMessageHandler}).Methods(http.MethodDelete)
+	router.Handle("/bulk-jobs/{jobID}/", AppHandlerRunner{H: getBulkJobHandler}).Methods(http.MethodGet)
+	router.Handle("/bulk-jobs/{jobID}/", AppHandlerRunner{H: cancelBulkJobHandler}).Methods(http.MethodDelete)
+	router.Handle("/scheduled/{messageID}/", AppHandlerRunner{H: getScheduledMessageHandler}).Methods(http.MethodGet)
+	router.Handle("/scheduled/{messageID}/", AppHandlerRunner{H: cancelScheduledMessageHandler}).Methods(http.MethodDelete)
+	router.Handle("/templates/", AppHandlerRunner{H: getTemplatesHandler}).Methods(http.MethodGet)
+	router.Handle("/templates/", AppHandlerRunner{H: createTemplateHandler}).Methods(http.MethodPost)
+	router.Handle("/templates/{templateID}/", AppHandlerRunner{H: getTemplateHandler}).Methods(http.MethodGet)
+	router.Handle("/templates/{templateID}/", AppHandlerRunner{H: updateTemplateHandler}).Methods(http.MethodPut)
+	router.Handle("/templates/{templateID}/", AppHandlerRunner{H: removeTemplateHandler}).Methods(http.MethodDelete)
+	router.Handle("/groups/{sessionID}/", AppHandlerRunner{H: idempotent(createGroupHandler)}).Methods(http.MethodPost)
+	router.Handle("/groups/{sessionID}/{groupID}/", AppHandlerRunner{H: getGroupHandler}).Methods(http.MethodGet)
+	router.Handle("/groups/{sessionID}/{groupID}/participants/{action}/", AppHandlerRunner{H: groupParticipantsHandler}).Methods(http.MethodPost)
+	router.Handle("/groups/{sessionID}/{groupID}/subject/", AppHandlerRunner{H: groupSubjectHandler}).Methods(http.MethodPut)
+	router.Handle("/groups/{sessionID}/{groupID}/description/", AppHandlerRunner{H: groupDescriptionHandler}).Methods(http.MethodPut)
+	router.Handle("/groups/{sessionID}/{groupID}/leave/", AppHandlerRunner{H: leaveGroupHandler}).Methods(http.MethodPost)
+	router.Handle("/groups/{sessionID}/{groupID}/invite-link/", AppHandlerRunner{H: getGroupInviteLinkHandler}).Methods(http.MethodGet)
 
 	return router, nil
 }
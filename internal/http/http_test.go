@@ -17,10 +17,19 @@ import (
 type routerMocksFactory func(t *testing.T) (
 	*config.Config,
 	repository.Session,
+	repository.Message,
 	service.Connections,
 	service.Authorizer,
 	service.QRFileResolver,
 	service.Listener,
+	repository.Queue,
+	service.Enqueuer,
+	service.BulkSender,
+	service.Scheduler,
+	repository.Template,
+	service.NumberChecker,
+	repository.Contact,
+	service.ProfileProvider,
 	os.FileSystem,
 )
 
@@ -40,17 +49,26 @@ func TestRouter(t *testing.T) {
 			mocksFactory: func(t *testing.T) (
 				*config.Config,
 				repository.Session,
+				repository.Message,
 				service.Connections,
 				service.Authorizer,
 				service.QRFileResolver,
 				service.Listener,
+				repository.Queue,
+				service.Enqueuer,
+				service.BulkSender,
+				service.Scheduler,
+				repository.Template,
+				service.NumberChecker,
+				repository.Contact,
+				service.ProfileProvider,
 				os.FileSystem,
 			) {
-				conf, _, connSupervisor, authorizer, fileResolver, listener, fs := routerMocks(t)
+				conf, _, msgRepo, connSupervisor, authorizer, fileResolver, listener, queueRepo, queue, bulkSender, scheduler, templateRepo, numberChecker, contactRepo, profileProvider, fs := routerMocks(t)
 				c := gomock.NewController(t)
 				sessRepo := mock.NewMockSession(c)
 				sessRepo.EXPECT().AllSavedSessionIds().Return(nil, errors.New("something went wrong... "))
-				return conf, sessRepo, connSupervisor, authorizer, fileResolver, listener, fs
+				return conf, sessRepo, msgRepo, connSupervisor, authorizer, fileResolver, listener, queueRepo, queue, bulkSender, scheduler, templateRepo, numberChecker, contactRepo, profileProvider, fs
 			},
 			expectError: true,
 		},
@@ -74,10 +92,19 @@ func TestRouter(t *testing.T) {
 func routerMocks(t *testing.T) (
 	*config.Config,
 	repository.Session,
+	repository.Message,
 	service.Connections,
 	service.Authorizer,
 	service.QRFileResolver,
 	service.Listener,
+	repository.Queue,
+	service.Enqueuer,
+	service.BulkSender,
+	service.Scheduler,
+	repository.Template,
+	service.NumberChecker,
+	repository.Contact,
+	service.ProfileProvider,
 	os.FileSystem,
 ) {
 	conf := &config.Config{
@@ -97,9 +124,18 @@ func routerMocks(t *testing.T) (
 	sessRepo.EXPECT().AllSavedSessionIds().Return(nil, nil)
 	return conf,
 		sessRepo,
+		mock.NewMockMessage(c),
 		mock.NewMockConnections(c),
 		mock.NewMockAuthorizer(c),
 		mock.NewMockQRFileResolver(c),
 		mock.NewMockListener(c),
+		mock.NewMockQueue(c),
+		mock.NewMockEnqueuer(c),
+		mock.NewMockBulkSender(c),
+		mock.NewMockScheduler(c),
+		mock.NewMockTemplate(c),
+		mock.NewMockNumberChecker(c),
+		mock.NewMockContact(c),
+		mock.NewMockProfileProvider(c),
 		mock.NewMockFileSystem(c)
 }
@@ -0,0 +1,195 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// IdempotencyKeyHeader is a header containing client generated key which identifies request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks responses replayed for duplicate requests.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyLockTime = time.Minute * 5
+)
+
+// IdempotentHandler makes handler idempotent: the first successful response of request with idempotency key
+// is stored and replayed for duplicate requests of the same session with the same key within window.
+// Requests without idempotency key are handled as usual.
+type IdempotentHandler struct {
+	handler     AppHTTPHandler
+	messageRepo repository.Message
+	window      time.Duration
+}
+
+// NewIdempotentHandler creates IdempotentHandler.
+func NewIdempotentHandler(handler AppHTTPHandler, messageRepo repository.Message, window time.Duration) *IdempotentHandler {
+	return &IdempotentHandler{handler: handler, messageRepo: messageRepo, window: window}
+}
+
+// Handle replays stored response of duplicate request or handles request and stores its response.
+func (h *IdempotentHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+	if idempotencyKey == "" {
+		return h.handler.Handle(w, r)
+	}
+	sessionID, err := requestSessionID(r)
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "can't read request in idempotent handler"),
+			ResponseMsg: "can't read request",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	key := r.URL.Path + ":" + sessionID + ":" + idempotencyKey
+
+	stored, appErr := h.storedResponse(key)
+	if appErr != nil {
+		return appErr
+	}
+	if stored != nil {
+		return h.replay(w, stored)
+	}
+
+	locked, err := h.messageRepo.LockIdempotencyKey(key, idempotencyLockTime)
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "can't lock idempotency key in idempotent handler"),
+			ResponseMsg: "idempotency key checking error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	if !locked {
+		return &AppError{
+			Error:       errors.Errorf("request with idempotency key `%s` is in progress", idempotencyKey),
+			ResponseMsg: "request with the same idempotency key is in progress",
+			Code:        http.StatusConflict,
+		}
+	}
+	defer func() {
+		if err := h.messageRepo.UnlockIdempotencyKey(key); err != nil {
+			log.Printf("can't unlock idempotency key `%s`: %v\n", key, err)
+		}
+	}()
+
+	// Request with the same key could be completed and unlocked between reading stored response and locking.
+	if stored, appErr = h.storedResponse(key); appErr != nil {
+		return appErr
+	}
+	if stored != nil {
+		return h.replay(w, stored)
+	}
+
+	recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	if appErr := h.handler.Handle(recorder, r); appErr != nil {
+		return appErr
+	}
+	response := &model.IdempotentResponse{
+		StatusCode:  recorder.statusCode,
+		ContentType: w.Header().Get("Content-Type"),
+		Body:        recorder.body,
+	}
+	if err := h.messageRepo.SaveIdempotentResponse(key, response, h.window); err != nil {
+		log.Printf("can't store response of request with idempotency key `%s`: %v\n", key, err)
+	}
+	return nil
+}
+
+// requestSessionID reads session of request from JSON body or from form fields preceding the file part
+// of multipart body, so keys of different sessions don't collide. Read part of body is restored for handler,
+// empty session is returned if request can't be decoded, so handler responds to it as usual.
+func requestSessionID(r *http.Request) (string, error) {
+	var read bytes.Buffer
+	body := io.TeeReader(r.Body, &read)
+	defer func() {
+		r.Body = readCloser{Reader: io.MultiReader(&read, r.Body), Closer: r.Body}
+	}()
+
+	contentType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != "multipart/form-data" {
+		if _, err := ioutil.ReadAll(body); err != nil {
+			return "", err
+		}
+		var sessionReq struct {
+			SessionID string `json:"session_name"`
+		}
+		_ = json.Unmarshal(read.Bytes(), &sessionReq)
+		return sessionReq.SessionID, nil
+	}
+
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil || part.FormName() == mediaFormFile {
+			return "", nil
+		}
+		if part.FormName() == "session_name" {
+			value, err := ioutil.ReadAll(part)
+			return string(value), err
+		}
+	}
+}
+
+// readCloser reads restored request body and closes original one.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (h *IdempotentHandler) storedResponse(key string) (*model.IdempotentResponse, *AppError) {
+	stored, err := h.messageRepo.IdempotentResponse(key)
+	if err != nil {
+		return nil, &AppError{
+			Error:       errors.Wrap(err, "can't read stored response in idempotent handler"),
+			ResponseMsg: "idempotency key checking error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return stored, nil
+}
+
+func (h *IdempotentHandler) replay(w http.ResponseWriter, response *model.IdempotentResponse) *AppError {
+	if response.ContentType != "" {
+		w.Header().Set("Content-Type", response.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(response.StatusCode)
+	if _, err := w.Write(response.Body); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "can't write body to response in idempotent handler"),
+			ResponseMsg: "can't write body to response",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
+
+// responseRecorder writes response and keeps its status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       []byte
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(body []byte) (int, error) {
+	r.body = append(r.body, body...)
+	return r.ResponseWriter.Write(body)
+}
@@ -0,0 +1,229 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/model"
+	httpTest "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type sendHandlerStub struct {
+	calls  int
+	appErr *internalHttp.AppError
+}
+
+func (h *sendHandlerStub) Handle(w http.ResponseWriter, r *http.Request) *internalHttp.AppError {
+	h.calls++
+	if h.appErr != nil {
+		return h.appErr
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = w.Write([]byte(`{"id":"MSG_ID"}`))
+	return nil
+}
+
+func TestIdempotentHandler(t *testing.T) {
+	const key = "/send-message/:_sid_:_key_"
+	storedResponse := &model.IdempotentResponse{
+		StatusCode:  http.StatusAccepted,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":"MSG_ID"}`),
+	}
+
+	tests := []struct {
+		name           string
+		idempotencyKey string
+		handler        *sendHandlerStub
+		mocksFactory   func(c *gomock.Controller) *mock.MockMessage
+		expectStatus   int
+		expectCalls    int
+		expectReplayed bool
+	}{
+		{
+			name:           "Request without idempotency key",
+			handler:        &sendHandlerStub{},
+			mocksFactory:   mock.NewMockMessage,
+			expectStatus:   http.StatusAccepted,
+			expectCalls:    1,
+			expectReplayed: false,
+		},
+		{
+			name:           "First request",
+			idempotencyKey: "_key_",
+			handler:        &sendHandlerStub{},
+			mocksFactory: func(c *gomock.Controller) *mock.MockMessage {
+				msgRepo := mock.NewMockMessage(c)
+				msgRepo.EXPECT().IdempotentResponse(key).Return(nil, nil).Times(2)
+				msgRepo.EXPECT().LockIdempotencyKey(key, gomock.Any()).Return(true, nil)
+				msgRepo.EXPECT().SaveIdempotentResponse(key, storedResponse, time.Hour)
+				msgRepo.EXPECT().UnlockIdempotencyKey(key)
+				return msgRepo
+			},
+			expectStatus: http.StatusAccepted,
+			expectCalls:  1,
+		},
+		{
+			name:           "Duplicate request",
+			idempotencyKey: "_key_",
+			handler:        &sendHandlerStub{},
+			mocksFactory: func(c *gomock.Controller) *mock.MockMessage {
+				msgRepo := mock.NewMockMessage(c)
+				msgRepo.EXPECT().IdempotentResponse(key).Return(storedResponse, nil)
+				return msgRepo
+			},
+			expectStatus:   http.StatusAccepted,
+			expectCalls:    0,
+			expectReplayed: true,
+		},
+		{
+			name:           "Duplicate request completed while locking",
+			idempotencyKey: "_key_",
+			handler:        &sendHandlerStub{},
+			mocksFactory: func(c *gomock.Controller) *mock.MockMessage {
+				msgRepo := mock.NewMockMessage(c)
+				gomock.InOrder(
+					msgRepo.EXPECT().IdempotentResponse(key).Return(nil, nil),
+					msgRepo.EXPECT().LockIdempotencyKey(key, gomock.Any()).Return(true, nil),
+					msgRepo.EXPECT().IdempotentResponse(key).Return(storedResponse, nil),
+					msgRepo.EXPECT().UnlockIdempotencyKey(key),
+				)
+				return msgRepo
+			},
+			expectStatus:   http.StatusAccepted,
+			expectCalls:    0,
+			expectReplayed: true,
+		},
+		{
+			name:           "Duplicate request in progress",
+			idempotencyKey: "_key_",
+			handler:        &sendHandlerStub{},
+			mocksFactory: func(c *gomock.Controller) *mock.MockMessage {
+				msgRepo := mock.NewMockMessage(c)
+				msgRepo.EXPECT().IdempotentResponse(key).Return(nil, nil)
+				msgRepo.EXPECT().LockIdempotencyKey(key, gomock.Any()).Return(false, nil)
+				return msgRepo
+			},
+			expectStatus: http.StatusConflict,
+			expectCalls:  0,
+		},
+		{
+			name:           "Failed request isn't stored",
+			idempotencyKey: "_key_",
+			handler: &sendHandlerStub{appErr: &internalHttp.AppError{
+				Error:       errors.New("sending message error"),
+				ResponseMsg: "sending message error",
+				Code:        http.StatusInternalServerError,
+			}},
+			mocksFactory: func(c *gomock.Controller) *mock.MockMessage {
+				msgRepo := mock.NewMockMessage(c)
+				msgRepo.EXPECT().IdempotentResponse(key).Return(nil, nil).Times(2)
+				msgRepo.EXPECT().LockIdempotencyKey(key, gomock.Any()).Return(true, nil)
+				msgRepo.EXPECT().SaveIdempotentResponse(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				msgRepo.EXPECT().UnlockIdempotencyKey(key)
+				return msgRepo
+			},
+			expectStatus: http.StatusInternalServerError,
+			expectCalls:  1,
+		},
+		{
+			name:           "Stored response reading error",
+			idempotencyKey: "_key_",
+			handler:        &sendHandlerStub{},
+			mocksFactory: func(c *gomock.Controller) *mock.MockMessage {
+				msgRepo := mock.NewMockMessage(c)
+				msgRepo.EXPECT().IdempotentResponse(key).Return(nil, errors.New("redis is down"))
+				return msgRepo
+			},
+			expectStatus: http.StatusInternalServerError,
+			expectCalls:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			handler := internalHttp.NewIdempotentHandler(tt.handler, tt.mocksFactory(c), time.Hour)
+			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{"/send-message/": handler})
+			defer server.Close()
+
+			expect := httpexpect.New(t, server.URL)
+			request := expect.POST("/send-message/").WithJSON(map[string]string{"session_name": "_sid_"})
+			if tt.idempotencyKey != "" {
+				request = request.WithHeader(internalHttp.IdempotencyKeyHeader, tt.idempotencyKey)
+			}
+			response := request.Expect().Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusAccepted {
+				response.JSON().Object().ValueEqual("id", "MSG_ID")
+			}
+			if tt.expectReplayed {
+				response.Header(internalHttp.IdempotentReplayedHeader).Equal("true")
+			}
+			assert.Equal(t, tt.expectCalls, tt.handler.calls)
+		})
+	}
+}
+
+func TestIdempotentHandler_SessionsWithSameKey(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	msgRepo := mock.NewMockMessage(c)
+	for _, key := range []string{"/send-message/:_sid_1_:_key_", "/send-message/:_sid_2_:_key_"} {
+		msgRepo.EXPECT().IdempotentResponse(key).Return(nil, nil).Times(2)
+		msgRepo.EXPECT().LockIdempotencyKey(key, gomock.Any()).Return(true, nil)
+		msgRepo.EXPECT().UnlockIdempotencyKey(key)
+		msgRepo.EXPECT().SaveIdempotentResponse(key, gomock.Any(), time.Hour)
+	}
+	var sessions []string
+	handler := internalHttp.NewIdempotentHandler(sessionHandlerStub(func(sessionID string) {
+		sessions = append(sessions, sessionID)
+	}), msgRepo, time.Hour)
+	server := httpTest.New(map[string]internalHttp.AppHTTPHandler{"/send-message/": handler})
+	defer server.Close()
+
+	expect := httpexpect.New(t, server.URL)
+	expect.POST("/send-message/").
+		WithHeader(internalHttp.IdempotencyKeyHeader, "_key_").
+		WithJSON(map[string]string{"session_name": "_sid_1_"}).
+		Expect().
+		Status(http.StatusOK)
+	expect.POST("/send-message/").
+		WithHeader(internalHttp.IdempotencyKeyHeader, "_key_").
+		WithMultipart().
+		WithFormField("session_name", "_sid_2_").
+		WithFile("file", "report.pdf", strings.NewReader("%PDF-1.4 report")).
+		Expect().
+		Status(http.StatusOK)
+	assert.Equal(t, []string{"_sid_1_", "_sid_2_"}, sessions)
+}
+
+// sessionHandlerStub passes session of request decoded by handler to callback.
+type sessionHandlerStub func(sessionID string)
+
+func (h sessionHandlerStub) Handle(w http.ResponseWriter, r *http.Request) *internalHttp.AppError {
+	if err := r.ParseMultipartForm(1024); err == nil {
+		h(r.FormValue("session_name"))
+		return nil
+	}
+	var req struct {
+		SessionID string `json:"session_name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return &internalHttp.AppError{Error: err, ResponseMsg: "can't decode request", Code: http.StatusBadRequest}
+	}
+	h(req.SessionID)
+	return nil
+}
@@ -2,18 +2,21 @@ package http_test
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	internalHttp "github.com/r-erema/wapi/internal/http"
 	httpInfra "github.com/r-erema/wapi/internal/infrastructure/http"
 	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
 	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
 	"github.com/r-erema/wapi/internal/service"
 	httpTest "github.com/r-erema/wapi/internal/testutil/http"
 	"github.com/r-erema/wapi/internal/testutil/mock"
@@ -25,7 +28,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-type imagesMocksFactory func(t *testing.T) (service.Authorizer, service.Connections, httpInfra.Client, *jsonInfra.MarshallCallback)
+// testMaxUploadSize is a max size of media of outgoing messages used in tests.
+const testMaxUploadSize = 1024
+
+type imagesMocksFactory func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64)
 
 func TestNewImageHandler(t *testing.T) {
 	imgHandler := internalHttp.NewImageHandler(mocks(t))
@@ -42,7 +48,7 @@ type testData struct {
 func ok() testData {
 	return testData{
 		"OK",
-		func(t *testing.T) (service.Authorizer, service.Connections, httpInfra.Client, *jsonInfra.MarshallCallback) {
+		func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
 			return mocks(t)
 		},
 		imageRequest,
@@ -53,7 +59,7 @@ func ok() testData {
 func badImageRequest() testData {
 	return testData{
 		name: "Bad image request",
-		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, httpInfra.Client, *jsonInfra.MarshallCallback) {
+		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
 			return mocks(t)
 		},
 		jsonRequest: func() interface{} {
@@ -66,14 +72,14 @@ func badImageRequest() testData {
 func connectionNotFound() testData {
 	return testData{
 		name: "Connection not found",
-		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, httpInfra.Client, *jsonInfra.MarshallCallback) {
-			authorizer, _, client, marshal := mocks(t)
+		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+			authorizer, _, jidNormalizer, msgRepo, client, marshal, maxSize := mocks(t)
 			c := gomock.NewController(t)
 			connections := mock.NewMockConnections(c)
 			connections.EXPECT().
 				AuthenticatedConnectionForSession(gomock.Any()).
 				Return(nil, &service.NotFoundError{})
-			return authorizer, connections, client, marshal
+			return authorizer, connections, jidNormalizer, msgRepo, client, marshal, maxSize
 		},
 		jsonRequest:  imageRequest,
 		expectStatus: http.StatusBadRequest,
@@ -83,31 +89,48 @@ func connectionNotFound() testData {
 func badImageURL() testData {
 	return testData{
 		name: "Bad image url",
-		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, httpInfra.Client, *jsonInfra.MarshallCallback) {
-			authorizer, connections, _, marshal := mocks(t)
+		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+			authorizer, connections, jidNormalizer, msgRepo, _, marshal, maxSize := mocks(t)
 			c := gomock.NewController(t)
 			httpClient := mock.NewMockClient(c)
 			httpClient.EXPECT().
 				Get(gomock.Any()).
 				Return(nil, fmt.Errorf("bad image url"))
-			return authorizer, connections, httpClient, marshal
+			return authorizer, connections, jidNormalizer, msgRepo, httpClient, marshal, maxSize
 		},
 		jsonRequest:  imageRequest,
 		expectStatus: http.StatusInternalServerError,
 	}
 }
 
+func notFoundImageURL() testData {
+	return testData{
+		name: "Image url not found",
+		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+			authorizer, connections, jidNormalizer, msgRepo, _, marshal, maxSize := mocks(t)
+			c := gomock.NewController(t)
+			httpClient := mock.NewMockClient(c)
+			httpClient.EXPECT().
+				Get(gomock.Any()).
+				Return(&http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewBufferString("not found"))}, nil)
+			return authorizer, connections, jidNormalizer, msgRepo, httpClient, marshal, maxSize
+		},
+		jsonRequest:  imageRequest,
+		expectStatus: http.StatusBadGateway,
+	}
+}
+
 func cantReadImageBody() testData {
 	return testData{
 		name: "Couldn't read image body by url",
-		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, httpInfra.Client, *jsonInfra.MarshallCallback) {
-			authorizer, connections, _, marshal := mocks(t)
+		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+			authorizer, connections, jidNormalizer, msgRepo, _, marshal, maxSize := mocks(t)
 			c := gomock.NewController(t)
 			httpClient := mock.NewMockClient(c)
 			httpClient.EXPECT().
 				Get(gomock.Any()).
-				Return(&http.Response{Body: ioutil.NopCloser(&mock.FailReader{})}, nil)
-			return authorizer, connections, httpClient, marshal
+				Return(&http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(&mock.FailReader{})}, nil)
+			return authorizer, connections, jidNormalizer, msgRepo, httpClient, marshal, maxSize
 		},
 		jsonRequest:  imageRequest,
 		expectStatus: http.StatusInternalServerError,
@@ -117,8 +140,8 @@ func cantReadImageBody() testData {
 func errorImageSending() testData {
 	return testData{
 		name: "Error image sending",
-		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, httpInfra.Client, *jsonInfra.MarshallCallback) {
-			authorizer, _, httpClient, marshal := mocks(t)
+		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+			authorizer, _, jidNormalizer, msgRepo, httpClient, marshal, maxSize := mocks(t)
 			c := gomock.NewController(t)
 			wac := mock.NewMockConn(c)
 			wac.EXPECT().Info().Return(&whatsapp.Info{Wid: "wid"})
@@ -129,7 +152,7 @@ func errorImageSending() testData {
 				AuthenticatedConnectionForSession(gomock.Any()).
 				Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
 
-			return authorizer, connections, httpClient, marshal
+			return authorizer, connections, jidNormalizer, msgRepo, httpClient, marshal, maxSize
 		},
 		jsonRequest:  imageRequest,
 		expectStatus: http.StatusInternalServerError,
@@ -142,29 +165,120 @@ func marshalingError() testData {
 		imagesMocksFactory: func(t *testing.T) (
 			service.Authorizer,
 			service.Connections,
+			*service.JidNormalizer,
+			repository.Message,
 			httpInfra.Client,
 			*jsonInfra.MarshallCallback,
+			int64,
 		) {
-			authorizer, connections, httpClient, _ := mocks(t)
+			authorizer, connections, jidNormalizer, msgRepo, httpClient, _, maxSize := mocks(t)
 			marshal := jsonInfra.MarshallCallback(func(i interface{}) ([]byte, error) {
 				return nil, errors.New("marshaling error")
 			})
-			return authorizer, connections, httpClient, &marshal
+			return authorizer, connections, jidNormalizer, msgRepo, httpClient, &marshal, maxSize
 		},
 		jsonRequest:  imageRequest,
 		expectStatus: http.StatusInternalServerError,
 	}
 }
 
+func base64Image() testData {
+	return testData{
+		name: "Image in base64",
+		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+			return mocks(t)
+		},
+		jsonRequest: func() interface{} {
+			return &internalHttp.SendImageRequest{
+				SessionID:   "_sid_",
+				ChatID:      "+000000000000",
+				ImageBase64: "iVBORw0KGgo=",
+			}
+		},
+		expectStatus: http.StatusOK,
+	}
+}
+
+func invalidBase64Image() testData {
+	return testData{
+		name: "Invalid base64 image",
+		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+			return mocks(t)
+		},
+		jsonRequest: func() interface{} {
+			return &internalHttp.SendImageRequest{
+				SessionID:   "_sid_",
+				ChatID:      "+000000000000",
+				ImageBase64: "not base64!",
+			}
+		},
+		expectStatus: http.StatusBadRequest,
+	}
+}
+
+func missingImageSource() testData {
+	return testData{
+		name: "Missing image source",
+		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+			return mocks(t)
+		},
+		jsonRequest: func() interface{} {
+			return &internalHttp.SendImageRequest{SessionID: "_sid_", ChatID: "+000000000000"}
+		},
+		expectStatus: http.StatusBadRequest,
+	}
+}
+
+func tooLargeImage() testData {
+	return testData{
+		name: "Too large image",
+		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+			return mocks(t)
+		},
+		jsonRequest: func() interface{} {
+			return &internalHttp.SendImageRequest{
+				SessionID:   "_sid_",
+				ChatID:      "+000000000000",
+				ImageBase64: base64.StdEncoding.EncodeToString(make([]byte, testMaxUploadSize+1)),
+			}
+		},
+		expectStatus: http.StatusRequestEntityTooLarge,
+	}
+}
+
+func tooLargeImageRequest() testData {
+	return testData{
+		name: "Too large image request",
+		imagesMocksFactory: func(t *testing.T) (service.Authorizer, service.Connections, *service.JidNormalizer, repository.Message, httpInfra.Client, *jsonInfra.MarshallCallback, int64) {
+			return mocks(t)
+		},
+		jsonRequest: func() interface{} {
+			return &internalHttp.SendImageRequest{
+				SessionID: "_sid_",
+				ChatID:    "+000000000000",
+				Caption:   strings.Repeat("a", 100*1024),
+				ImageURL:  "https://img.jpg",
+			}
+		},
+		expectStatus: http.StatusRequestEntityTooLarge,
+	}
+}
+
 func TestSendImageHandler(t *testing.T) {
 	tests := []testData{
 		ok(),
 		badImageRequest(),
 		connectionNotFound(),
 		badImageURL(),
+		notFoundImageURL(),
 		cantReadImageBody(),
 		errorImageSending(),
 		marshalingError(),
+		base64Image(),
+		invalidBase64Image(),
+		missingImageSource(),
+		tooLargeImage(),
+		tooLargeImageRequest(),
 	}
 
 	for _, tt := range tests {
@@ -186,7 +300,7 @@ func TestSendImageHandler(t *testing.T) {
 func TestFailWriteResponse(t *testing.T) {
 	handler := internalHttp.NewImageHandler(mocks(t))
 	w := mock.NewFailResponseRecorder(httptest.NewRecorder())
-	r, err := http.NewRequest("POST", "/send-image/", bytes.NewReader([]byte("{}")))
+	r, err := http.NewRequest("POST", "/send-image/", bytes.NewReader([]byte(`{"chat_id":"+000000000000","image_url":"https://img.jpg"}`)))
 	require.Nil(t, err)
 	internalHttp.AppHandlerRunner{H: handler}.ServeHTTP(w, r)
 	assert.Equal(t, w.Status(), http.StatusInternalServerError)
@@ -195,8 +309,11 @@ func TestFailWriteResponse(t *testing.T) {
 func mocks(t *testing.T) (
 	*mock.MockAuthorizer,
 	*mock.MockConnections,
+	*service.JidNormalizer,
+	*mock.MockMessage,
 	*mock.MockClient,
 	*jsonInfra.MarshallCallback,
+	int64,
 ) {
 	c := gomock.NewController(t)
 
@@ -212,10 +329,17 @@ func mocks(t *testing.T) (
 	httpClient := mock.NewMockClient(c)
 	httpClient.EXPECT().
 		Get(gomock.Any()).
-		Return(&http.Response{Body: ioutil.NopCloser(bytes.NewBufferString("{}"))}, nil)
+		Return(&http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewBufferString("{}"))}, nil)
 
 	marshal := jsonInfra.MarshallCallback(json.Marshal)
-	return mock.NewMockAuthorizer(c), connections, httpClient, &marshal
+	return mock.NewMockAuthorizer(c), connections, service.NewJidNormalizer(""), sentStatusRepo(c), httpClient, &marshal, testMaxUploadSize
+}
+
+// sentStatusRepo creates repository mock storing statuses of sent messages.
+func sentStatusRepo(c *gomock.Controller) *mock.MockMessage {
+	msgRepo := mock.NewMockMessage(c)
+	msgRepo.EXPECT().UpdateMessageStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	return msgRepo
 }
 
 func imageRequest() interface{} {
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/repository"
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/pkg/errors"
+)
+
+// SendLocationHandler is responsible for sending locations.
+type SendLocationHandler struct {
+	auth      service.Authorizer
+	sender    *messageSender
+	scheduler service.Scheduler
+}
+
+// NewLocationHandler creates SendLocationHandler.
+func NewLocationHandler(
+	authorizer service.Authorizer,
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+	msgRepo repository.Message,
+	scheduler service.Scheduler,
+	marshal *jsonInfra.MarshallCallback,
+) *SendLocationHandler {
+	return &SendLocationHandler{
+		auth:      authorizer,
+		sender:    newMessageSender(connectionsSupervisor, jidNormalizer, msgRepo, marshal, "location"),
+		scheduler: scheduler,
+	}
+}
+
+// Handle sends location message to WhatsApp server, message with sending time is scheduled.
+func (h *SendLocationHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	decoder := json.NewDecoder(r.Body)
+	var msgReq SendLocationRequest
+	err := decoder.Decode(&msgReq)
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "can't decode request in location handler"),
+			ResponseMsg: "can't decode request",
+			Code:        http.StatusBadRequest,
+		}
+	}
+
+	if msgReq.Latitude < -90 || msgReq.Latitude > 90 || msgReq.Longitude < -180 || msgReq.Longitude > 180 {
+		return &AppError{
+			Error: errors.Errorf(
+				"invalid coordinates %f, %f in location handler",
+				msgReq.Latitude,
+				msgReq.Longitude,
+			),
+			ResponseMsg: "invalid coordinates",
+			Code:        http.StatusBadRequest,
+		}
+	}
+
+	if msgReq.SendAt != nil {
+		chatID, appErr := h.sender.chatJid(msgReq.ChatID)
+		if appErr != nil {
+			return appErr
+		}
+		message := newProtoMessage(chatID, &proto.Message{LocationMessage: &proto.LocationMessage{
+			DegreesLatitude:  &msgReq.Latitude,
+			DegreesLongitude: &msgReq.Longitude,
+			Name:             &msgReq.Name,
+			Address:          &msgReq.Address,
+			Url:              &msgReq.URL,
+		}})
+		return h.sender.schedule(w, h.scheduler, msgReq.SessionID, message, *msgReq.SendAt)
+	}
+
+	return h.sender.send(w, msgReq.SessionID, msgReq.ChatID, func(info whatsapp.MessageInfo) (interface{}, *AppError) {
+		return whatsapp.LocationMessage{
+			Info:             info,
+			DegreesLatitude:  msgReq.Latitude,
+			DegreesLongitude: msgReq.Longitude,
+			Name:             msgReq.Name,
+			Address:          msgReq.Address,
+			Url:              msgReq.URL,
+		}, nil
+	})
+}
+
+// SendLocationRequest is the request for sending location to WhatsApp, location with sending time is scheduled.
+type SendLocationRequest struct {
+	SessionID string     `json:"session_name"`
+	ChatID    string     `json:"chat_id"`
+	Latitude  float64    `json:"latitude"`
+	Longitude float64    `json:"longitude"`
+	Name      string     `json:"name"`
+	Address   string     `json:"address"`
+	URL       string     `json:"url"`
+	SendAt    *time.Time `json:"send_at"`
+}
@@ -0,0 +1,139 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	httpTest "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	waProto "github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/gavv/httpexpect"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLocationHandler(t *testing.T) {
+	handler := internalHttp.NewLocationHandler(mocksSchedulingHandler(t))
+	assert.NotNil(t, handler)
+}
+
+func TestSendLocationHandler(t *testing.T) {
+	sendAt := time.Now().Add(time.Hour)
+	tests := []struct {
+		name         string
+		request      interface{}
+		expectStatus int
+	}{
+		{
+			name: "OK",
+			request: &internalHttp.SendLocationRequest{
+				SessionID: "_sid_",
+				ChatID:    "+000000000000",
+				Latitude:  53.9,
+				Longitude: 27.56,
+				Name:      "Minsk",
+			},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Bad location request",
+			request:      "",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid coordinates",
+			request: &internalHttp.SendLocationRequest{
+				SessionID: "_sid_",
+				ChatID:    "+000000000000",
+				Latitude:  91,
+				Longitude: 27.56,
+			},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Scheduled location",
+			request: &internalHttp.SendLocationRequest{
+				SessionID: "_sid_",
+				ChatID:    "+000000000000",
+				Latitude:  53.9,
+				Longitude: 27.56,
+				SendAt:    &sendAt,
+			},
+			expectStatus: http.StatusAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{
+				"/send-location/": internalHttp.NewLocationHandler(mocksSchedulingHandler(t)),
+			})
+			defer server.Close()
+
+			expect := httpexpect.New(t, server.URL)
+			expect.POST("/send-location/").
+				WithJSON(tt.request).
+				Expect().
+				Status(tt.expectStatus)
+		})
+	}
+}
+
+func TestSendLocationHandler_Schedule(t *testing.T) {
+	c := gomock.NewController(t)
+	sendAt := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+	scheduler := mock.NewMockScheduler(c)
+	scheduler.EXPECT().
+		Schedule("_sid_", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(sessionID string, message *waProto.WebMessageInfo, at time.Time) (*model.ScheduledMessage, error) {
+			assert.Equal(t, "000000000000@s.whatsapp.net", message.GetKey().GetRemoteJid())
+			assert.NotEmpty(t, message.GetKey().GetId())
+			assert.Equal(t, 53.9, message.GetMessage().GetLocationMessage().GetDegreesLatitude())
+			assert.Equal(t, "Minsk", message.GetMessage().GetLocationMessage().GetName())
+			assert.True(t, sendAt.Equal(at))
+			return &model.ScheduledMessage{
+				ID:        message.GetKey().GetId(),
+				SessionID: sessionID,
+				ChatID:    message.GetKey().GetRemoteJid(),
+				SendAt:    at,
+				Status:    model.ScheduledStatus,
+			}, nil
+		})
+	marshal := jsonInfra.MarshallCallback(json.Marshal)
+
+	server := httpTest.New(map[string]internalHttp.AppHTTPHandler{
+		"/send-location/": internalHttp.NewLocationHandler(
+			mock.NewMockAuthorizer(c),
+			mock.NewMockConnections(c),
+			service.NewJidNormalizer(""),
+			sentStatusRepo(c),
+			scheduler,
+			&marshal,
+		),
+	})
+	defer server.Close()
+
+	expect := httpexpect.New(t, server.URL)
+	expect.POST("/send-location/").
+		WithJSON(&internalHttp.SendLocationRequest{
+			SessionID: "_sid_",
+			ChatID:    "+000000000000",
+			Latitude:  53.9,
+			Longitude: 27.56,
+			Name:      "Minsk",
+			SendAt:    &sendAt,
+		}).
+		Expect().
+		Status(http.StatusAccepted).
+		JSON().Object().
+		ValueEqual("status", model.ScheduledStatus).
+		ValueEqual("timestamp", sendAt.Unix())
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/r-erema/wapi/internal/infrastructure/os"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// MediaHandler represents a handler for getting stored media files of incoming messages.
+type MediaHandler struct {
+	fs               os.FileSystem
+	mediaStoragePath string
+}
+
+// NewMediaHandler creates MediaHandler.
+func NewMediaHandler(fs os.FileSystem, mediaStoragePath string) *MediaHandler {
+	return &MediaHandler{fs: fs, mediaStoragePath: mediaStoragePath}
+}
+
+// Handle sends media file.
+func (h *MediaHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	params := mux.Vars(r)
+	fileName := path.Base(params["fileName"])
+	mediaPath := fmt.Sprintf("%s/%s", h.mediaStoragePath, fileName)
+	if _, err := h.fs.Stat(mediaPath); h.fs.IsNotExist(err) {
+		return &AppError{
+			Error:       errors.Wrap(err, "media file not found in media handler"),
+			ResponseMsg: "media file not found",
+			Code:        http.StatusNotFound,
+		}
+	}
+
+	f, err := h.fs.Open(mediaPath)
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "can't open media file in media handler"),
+			ResponseMsg: "can't open media file",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	buffer := new(bytes.Buffer)
+	if _, err := io.Copy(buffer, f); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "can't read media file in media handler"),
+			ResponseMsg: "can't read media file",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(fileName))
+	if contentType == "" {
+		contentType = http.DetectContentType(buffer.Bytes())
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(buffer.Bytes())))
+	if _, err := w.Write(buffer.Bytes()); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "unable to write media file in media handler"),
+			ResponseMsg: "unable to write media file",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package http_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	httpTest "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMediaHandler(t *testing.T) {
+	handler := internalHttp.NewMediaHandler(mediaMocks(t), "/fake/path")
+	assert.NotNil(t, handler)
+}
+
+func TestMediaHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		mocksFactory func(t *testing.T) *mock.MockFileSystem
+		expectStatus int
+	}{
+		{
+			name:         "OK",
+			mocksFactory: mediaMocks,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name: "Media file not found",
+			mocksFactory: func(t *testing.T) *mock.MockFileSystem {
+				c := gomock.NewController(t)
+				fs := mock.NewMockFileSystem(c)
+				fs.EXPECT().Stat(gomock.Any()).Return(nil, nil)
+				fs.EXPECT().IsNotExist(gomock.Any()).Return(true)
+				return fs
+			},
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name: "Couldn't open media file",
+			mocksFactory: func(t *testing.T) *mock.MockFileSystem {
+				c := gomock.NewController(t)
+				fs := mock.NewMockFileSystem(c)
+				fs.EXPECT().Stat(gomock.Any()).Return(nil, nil)
+				fs.EXPECT().IsNotExist(gomock.Any()).Return(false)
+				fs.EXPECT().Open(gomock.Any()).Return(nil, errors.New("can't open file"))
+				return fs
+			},
+			expectStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "Couldn't read media file",
+			mocksFactory: func(t *testing.T) *mock.MockFileSystem {
+				c := gomock.NewController(t)
+				fs := mock.NewMockFileSystem(c)
+				fs.EXPECT().Stat(gomock.Any()).Return(nil, nil)
+				fs.EXPECT().IsNotExist(gomock.Any()).Return(false)
+
+				file := mock.NewMockFile(c)
+				file.EXPECT().Read(gomock.Any()).Return(0, io.ErrUnexpectedEOF)
+				file.EXPECT().Close().Return(nil)
+				fs.EXPECT().Open(gomock.Any()).Return(file, nil)
+				return fs
+			},
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			handler := internalHttp.NewMediaHandler(tt.mocksFactory(t), "/fake/path")
+			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{"/get-media/{fileName}/": handler})
+			defer server.Close()
+
+			expect := httpexpect.New(t, server.URL)
+			expect.GET("/get-media/{fileName}/", "MSG_ID.jpg").
+				Expect().
+				Status(tt.expectStatus)
+		})
+	}
+}
+
+func TestMediaHandlerFailWriteResponse(t *testing.T) {
+	handler := internalHttp.NewMediaHandler(mediaMocks(t), "/fake/path")
+	w := mock.NewFailResponseRecorder(httptest.NewRecorder())
+	r, err := http.NewRequest("GET", "/get-media/MSG_ID.jpg/", nil)
+	require.Nil(t, err)
+	internalHttp.AppHandlerRunner{H: handler}.ServeHTTP(w, r)
+	assert.Equal(t, w.Status(), http.StatusInternalServerError)
+}
+
+func mediaMocks(t *testing.T) *mock.MockFileSystem {
+	c := gomock.NewController(t)
+	fs := mock.NewMockFileSystem(c)
+	fs.EXPECT().Stat(gomock.Any()).Return(nil, nil)
+	fs.EXPECT().IsNotExist(gomock.Any()).Return(false)
+
+	file := mock.NewMockFile(c)
+	file.EXPECT().Read(gomock.Any()).Return(0, io.EOF)
+	file.EXPECT().Close().Return(nil)
+	fs.EXPECT().Open(gomock.Any()).Return(file, nil)
+	return fs
+}
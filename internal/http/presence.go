@@ -0,0 +1,239 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	infrastructureWhatsapp "github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// Typing speed of "typing..." shown before sending text, long texts are typed no longer than maxTypingDuration.
+const (
+	typingDurationPerChar = 50 * time.Millisecond
+	minTypingDuration     = 500 * time.Millisecond
+	maxTypingDuration     = 10 * time.Second
+)
+
+// chatPresences are presences shown in particular chat, other presences are shown to all contacts.
+var chatPresences = map[whatsapp.Presence]bool{
+	whatsapp.PresenceComposing: true,
+	whatsapp.PresenceRecording: true,
+	whatsapp.PresencePaused:    true,
+}
+
+// PresenceHandler is responsible for sending presence of session account, e.g. "typing..." in chat.
+type PresenceHandler struct {
+	resolver *chatResolver
+}
+
+// NewPresenceHandler creates PresenceHandler.
+func NewPresenceHandler(
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+) *PresenceHandler {
+	return &PresenceHandler{resolver: &chatResolver{
+		connectionsSupervisor: connectionsSupervisor,
+		jidNormalizer:         jidNormalizer,
+		handlerName:           "presence",
+	}}
+}
+
+// Handle sends presence to WhatsApp server.
+func (handler *PresenceHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	var presenceReq PresenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&presenceReq); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "decoding error in presence handler"),
+			ResponseMsg: "can't decode request",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	presence := whatsapp.Presence(presenceReq.Presence)
+	if !chatPresences[presence] && presence != whatsapp.PresenceAvailable && presence != whatsapp.PresenceUnavailable {
+		return &AppError{
+			Error:       errors.Errorf("unknown presence `%s` in presence handler", presence),
+			ResponseMsg: "presence must be one of composing, recording, paused, available, unavailable",
+			Code:        http.StatusBadRequest,
+		}
+	}
+
+	var chatJid string
+	if chatPresences[presence] {
+		var appErr *AppError
+		if chatJid, appErr = handler.resolver.chatJid(presenceReq.ChatID); appErr != nil {
+			return appErr
+		}
+	}
+	wac, appErr := handler.resolver.connection(presenceReq.SessionID)
+	if appErr != nil {
+		return appErr
+	}
+	if err := wac.Presence(chatJid, presence); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "sending presence error in presence handler"),
+			ResponseMsg: "sending presence error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// PresenceRequest is the request for sending presence,
+// chat is required for composing, recording and paused presences only.
+type PresenceRequest struct {
+	SessionID string `json:"session_name"`
+	ChatID    string `json:"chat_id"`
+	Presence  string `json:"presence"`
+}
+
+// SubscribePresenceHandler is responsible for subscribing session to presence of contact.
+type SubscribePresenceHandler struct {
+	resolver *chatResolver
+}
+
+// NewSubscribePresenceHandler creates SubscribePresenceHandler.
+func NewSubscribePresenceHandler(
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+) *SubscribePresenceHandler {
+	return &SubscribePresenceHandler{resolver: &chatResolver{
+		connectionsSupervisor: connectionsSupervisor,
+		jidNormalizer:         jidNormalizer,
+		handlerName:           "subscribe presence",
+	}}
+}
+
+// Handle subscribes session to presence of contact, updates of presence are sent to webhook.
+func (handler *SubscribePresenceHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	var subscribeReq SubscribePresenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&subscribeReq); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "decoding error in subscribe presence handler"),
+			ResponseMsg: "can't decode request",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	jid, appErr := handler.resolver.chatJid(subscribeReq.ChatID)
+	if appErr != nil {
+		return appErr
+	}
+	if !strings.HasSuffix(jid, service.UserJidSuffix) {
+		return &AppError{
+			Error:       errors.Errorf("presence of group `%s` requested in subscribe presence handler", jid),
+			ResponseMsg: "presence is available for users only",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	wac, appErr := handler.resolver.connection(mux.Vars(r)["sessionID"])
+	if appErr != nil {
+		return appErr
+	}
+	if err := wac.SubscribePresence(jid); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "subscribing presence error in subscribe presence handler"),
+			ResponseMsg: "subscribing presence error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// SubscribePresenceRequest is the request for subscribing to presence of user of chat.
+type SubscribePresenceRequest struct {
+	ChatID string `json:"chat_id"`
+}
+
+// MarkReadHandler is responsible for marking chat messages as read.
+type MarkReadHandler struct {
+	resolver *chatResolver
+}
+
+// NewMarkReadHandler creates MarkReadHandler.
+func NewMarkReadHandler(
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+) *MarkReadHandler {
+	return &MarkReadHandler{resolver: &chatResolver{
+		connectionsSupervisor: connectionsSupervisor,
+		jidNormalizer:         jidNormalizer,
+		handlerName:           "mark read",
+	}}
+}
+
+// Handle marks messages of chat up to requested message as read.
+func (handler *MarkReadHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	var readReq MarkReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&readReq); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "decoding error in mark read handler"),
+			ResponseMsg: "can't decode request",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	if readReq.MessageID == "" {
+		return &AppError{
+			Error:       errors.New("no message id in mark read handler"),
+			ResponseMsg: "message_id is required",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	chatJid, appErr := handler.resolver.chatJid(readReq.ChatID)
+	if appErr != nil {
+		return appErr
+	}
+	wac, appErr := handler.resolver.connection(readReq.SessionID)
+	if appErr != nil {
+		return appErr
+	}
+	if err := wac.Read(chatJid, readReq.MessageID); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "marking messages as read error in mark read handler"),
+			ResponseMsg: "marking messages as read error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// MarkReadRequest is the request for marking messages of chat up to message with id as read.
+type MarkReadRequest struct {
+	SessionID string `json:"session_name"`
+	ChatID    string `json:"chat_id"`
+	MessageID string `json:"message_id"`
+}
+
+// typingDuration calculates duration of typing text.
+func typingDuration(text string) time.Duration {
+	duration := time.Duration(utf8.RuneCountInString(text)) * typingDurationPerChar
+	switch {
+	case duration < minTypingDuration:
+		return minTypingDuration
+	case duration > maxTypingDuration:
+		return maxTypingDuration
+	}
+	return duration
+}
+
+// simulateTyping shows "typing..." in chat during duration, presence errors don't prevent sending of message.
+func simulateTyping(wac infrastructureWhatsapp.Conn, chatJid string, duration time.Duration) {
+	if err := wac.Presence(chatJid, whatsapp.PresenceComposing); err != nil {
+		log.Printf("sending typing presence error: %v\n", err)
+		return
+	}
+	time.Sleep(duration)
+	if err := wac.Presence(chatJid, whatsapp.PresencePaused); err != nil {
+		log.Printf("sending paused presence error: %v\n", err)
+	}
+}
@@ -0,0 +1,185 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	testHttp "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPresenceHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        interface{}
+		expectPresence bool
+		expectJid      string
+		presenceErr    error
+		expectStatus   int
+	}{
+		{
+			name:           "Composing",
+			request:        internalHttp.PresenceRequest{SessionID: "_sid_", ChatID: "+375000000001", Presence: "composing"},
+			expectPresence: true,
+			expectJid:      "375000000001@s.whatsapp.net",
+			expectStatus:   http.StatusNoContent,
+		},
+		{
+			name:           "Available",
+			request:        internalHttp.PresenceRequest{SessionID: "_sid_", Presence: "available"},
+			expectPresence: true,
+			expectStatus:   http.StatusNoContent,
+		},
+		{
+			name:         "Unknown presence",
+			request:      internalHttp.PresenceRequest{SessionID: "_sid_", ChatID: "+375000000001", Presence: "sleeping"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "No chat of composing presence",
+			request:      internalHttp.PresenceRequest{SessionID: "_sid_", Presence: "composing"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Sending error",
+			request:        internalHttp.PresenceRequest{SessionID: "_sid_", ChatID: "+375000000001", Presence: "paused"},
+			expectPresence: true,
+			expectJid:      "375000000001@s.whatsapp.net",
+			presenceErr:    errors.New("connection closed"),
+			expectStatus:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			wac := mock.NewMockConn(c)
+			connections := mock.NewMockConnections(c)
+			if tt.expectPresence {
+				wac.EXPECT().Presence(tt.expectJid, gomock.Any()).Return(tt.presenceErr)
+				connections.EXPECT().
+					AuthenticatedConnectionForSession("_sid_").
+					Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/presence/": internalHttp.NewPresenceHandler(connections, service.NewJidNormalizer("")),
+			})
+			defer server.Close()
+			httpexpect.New(t, server.URL).POST("/presence/").WithJSON(tt.request).Expect().Status(tt.expectStatus)
+		})
+	}
+}
+
+func TestSubscribePresenceHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name            string
+		request         interface{}
+		expectSubscribe bool
+		subscribeErr    error
+		expectStatus    int
+	}{
+		{
+			name:            "OK",
+			request:         internalHttp.SubscribePresenceRequest{ChatID: "+375000000001"},
+			expectSubscribe: true,
+			expectStatus:    http.StatusNoContent,
+		},
+		{
+			name:         "Group chat",
+			request:      internalHttp.SubscribePresenceRequest{ChatID: "375000000001-1589212345@g.us"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:            "Subscribing error",
+			request:         internalHttp.SubscribePresenceRequest{ChatID: "375000000001@s.whatsapp.net"},
+			expectSubscribe: true,
+			subscribeErr:    &whatsapp.StatusError{Query: "subscribe presence", Status: http.StatusBadRequest},
+			expectStatus:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			wac := mock.NewMockConn(c)
+			connections := mock.NewMockConnections(c)
+			if tt.expectSubscribe {
+				wac.EXPECT().SubscribePresence("375000000001@s.whatsapp.net").Return(tt.subscribeErr)
+				connections.EXPECT().
+					AuthenticatedConnectionForSession("_sid_").
+					Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/presence/{sessionID}/subscribe/": internalHttp.NewSubscribePresenceHandler(connections, service.NewJidNormalizer("")),
+			})
+			defer server.Close()
+			httpexpect.New(t, server.URL).POST("/presence/_sid_/subscribe/").WithJSON(tt.request).Expect().Status(tt.expectStatus)
+		})
+	}
+}
+
+func TestMarkReadHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      interface{}
+		expectRead   bool
+		connErr      error
+		expectStatus int
+	}{
+		{
+			name:         "OK",
+			request:      internalHttp.MarkReadRequest{SessionID: "_sid_", ChatID: "+375000000001", MessageID: "MSG_ID"},
+			expectRead:   true,
+			expectStatus: http.StatusNoContent,
+		},
+		{
+			name:         "No message id",
+			request:      internalHttp.MarkReadRequest{SessionID: "_sid_", ChatID: "+375000000001"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Session isn't connected",
+			request:      internalHttp.MarkReadRequest{SessionID: "_sid_", ChatID: "+375000000001", MessageID: "MSG_ID"},
+			connErr:      &service.NotFoundError{SessionID: "_sid_"},
+			expectStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			wac := mock.NewMockConn(c)
+			connections := mock.NewMockConnections(c)
+			if tt.expectRead {
+				wac.EXPECT().Read("375000000001@s.whatsapp.net", "MSG_ID").Return(nil)
+			}
+			if tt.connErr != nil {
+				connections.EXPECT().AuthenticatedConnectionForSession("_sid_").Return(nil, tt.connErr)
+			} else {
+				connections.EXPECT().
+					AuthenticatedConnectionForSession("_sid_").
+					Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil).
+					AnyTimes()
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/mark-read/": internalHttp.NewMarkReadHandler(connections, service.NewJidNormalizer("")),
+			})
+			defer server.Close()
+			httpexpect.New(t, server.URL).POST("/mark-read/").WithJSON(tt.request).Expect().Status(tt.expectStatus)
+		})
+	}
+}
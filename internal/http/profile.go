@@ -0,0 +1,138 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	httpInfra "github.com/r-erema/wapi/internal/infrastructure/http"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// profileReader resolves profile of account requested by path of profile handlers.
+type profileReader struct {
+	profileProvider service.ProfileProvider
+	jidNormalizer   *service.JidNormalizer
+}
+
+func (p *profileReader) profile(r *http.Request) (*model.Profile, *AppError) {
+	jid, err := p.jidNormalizer.Normalize(mux.Vars(r)["jid"])
+	if err != nil {
+		return nil, &AppError{
+			Error:       errors.Wrap(err, "invalid jid in profile handler"),
+			ResponseMsg: "invalid jid: " + err.Error(),
+			Code:        http.StatusBadRequest,
+		}
+	}
+	profile, err := p.profileProvider.Profile(mux.Vars(r)["sessionID"], jid)
+	if err != nil {
+		appErr := &AppError{
+			Error:       errors.Wrap(err, "profile lookup error in profile handler"),
+			ResponseMsg: "profile lookup error",
+			Code:        http.StatusInternalServerError,
+		}
+		if _, ok := errors.Cause(err).(*service.NotFoundError); ok {
+			appErr.ResponseMsg = "session not registered"
+			appErr.Code = http.StatusBadRequest
+		}
+		return nil, appErr
+	}
+	return profile, nil
+}
+
+// ProfileHandler provides profile picture URL and status of WhatsApp account.
+type ProfileHandler struct {
+	profileReader
+}
+
+// NewProfileHandler creates ProfileHandler.
+func NewProfileHandler(profileProvider service.ProfileProvider, jidNormalizer *service.JidNormalizer) *ProfileHandler {
+	return &ProfileHandler{profileReader{profileProvider: profileProvider, jidNormalizer: jidNormalizer}}
+}
+
+// Handle sends profile of account.
+func (handler *ProfileHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	profile, appErr := handler.profile(r)
+	if appErr != nil {
+		return appErr
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(profile); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "profile encoding error in profile handler"),
+			ResponseMsg: "can't encode profile",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
+
+// ProfilePictureHandler proxies profile picture of WhatsApp account.
+type ProfilePictureHandler struct {
+	profileReader
+	httpClient httpInfra.Client
+}
+
+// NewProfilePictureHandler creates ProfilePictureHandler.
+func NewProfilePictureHandler(
+	profileProvider service.ProfileProvider,
+	jidNormalizer *service.JidNormalizer,
+	client httpInfra.Client,
+) *ProfilePictureHandler {
+	return &ProfilePictureHandler{
+		profileReader: profileReader{profileProvider: profileProvider, jidNormalizer: jidNormalizer},
+		httpClient:    client,
+	}
+}
+
+// Handle streams profile picture of account downloaded from WhatsApp.
+func (handler *ProfilePictureHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	profile, appErr := handler.profile(r)
+	if appErr != nil {
+		return appErr
+	}
+	if profile.PictureURL == "" {
+		return &AppError{
+			Error:       errors.Errorf("no profile picture of `%s` in profile picture handler", profile.Jid),
+			ResponseMsg: "profile picture not found",
+			Code:        http.StatusNotFound,
+		}
+	}
+
+	response, err := handler.httpClient.Get(profile.PictureURL)
+	if err == nil && response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		err = errors.Errorf("picture responded with status %d", response.StatusCode)
+	}
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "picture downloading error in profile picture handler"),
+			ResponseMsg: "can't download profile picture",
+			Code:        http.StatusBadGateway,
+		}
+	}
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			log.Printf("profile picture body closing error: %v\n", err)
+		}
+	}()
+
+	contentType := response.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "image/jpeg"
+	}
+	w.Header().Set("Content-Type", contentType)
+	if _, err = io.Copy(w, response.Body); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "unable to write profile picture in profile picture handler"),
+			ResponseMsg: "unable to write profile picture",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
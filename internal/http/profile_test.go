@@ -0,0 +1,127 @@
+package http_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	testHttp "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/golang/mock/gomock"
+	pkgErrors "github.com/pkg/errors"
+)
+
+func TestProfileHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		jid          string
+		lookupErr    error
+		expectLookup bool
+		expectStatus int
+	}{
+		{name: "OK", jid: "+375000000001", expectLookup: true, expectStatus: http.StatusOK},
+		{name: "Invalid jid", jid: "john", expectStatus: http.StatusBadRequest},
+		{
+			name:         "Session isn't connected",
+			jid:          "375000000001@s.whatsapp.net",
+			lookupErr:    pkgErrors.Wrap(&service.NotFoundError{SessionID: "_sid_"}, "can't find connection of session"),
+			expectLookup: true,
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Lookup error",
+			jid:          "375000000001@s.whatsapp.net",
+			lookupErr:    errors.New("timed out"),
+			expectLookup: true,
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			provider := mock.NewMockProfileProvider(c)
+			if tt.expectLookup {
+				var profile *model.Profile
+				if tt.lookupErr == nil {
+					profile = &model.Profile{Jid: "375000000001@s.whatsapp.net", PictureURL: "https://pps.whatsapp.net/p.jpg", Status: "Busy"}
+				}
+				provider.EXPECT().Profile("_sid_", "375000000001@s.whatsapp.net").Return(profile, tt.lookupErr)
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/profile/{sessionID}/{jid}/": internalHttp.NewProfileHandler(provider, service.NewJidNormalizer("")),
+			})
+			defer server.Close()
+
+			response := httpexpect.New(t, server.URL).GET("/profile/_sid_/{jid}/", tt.jid).Expect().Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusOK {
+				profile := response.JSON().Object()
+				profile.ValueEqual("jid", "375000000001@s.whatsapp.net")
+				profile.ValueEqual("picture_url", "https://pps.whatsapp.net/p.jpg")
+				profile.ValueEqual("status", "Busy")
+			}
+		})
+	}
+}
+
+func TestProfilePictureHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		pictureURL   string
+		expectGet    bool
+		getStatus    int
+		expectStatus int
+	}{
+		{name: "OK", pictureURL: "https://pps.whatsapp.net/p.jpg", expectGet: true, getStatus: http.StatusOK, expectStatus: http.StatusOK},
+		{name: "No picture", expectStatus: http.StatusNotFound},
+		{
+			name:         "Expired picture URL",
+			pictureURL:   "https://pps.whatsapp.net/p.jpg",
+			expectGet:    true,
+			getStatus:    http.StatusForbidden,
+			expectStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			provider := mock.NewMockProfileProvider(c)
+			provider.EXPECT().
+				Profile("_sid_", "375000000001@s.whatsapp.net").
+				Return(&model.Profile{Jid: "375000000001@s.whatsapp.net", PictureURL: tt.pictureURL}, nil)
+			client := mock.NewMockClient(c)
+			if tt.expectGet {
+				client.EXPECT().Get(tt.pictureURL).Return(&http.Response{
+					StatusCode: tt.getStatus,
+					Header:     http.Header{"Content-Type": []string{"image/jpeg"}},
+					Body:       ioutil.NopCloser(bytes.NewBufferString("jpeg")),
+				}, nil)
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/profile/{sessionID}/{jid}/picture/": internalHttp.NewProfilePictureHandler(provider, service.NewJidNormalizer(""), client),
+			})
+			defer server.Close()
+
+			response := httpexpect.New(t, server.URL).
+				GET("/profile/_sid_/375000000001@s.whatsapp.net/picture/").
+				Expect().
+				Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusOK {
+				response.ContentType("image/jpeg")
+				response.Body().Equal("jpeg")
+			}
+		})
+	}
+}
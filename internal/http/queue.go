@@ -0,0 +1,51 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/r-erema/wapi/internal/repository"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// QueuedMessageHandler provides status of message queued to be sent asynchronously.
+type QueuedMessageHandler struct {
+	queueRepo repository.Queue
+}
+
+// NewQueuedMessageHandler creates QueuedMessageHandler.
+func NewQueuedMessageHandler(queueRepo repository.Queue) *QueuedMessageHandler {
+	return &QueuedMessageHandler{queueRepo: queueRepo}
+}
+
+// Handle sends queued message info.
+func (handler *QueuedMessageHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	msgID := mux.Vars(r)["messageID"]
+	msg, err := handler.queueRepo.QueuedMessage(msgID)
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "queued message reading error in queued message handler"),
+			ResponseMsg: "queued message reading error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	if msg == nil {
+		return &AppError{
+			Error:       errors.Errorf("queued message `%s` not found in queued message handler", msgID),
+			ResponseMsg: "queued message not found",
+			Code:        http.StatusNotFound,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(msg); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "queued message encoding error in queued message handler"),
+			ResponseMsg: "can't encode queued message",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/model"
+	testHttp "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewQueuedMessageHandler(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	assert.NotNil(t, internalHttp.NewQueuedMessageHandler(mock.NewMockQueue(mockCtrl)))
+}
+
+func TestQueuedMessageHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		msg          *model.QueuedMessage
+		err          error
+		expectStatus int
+	}{
+		{
+			name:         "OK",
+			msg:          &model.QueuedMessage{ID: "_msg_id_", Status: model.RetryingStatus, Attempts: 2},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Message not found",
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "Internal server error",
+			err:          fmt.Errorf("something went wrong... "),
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			queueRepo := mock.NewMockQueue(mockCtrl)
+			queueRepo.EXPECT().QueuedMessage("_msg_id_").Return(tt.msg, tt.err)
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/get-queued-message/{messageID}/": internalHttp.NewQueuedMessageHandler(queueRepo),
+			})
+			defer server.Close()
+			expect := httpexpect.New(t, server.URL)
+
+			response := expect.GET("/get-queued-message/_msg_id_/").
+				Expect().
+				Status(tt.expectStatus)
+			if tt.msg != nil {
+				response.JSON().Object().
+					ValueEqual("status", model.RetryingStatus).
+					ValueEqual("attempts", 2)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// ScheduledMessageHandler provides status of message scheduled to be sent at specified time.
+type ScheduledMessageHandler struct {
+	scheduler service.Scheduler
+}
+
+// NewScheduledMessageHandler creates ScheduledMessageHandler.
+func NewScheduledMessageHandler(scheduler service.Scheduler) *ScheduledMessageHandler {
+	return &ScheduledMessageHandler{scheduler: scheduler}
+}
+
+// Handle sends scheduled message info.
+func (handler *ScheduledMessageHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	msgID := mux.Vars(r)["messageID"]
+	msg, err := handler.scheduler.ScheduledMessage(msgID)
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "scheduled message reading error in scheduled message handler"),
+			ResponseMsg: "scheduled message reading error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return writeScheduledMessage(w, msgID, msg)
+}
+
+// CancelScheduledMessageHandler cancels message scheduled to be sent at specified time.
+type CancelScheduledMessageHandler struct {
+	scheduler service.Scheduler
+}
+
+// NewCancelScheduledMessageHandler creates CancelScheduledMessageHandler.
+func NewCancelScheduledMessageHandler(scheduler service.Scheduler) *CancelScheduledMessageHandler {
+	return &CancelScheduledMessageHandler{scheduler: scheduler}
+}
+
+// Handle cancels scheduled message and writes it to response,
+// message already queued or cancelled can't be cancelled.
+func (handler *CancelScheduledMessageHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	msgID := mux.Vars(r)["messageID"]
+	msg, err := handler.scheduler.Cancel(msgID)
+	if err == service.ErrNotScheduled {
+		return &AppError{
+			Error:       errors.Wrapf(err, "cancellation of message `%s` in scheduled message handler", msgID),
+			ResponseMsg: "message is already " + msg.Status,
+			Code:        http.StatusConflict,
+		}
+	}
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "scheduled message cancellation error in scheduled message handler"),
+			ResponseMsg: "scheduled message cancellation error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return writeScheduledMessage(w, msgID, msg)
+}
+
+func writeScheduledMessage(w http.ResponseWriter, msgID string, msg *model.ScheduledMessage) *AppError {
+	if msg == nil {
+		return &AppError{
+			Error:       errors.Errorf("scheduled message `%s` not found in scheduled message handler", msgID),
+			ResponseMsg: "scheduled message not found",
+			Code:        http.StatusNotFound,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(msg); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "scheduled message encoding error in scheduled message handler"),
+			ResponseMsg: "can't encode scheduled message",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
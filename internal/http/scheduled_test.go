@@ -0,0 +1,88 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	testHttp "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/golang/mock/gomock"
+)
+
+func TestScheduledMessageHandlers_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		msg          *model.ScheduledMessage
+		err          error
+		expectStatus int
+	}{
+		{
+			name:         "Get OK",
+			method:       http.MethodGet,
+			msg:          &model.ScheduledMessage{ID: "_msg_id_", Status: model.ScheduledStatus},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Get not found",
+			method:       http.MethodGet,
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "Get error",
+			method:       http.MethodGet,
+			err:          errors.New("redis is down"),
+			expectStatus: http.StatusInternalServerError,
+		},
+		{
+			name:         "Cancel OK",
+			method:       http.MethodDelete,
+			msg:          &model.ScheduledMessage{ID: "_msg_id_", Status: model.CancelledStatus},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Cancel already queued",
+			method:       http.MethodDelete,
+			msg:          &model.ScheduledMessage{ID: "_msg_id_", Status: model.QueuedStatus},
+			err:          service.ErrNotScheduled,
+			expectStatus: http.StatusConflict,
+		},
+		{
+			name:         "Cancel not found",
+			method:       http.MethodDelete,
+			expectStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			scheduler := mock.NewMockScheduler(c)
+			var handler internalHttp.AppHTTPHandler
+			if tt.method == http.MethodGet {
+				scheduler.EXPECT().ScheduledMessage("_msg_id_").Return(tt.msg, tt.err)
+				handler = internalHttp.NewScheduledMessageHandler(scheduler)
+			} else {
+				scheduler.EXPECT().Cancel("_msg_id_").Return(tt.msg, tt.err)
+				handler = internalHttp.NewCancelScheduledMessageHandler(scheduler)
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{"/scheduled/{messageID}/": handler})
+			defer server.Close()
+
+			response := httpexpect.New(t, server.URL).Request(tt.method, "/scheduled/_msg_id_/").
+				Expect().
+				Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusOK {
+				response.JSON().Object().ValueEqual("status", tt.msg.Status)
+			}
+		})
+	}
+}
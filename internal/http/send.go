@@ -0,0 +1,569 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	httpInfra "github.com/r-erema/wapi/internal/infrastructure/http"
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	infrastructureWhatsapp "github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/pkg/errors"
+)
+
+// messageFactory builds WhatsApp message to be sent.
+type messageFactory func(info whatsapp.MessageInfo) (interface{}, *AppError)
+
+// protoMessage is a message sent to WhatsApp server as raw proto message,
+// it's used for features unsupported by message types of go-whatsapp package, e.g. mentions.
+type protoMessage interface {
+	Proto() *proto.WebMessageInfo
+}
+
+// mediaMessageFactory builds WhatsApp message containing media content.
+type mediaMessageFactory func(info whatsapp.MessageInfo, content *mediaContent) interface{}
+
+// SendMessageResponse is a response of send endpoints, id is an id of message assigned by WhatsApp,
+// it's used to correlate message with delivery status events.
+type SendMessageResponse struct {
+	ID        string `json:"id"`
+	SessionID string `json:"session_name"`
+	ChatID    string `json:"chat_id"`
+	Timestamp uint64 `json:"timestamp"`
+	Status    string `json:"status"`
+}
+
+// chatResolver is responsible for common steps of chat handlers: resolving connection of session and JID of chat.
+type chatResolver struct {
+	connectionsSupervisor service.Connections
+	jidNormalizer         *service.JidNormalizer
+	handlerName           string
+}
+
+// messageSender is responsible for common steps of sending messages:
+// resolving connection of session, sending message to WhatsApp server and writing its id and status to response.
+type messageSender struct {
+	chatResolver
+	msgRepo repository.Message
+	marshal *jsonInfra.MarshallCallback
+}
+
+func newMessageSender(
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+	msgRepo repository.Message,
+	marshal *jsonInfra.MarshallCallback,
+	messageName string,
+) *messageSender {
+	return &messageSender{
+		chatResolver: chatResolver{
+			connectionsSupervisor: connectionsSupervisor,
+			jidNormalizer:         jidNormalizer,
+			handlerName:           messageName,
+		},
+		msgRepo: msgRepo,
+		marshal: marshal,
+	}
+}
+
+func (s *messageSender) send(w http.ResponseWriter, sessionID, chatID string, buildMessage messageFactory) *AppError {
+	return s.sendTyping(w, sessionID, chatID, 0, buildMessage)
+}
+
+// sendTyping sends message after showing "typing..." in chat during typing duration, zero duration disables typing.
+func (s *messageSender) sendTyping(
+	w http.ResponseWriter,
+	sessionID,
+	chatID string,
+	typing time.Duration,
+	buildMessage messageFactory,
+) *AppError {
+	chatID, appErr := s.chatJid(chatID)
+	if appErr != nil {
+		return appErr
+	}
+	wac, appErr := s.connection(sessionID)
+	if appErr != nil {
+		return appErr
+	}
+	if typing > 0 {
+		simulateTyping(wac, chatID, typing)
+	}
+
+	info := whatsapp.MessageInfo{
+		Id:        infrastructureWhatsapp.NewMessageID(),
+		RemoteJid: chatID,
+		SenderJid: wac.Info().Wid,
+		Timestamp: uint64(time.Now().Unix()),
+	}
+	message, appErr := buildMessage(info)
+	if appErr != nil {
+		return appErr
+	}
+
+	outgoing := message
+	if protoMsg, ok := message.(protoMessage); ok {
+		outgoing = protoMsg.Proto()
+	}
+	msgID, err := wac.Send(outgoing)
+	if err != nil {
+		if limitErr, ok := err.(*service.RateLimitError); ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limitErr.RetryAfter.Seconds()))))
+			return &AppError{
+				Error:       errors.Wrapf(err, "sending message error in %s handler", s.handlerName),
+				ResponseMsg: "rate limit exceeded",
+				Code:        http.StatusTooManyRequests,
+			}
+		}
+		return &AppError{
+			Error:       errors.Wrapf(err, "sending message error in %s handler", s.handlerName),
+			ResponseMsg: "sending message error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	log.Printf("%s message sent to %s by session %s \n", s.handlerName, chatID, sessionID)
+	s.saveSentStatus(sessionID, msgID, chatID)
+	sent := &SendMessageResponse{
+		ID:        msgID,
+		SessionID: sessionID,
+		ChatID:    chatID,
+		Timestamp: info.Timestamp,
+		Status:    model.SentDeliveryStatus,
+	}
+	if err := s.writeMsgToResponse(sent, w); err != nil {
+		return &AppError{
+			Error:       errors.Wrapf(err, "error writing message to response in %s handler", s.handlerName),
+			ResponseMsg: "can't send " + s.handlerName,
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
+
+// schedule schedules message to be sent at requested time, id and status of scheduled message are written to response.
+func (s *messageSender) schedule(
+	w http.ResponseWriter,
+	scheduler service.Scheduler,
+	sessionID string,
+	message *proto.WebMessageInfo,
+	sendAt time.Time,
+) *AppError {
+	scheduledMsg, err := scheduler.Schedule(sessionID, message, sendAt)
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrapf(err, "scheduling message error in %s handler", s.handlerName),
+			ResponseMsg: "scheduling message error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+
+	return s.writeAccepted(w, &SendMessageResponse{
+		ID:        scheduledMsg.ID,
+		SessionID: scheduledMsg.SessionID,
+		ChatID:    scheduledMsg.ChatID,
+		Timestamp: uint64(scheduledMsg.SendAt.Unix()),
+		Status:    scheduledMsg.Status,
+	})
+}
+
+// writeAccepted writes response of message accepted to be sent later.
+func (s *messageSender) writeAccepted(w http.ResponseWriter, response *SendMessageResponse) *AppError {
+	marshal := *s.marshal
+	responseBody, err := marshal(response)
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrapf(err, "error message marshaling in %s handler", s.handlerName),
+			ResponseMsg: "error message marshaling",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if _, err = w.Write(responseBody); err != nil {
+		return &AppError{
+			Error:       errors.Wrapf(err, "can't write body to response in %s handler", s.handlerName),
+			ResponseMsg: "can't write body to response",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
+
+// newProtoMessage builds proto of outgoing message to chat, it's used for messages stored to be sent later.
+func newProtoMessage(chatID string, message *proto.Message) *proto.WebMessageInfo {
+	id := infrastructureWhatsapp.NewMessageID()
+	timestamp := uint64(time.Now().Unix())
+	fromMe := true
+	var status proto.WebMessageInfo_WEB_MESSAGE_INFO_STATUS // Status of new message is set the way go-whatsapp does.
+	return &proto.WebMessageInfo{
+		Key: &proto.MessageKey{
+			FromMe:    &fromMe,
+			RemoteJid: &chatID,
+			Id:        &id,
+		},
+		MessageTimestamp: &timestamp,
+		Status:           &status,
+		Message:          message,
+	}
+}
+
+// saveSentStatus stores initial delivery status of sent message, it isn't overwritten if ack of message is already received.
+func (s *messageSender) saveSentStatus(sessionID, msgID, chatID string) {
+	_, err := s.msgRepo.UpdateMessageStatus(sessionID, msgID, func(current *model.MessageStatus) *model.MessageStatus {
+		if current != nil {
+			return nil
+		}
+		status := model.NewMessageStatus(msgID, sessionID, chatID)
+		status.Advance(model.SentDeliveryStatus, time.Now())
+		return status
+	})
+	if err != nil {
+		log.Printf("can't save status of msg `%s` sent by session `%s`: %v\n", msgID, sessionID, err)
+	}
+}
+
+// connection resolves authenticated connection of session.
+func (r *chatResolver) connection(sessionID string) (infrastructureWhatsapp.Conn, *AppError) {
+	sessConnDTO, err := r.connectionsSupervisor.AuthenticatedConnectionForSession(sessionID)
+	if err != nil {
+		return nil, &AppError{
+			Error:       errors.Wrapf(err, "can't find session in %s handler", r.handlerName),
+			ResponseMsg: "session not registered",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	return sessConnDTO.Wac(), nil
+}
+
+// chatJid converts chat id of request, which is either JID or phone number, to JID.
+func (r *chatResolver) chatJid(chatID string) (string, *AppError) {
+	jid, err := r.jidNormalizer.Normalize(chatID)
+	if err != nil {
+		return "", &AppError{
+			Error:       errors.Wrapf(err, "invalid chat id in %s handler", r.handlerName),
+			ResponseMsg: "invalid chat_id: " + err.Error(),
+			Code:        http.StatusBadRequest,
+		}
+	}
+	return jid, nil
+}
+
+func (s *messageSender) writeMsgToResponse(msg interface{}, w http.ResponseWriter) error {
+	marshal := *s.marshal
+	responseBody, err := marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "error message marshaling")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(responseBody); err != nil {
+		return errors.Wrap(err, "can't write body to response")
+	}
+	return nil
+}
+
+// mediaFormFile is a name of multipart form part containing uploaded media file.
+const mediaFormFile = "file"
+
+// sniffLen is a number of bytes used to detect content type, see http.DetectContentType.
+const sniffLen = 512
+
+// mediaSource is a source of media content: either uploaded file, base64 encoded string or url.
+type mediaSource struct {
+	upload *multipart.Part
+	base64 string
+	url    string
+}
+
+// mediaContent is a media content of outgoing message, it's read into memory entirely
+// since go-whatsapp reads whole content anyway to encrypt it before uploading.
+type mediaContent struct {
+	*bytes.Reader
+	head     []byte // Beginning of content used for content type sniffing.
+	fileName string
+}
+
+// mediaSender sends messages containing media content read from its source.
+type mediaSender struct {
+	messageSender
+	httpClient httpInfra.Client
+	maxSize    int64
+}
+
+func newMediaSender(
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+	msgRepo repository.Message,
+	client httpInfra.Client,
+	marshal *jsonInfra.MarshallCallback,
+	mediaName string,
+	maxSize int64,
+) *mediaSender {
+	return &mediaSender{
+		messageSender: *newMessageSender(connectionsSupervisor, jidNormalizer, msgRepo, marshal, mediaName),
+		httpClient:    client,
+		maxSize:       maxSize,
+	}
+}
+
+// source resolves source of media content, exactly one of them must be set.
+func (s *mediaSender) source(upload *multipart.Part, base64Content, mediaURL string) (mediaSource, *AppError) {
+	sourcesCount := 0
+	for _, isSet := range []bool{upload != nil, base64Content != "", mediaURL != ""} {
+		if isSet {
+			sourcesCount++
+		}
+	}
+	if sourcesCount != 1 {
+		return mediaSource{}, &AppError{
+			Error:       errors.Errorf("%d sources of media are set in %s handler", sourcesCount, s.handlerName),
+			ResponseMsg: "exactly one of file, base64 content or url of " + s.handlerName + " must be set",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	return mediaSource{upload: upload, base64: base64Content, url: mediaURL}, nil
+}
+
+func (s *mediaSender) send(
+	w http.ResponseWriter,
+	sessionID,
+	chatID string,
+	source mediaSource,
+	buildMessage mediaMessageFactory,
+) *AppError {
+	var body io.ReadCloser
+	defer func() {
+		if body == nil {
+			return
+		}
+		if err := body.Close(); err != nil {
+			log.Printf("%s: %v\n", "media body closing error", err)
+		}
+	}()
+
+	return s.messageSender.send(w, sessionID, chatID, func(info whatsapp.MessageInfo) (interface{}, *AppError) {
+		var fileName string
+		var appErr *AppError
+		body, fileName, appErr = s.openMedia(source)
+		if appErr != nil {
+			return nil, appErr
+		}
+		content, appErr := s.readContent(body, fileName)
+		if appErr != nil {
+			return nil, appErr
+		}
+		return buildMessage(info, content), nil
+	})
+}
+
+func (s *mediaSender) openMedia(source mediaSource) (io.ReadCloser, string, *AppError) {
+	switch {
+	case source.upload != nil:
+		return source.upload, source.upload.FileName(), nil
+	case source.base64 != "":
+		return ioutil.NopCloser(base64.NewDecoder(base64.StdEncoding, strings.NewReader(source.base64))), "", nil
+	}
+
+	response, err := s.httpClient.Get(source.url)
+	if err != nil {
+		return nil, "", &AppError{
+			Error:       errors.Wrapf(err, "can't get %s by this url", s.handlerName),
+			ResponseMsg: s.handlerName + " url error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		_ = response.Body.Close()
+		return nil, "", &AppError{
+			Error:       errors.Errorf("%s url responded with status %d", s.handlerName, response.StatusCode),
+			ResponseMsg: fmt.Sprintf("%s url responded with status %d", s.handlerName, response.StatusCode),
+			Code:        http.StatusBadGateway,
+		}
+	}
+	return response.Body, urlFileName(source.url), nil
+}
+
+// readContent reads media content limited by max size of media.
+func (s *mediaSender) readContent(body io.Reader, fileName string) (*mediaContent, *AppError) {
+	content, err := ioutil.ReadAll(io.LimitReader(body, s.maxSize+1))
+	if isBodyTooLarge(err, body) {
+		return nil, s.tooLargeError()
+	}
+	if err != nil {
+		appErr := &AppError{
+			Error:       errors.Wrapf(err, "reading %s error in %s handler", s.handlerName, s.handlerName),
+			ResponseMsg: "reading " + s.handlerName + " error",
+			Code:        http.StatusInternalServerError,
+		}
+		if _, ok := err.(base64.CorruptInputError); ok {
+			appErr.ResponseMsg = "invalid base64 content of " + s.handlerName
+			appErr.Code = http.StatusBadRequest
+		}
+		return nil, appErr
+	}
+	if int64(len(content)) > s.maxSize {
+		return nil, s.tooLargeError()
+	}
+
+	head := content
+	if len(head) > sniffLen {
+		head = head[:sniffLen]
+	}
+	return &mediaContent{Reader: bytes.NewReader(content), head: head, fileName: fileName}, nil
+}
+
+// tooLargeError is an error of media exceeding max size of media.
+func (s *mediaSender) tooLargeError() *AppError {
+	return &AppError{
+		Error:       errors.Errorf("%s exceeds max size of %d bytes", s.handlerName, s.maxSize),
+		ResponseMsg: fmt.Sprintf("%s exceeds max size of %d bytes", s.handlerName, s.maxSize),
+		Code:        http.StatusRequestEntityTooLarge,
+	}
+}
+
+// isBodyTooLarge checks whether reading of request body failed since body exceeds limit of http.MaxBytesReader,
+// e.g. chunked body without content length. Readers of body like multipart one may replace error of the limit,
+// so the limited body is checked too: it keeps returning the error once the limit is exceeded.
+// The error has no type before Go 1.19, so it's recognized by message.
+func isBodyTooLarge(err error, body io.Reader) bool {
+	if err == nil {
+		return false
+	}
+	if strings.Contains(err.Error(), bodyTooLargeMsg) {
+		return true
+	}
+	_, bodyErr := body.Read(nil)
+	return bodyErr != nil && strings.Contains(bodyErr.Error(), bodyTooLargeMsg)
+}
+
+// bodyTooLargeMsg is a message of error of http.MaxBytesReader.
+const bodyTooLargeMsg = "http: request body too large"
+
+// maxRequestSize is a max size of request body: max size of media encoded in base64
+// and the room for other fields of request.
+func (s *mediaSender) maxRequestSize() int64 {
+	return int64(base64.StdEncoding.EncodedLen(int(s.maxSize))) + maxRequestFieldsSize
+}
+
+// maxRequestFieldsSize is a max size of fields of media sending request besides media content.
+const maxRequestFieldsSize = 64 * 1024
+
+// decodeRequest decodes request of media sending handler either from JSON body or from multipart form,
+// request body is limited by max size of media.
+// Form fields must precede the file part which is returned unread.
+func (s *mediaSender) decodeRequest(w http.ResponseWriter, r *http.Request, msgReq interface{}) (*multipart.Part, *AppError) {
+	if r.ContentLength > s.maxRequestSize() {
+		return nil, s.tooLargeError()
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxRequestSize())
+
+	upload, err := decodeMediaRequest(r, msgReq)
+	if isBodyTooLarge(err, r.Body) {
+		return nil, s.tooLargeError()
+	}
+	if err != nil {
+		return nil, &AppError{
+			Error:       errors.Wrapf(err, "can't decode request in %s handler", s.handlerName),
+			ResponseMsg: "can't decode request",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	return upload, nil
+}
+
+// decodeMediaRequest decodes request of media sending handler either from JSON body or from multipart form.
+// Form fields must precede the file part which is returned unread.
+func decodeMediaRequest(r *http.Request, msgReq interface{}) (*multipart.Part, error) {
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != "multipart/form-data" {
+		return nil, errors.Wrap(json.NewDecoder(r.Body).Decode(msgReq), "can't decode JSON request")
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read multipart request")
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "can't read multipart request part")
+		}
+		if part.FormName() == mediaFormFile {
+			return part, nil
+		}
+		value, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't read form field `%s`", part.FormName())
+		}
+		if err := setFormField(msgReq, part.FormName(), string(value)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// setFormField sets field of request struct tagged by JSON name of form field, unknown fields are skipped.
+func setFormField(msgReq interface{}, name, value string) error {
+	request := reflect.ValueOf(msgReq).Elem()
+	for i := 0; i < request.NumField(); i++ {
+		if strings.Split(request.Type().Field(i).Tag.Get("json"), ",")[0] != name {
+			continue
+		}
+		field := request.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Bool:
+			flag, err := strconv.ParseBool(value)
+			if err != nil {
+				return errors.Wrapf(err, "invalid value of form field `%s`", name)
+			}
+			field.SetBool(flag)
+		default:
+			return errors.Errorf("unsupported type of form field `%s`", name)
+		}
+		return nil
+	}
+	return nil
+}
+
+// detectMimeType resolves mime type of media content: explicitly requested type has priority,
+// then type is resolved by file extension and finally by content itself.
+func detectMimeType(content []byte, fileName, requestedType string) string {
+	if requestedType != "" {
+		return requestedType
+	}
+	if mimeType := mime.TypeByExtension(path.Ext(fileName)); mimeType != "" {
+		return mimeType
+	}
+	return http.DetectContentType(content)
+}
+
+// urlFileName resolves name of media file by its url.
+func urlFileName(mediaURL string) string {
+	name := path.Base(strings.SplitN(mediaURL, "?", 2)[0])
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectMimeType(t *testing.T) {
+	pdf := []byte("%PDF-1.4")
+	assert.Equal(t, "application/custom", detectMimeType(pdf, "invoice.pdf", "application/custom"))
+	assert.Equal(t, "application/pdf", detectMimeType([]byte("text"), "invoice.pdf", ""))
+	assert.Equal(t, "application/pdf", detectMimeType(pdf, "invoice", ""))
+}
+
+func TestURLFileName(t *testing.T) {
+	assert.Equal(t, "invoice.pdf", urlFileName("https://host/files/invoice.pdf?token=secret"))
+	assert.Equal(t, "", urlFileName(""))
+}
+
+func TestSetFormField(t *testing.T) {
+	var request SendAudioRequest
+	require.Nil(t, setFormField(&request, "chat_id", "+000000000000"))
+	require.Nil(t, setFormField(&request, "ptt", "true"))
+	require.Nil(t, setFormField(&request, "unknown", "value"))
+	assert.Equal(t, SendAudioRequest{ChatID: "+000000000000", Ptt: true}, request)
+	assert.NotNil(t, setFormField(&request, "ptt", "not bool"))
+}
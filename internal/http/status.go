@@ -0,0 +1,51 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/r-erema/wapi/internal/repository"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// MessageStatusHandler provides delivery status of message sent by session.
+type MessageStatusHandler struct {
+	msgRepo repository.Message
+}
+
+// NewMessageStatusHandler creates MessageStatusHandler.
+func NewMessageStatusHandler(msgRepo repository.Message) *MessageStatusHandler {
+	return &MessageStatusHandler{msgRepo: msgRepo}
+}
+
+// Handle sends delivery status of message.
+func (handler *MessageStatusHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	vars := mux.Vars(r)
+	status, err := handler.msgRepo.MessageStatus(vars["sessionID"], vars["messageID"])
+	if err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "message status reading error in message status handler"),
+			ResponseMsg: "message status reading error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	if status == nil {
+		return &AppError{
+			Error:       errors.Errorf("status of message `%s` not found in message status handler", vars["messageID"]),
+			ResponseMsg: "message status not found",
+			Code:        http.StatusNotFound,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(status); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "message status encoding error in message status handler"),
+			ResponseMsg: "can't encode message status",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
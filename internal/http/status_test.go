@@ -0,0 +1,75 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/model"
+	testHttp "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMessageStatusHandler(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	assert.NotNil(t, internalHttp.NewMessageStatusHandler(mock.NewMockMessage(mockCtrl)))
+}
+
+func TestMessageStatusHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       *model.MessageStatus
+		err          error
+		expectStatus int
+	}{
+		{
+			name: "OK",
+			status: &model.MessageStatus{
+				ID:         "_msg_id_",
+				SessionID:  "_sid_",
+				Status:     model.ReadDeliveryStatus,
+				Timestamps: map[string]time.Time{model.ReadDeliveryStatus: time.Now()},
+			},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Status not found",
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "Internal server error",
+			err:          fmt.Errorf("something went wrong... "),
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			msgRepo := mock.NewMockMessage(mockCtrl)
+			msgRepo.EXPECT().MessageStatus("_sid_", "_msg_id_").Return(tt.status, tt.err)
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/messages/{sessionID}/{messageID}/status/": internalHttp.NewMessageStatusHandler(msgRepo),
+			})
+			defer server.Close()
+			expect := httpexpect.New(t, server.URL)
+
+			response := expect.GET("/messages/_sid_/_msg_id_/status/").
+				Expect().
+				Status(tt.expectStatus)
+			if tt.status != nil {
+				response.JSON().Object().
+					ValueEqual("status", model.ReadDeliveryStatus).
+					Value("timestamps").Object().ContainsKey(model.ReadDeliveryStatus)
+			}
+		})
+	}
+}
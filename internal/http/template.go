@@ -0,0 +1,260 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// CreateTemplateHandler is responsible for creating text message templates.
+type CreateTemplateHandler struct {
+	templateRepo repository.Template
+}
+
+// NewCreateTemplateHandler creates CreateTemplateHandler.
+func NewCreateTemplateHandler(templateRepo repository.Template) *CreateTemplateHandler {
+	return &CreateTemplateHandler{templateRepo: templateRepo}
+}
+
+// Handle creates template and writes it to response.
+func (handler *CreateTemplateHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	template, appErr := decodeTemplate(r)
+	if appErr != nil {
+		return appErr
+	}
+	existing, err := handler.templateRepo.Template(template.ID)
+	if err != nil {
+		return templateReadingError(err)
+	}
+	if existing != nil {
+		return &AppError{
+			Error:       errors.Errorf("template `%s` already exists in template handler", template.ID),
+			ResponseMsg: "template already exists",
+			Code:        http.StatusConflict,
+		}
+	}
+
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = template.CreatedAt
+	return saveTemplate(w, handler.templateRepo, template, http.StatusCreated)
+}
+
+// UpdateTemplateHandler is responsible for updating text message templates.
+type UpdateTemplateHandler struct {
+	templateRepo repository.Template
+}
+
+// NewUpdateTemplateHandler creates UpdateTemplateHandler.
+func NewUpdateTemplateHandler(templateRepo repository.Template) *UpdateTemplateHandler {
+	return &UpdateTemplateHandler{templateRepo: templateRepo}
+}
+
+// Handle replaces template and writes it to response.
+func (handler *UpdateTemplateHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	existing, appErr := findTemplate(handler.templateRepo, mux.Vars(r)["templateID"])
+	if appErr != nil {
+		return appErr
+	}
+	template, appErr := decodeTemplate(r)
+	if appErr != nil {
+		return appErr
+	}
+	if template.ID != existing.ID {
+		return &AppError{
+			Error:       errors.Errorf("template id `%s` differs from `%s` in template handler", template.ID, existing.ID),
+			ResponseMsg: "template id can't be changed",
+			Code:        http.StatusBadRequest,
+		}
+	}
+
+	template.CreatedAt = existing.CreatedAt
+	template.UpdatedAt = time.Now()
+	return saveTemplate(w, handler.templateRepo, template, http.StatusOK)
+}
+
+// TemplateHandler provides text message template.
+type TemplateHandler struct {
+	templateRepo repository.Template
+}
+
+// NewTemplateHandler creates TemplateHandler.
+func NewTemplateHandler(templateRepo repository.Template) *TemplateHandler {
+	return &TemplateHandler{templateRepo: templateRepo}
+}
+
+// Handle sends template.
+func (handler *TemplateHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	template, appErr := findTemplate(handler.templateRepo, mux.Vars(r)["templateID"])
+	if appErr != nil {
+		return appErr
+	}
+	return writeTemplateJSON(w, http.StatusOK, template)
+}
+
+// TemplatesHandler provides all text message templates.
+type TemplatesHandler struct {
+	templateRepo repository.Template
+}
+
+// NewTemplatesHandler creates TemplatesHandler.
+func NewTemplatesHandler(templateRepo repository.Template) *TemplatesHandler {
+	return &TemplatesHandler{templateRepo: templateRepo}
+}
+
+// Handle sends all templates.
+func (handler *TemplatesHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	templates, err := handler.templateRepo.AllTemplates()
+	if err != nil {
+		return templateReadingError(err)
+	}
+	return writeTemplateJSON(w, http.StatusOK, templates)
+}
+
+// RemoveTemplateHandler is responsible for removing text message templates.
+type RemoveTemplateHandler struct {
+	templateRepo repository.Template
+}
+
+// NewRemoveTemplateHandler creates RemoveTemplateHandler.
+func NewRemoveTemplateHandler(templateRepo repository.Template) *RemoveTemplateHandler {
+	return &RemoveTemplateHandler{templateRepo: templateRepo}
+}
+
+// Handle removes template.
+func (handler *RemoveTemplateHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	template, appErr := findTemplate(handler.templateRepo, mux.Vars(r)["templateID"])
+	if appErr != nil {
+		return appErr
+	}
+	if err := handler.templateRepo.RemoveTemplate(template.ID); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "template removing error in template handler"),
+			ResponseMsg: "template removing error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// findTemplate retrieves template, AppError with 404 status is returned if template isn't found.
+func findTemplate(templateRepo repository.Template, templateID string) (*model.Template, *AppError) {
+	template, err := templateRepo.Template(templateID)
+	if err != nil {
+		return nil, templateReadingError(err)
+	}
+	if template == nil {
+		return nil, &AppError{
+			Error:       errors.Errorf("template `%s` not found in template handler", templateID),
+			ResponseMsg: "template not found",
+			Code:        http.StatusNotFound,
+		}
+	}
+	return template, nil
+}
+
+func decodeTemplate(r *http.Request) (*model.Template, *AppError) {
+	template := &model.Template{}
+	if err := json.NewDecoder(r.Body).Decode(template); err != nil {
+		return nil, &AppError{
+			Error:       errors.Wrap(err, "decoding error in template handler"),
+			ResponseMsg: "can't decode request",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	if err := template.Validate(); err != nil {
+		return nil, &AppError{
+			Error:       errors.Wrap(err, "invalid template in template handler"),
+			ResponseMsg: "invalid template: " + err.Error(),
+			Code:        http.StatusBadRequest,
+		}
+	}
+	return template, nil
+}
+
+func saveTemplate(w http.ResponseWriter, templateRepo repository.Template, template *model.Template, status int) *AppError {
+	if err := templateRepo.SaveTemplate(template); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "template saving error in template handler"),
+			ResponseMsg: "template saving error",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return writeTemplateJSON(w, status, template)
+}
+
+func templateReadingError(err error) *AppError {
+	return &AppError{
+		Error:       errors.Wrap(err, "template reading error in template handler"),
+		ResponseMsg: "template reading error",
+		Code:        http.StatusInternalServerError,
+	}
+}
+
+func writeTemplateJSON(w http.ResponseWriter, status int, body interface{}) *AppError {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		return &AppError{
+			Error:       errors.Wrap(err, "template encoding error in template handler"),
+			ResponseMsg: "can't encode template",
+			Code:        http.StatusInternalServerError,
+		}
+	}
+	return nil
+}
+
+// messageText resolves text of message: text of template rendered with variables if template is set,
+// otherwise the text itself.
+func messageText(templateRepo repository.Template, text, templateID, locale string, vars map[string]string) (string, *AppError) {
+	template, appErr := messageTemplate(templateRepo, text, templateID)
+	if appErr != nil || template == nil {
+		return text, appErr
+	}
+	return renderText(template.Text(locale), vars)
+}
+
+// messageTemplate retrieves template message refers to, nil is returned if message has no template.
+func messageTemplate(templateRepo repository.Template, text, templateID string) (*model.Template, *AppError) {
+	if templateID == "" {
+		return nil, nil
+	}
+	if text != "" {
+		return nil, &AppError{
+			Error:       errors.New("both text and template are set"),
+			ResponseMsg: "either text or template_id must be set",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	template, err := templateRepo.Template(templateID)
+	if err != nil {
+		return nil, templateReadingError(err)
+	}
+	if template == nil {
+		return nil, &AppError{
+			Error:       errors.Errorf("template `%s` not found", templateID),
+			ResponseMsg: "template not found",
+			Code:        http.StatusBadRequest,
+		}
+	}
+	return template, nil
+}
+
+// renderText renders text as Go template with variables.
+func renderText(text string, vars map[string]string) (string, *AppError) {
+	rendered, err := model.RenderText(text, vars)
+	if err != nil {
+		return "", &AppError{
+			Error:       errors.Wrap(err, "rendering text error"),
+			ResponseMsg: "can't render text: " + errors.Cause(err).Error(),
+			Code:        http.StatusBadRequest,
+		}
+	}
+	return rendered, nil
+}
@@ -0,0 +1,132 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	"github.com/r-erema/wapi/internal/model"
+	testHttp "github.com/r-erema/wapi/internal/testutil/http"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func orderReadyTemplate() *model.Template {
+	return &model.Template{
+		ID:            "order_ready",
+		Name:          "Order is ready",
+		DefaultLocale: "en",
+		Locales:       map[string]string{"en": "Order {{.order}} is ready", "ru": "Заказ {{.order}} готов"},
+	}
+}
+
+func TestCreateTemplateHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		template     *model.Template
+		existing     *model.Template
+		expectStatus int
+	}{
+		{name: "OK", template: orderReadyTemplate(), expectStatus: http.StatusCreated},
+		{name: "Already exists", template: orderReadyTemplate(), existing: orderReadyTemplate(), expectStatus: http.StatusConflict},
+		{
+			name:         "Invalid template",
+			template:     &model.Template{ID: "order_ready", DefaultLocale: "en", Locales: map[string]string{"en": "Order {{.order"}},
+			expectStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			templateRepo := mock.NewMockTemplate(c)
+			if tt.expectStatus != http.StatusBadRequest {
+				templateRepo.EXPECT().Template("order_ready").Return(tt.existing, nil)
+			}
+			if tt.expectStatus == http.StatusCreated {
+				templateRepo.EXPECT().SaveTemplate(gomock.Any()).DoAndReturn(func(template *model.Template) error {
+					assert.False(t, template.CreatedAt.IsZero())
+					return nil
+				})
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/templates/": internalHttp.NewCreateTemplateHandler(templateRepo),
+			})
+			defer server.Close()
+
+			response := httpexpect.New(t, server.URL).POST("/templates/").
+				WithJSON(tt.template).
+				Expect().
+				Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusCreated {
+				response.JSON().Object().ValueEqual("id", "order_ready")
+			}
+		})
+	}
+}
+
+func TestUpdateTemplateHandler_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		templateID   string
+		existing     *model.Template
+		expectStatus int
+	}{
+		{name: "OK", templateID: "order_ready", existing: orderReadyTemplate(), expectStatus: http.StatusOK},
+		{name: "Not found", templateID: "order_ready", expectStatus: http.StatusNotFound},
+		{name: "Id changed", templateID: "payment_due", existing: &model.Template{ID: "payment_due"}, expectStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			templateRepo := mock.NewMockTemplate(c)
+			templateRepo.EXPECT().Template(tt.templateID).Return(tt.existing, nil)
+			if tt.expectStatus == http.StatusOK {
+				templateRepo.EXPECT().SaveTemplate(gomock.Any())
+			}
+
+			server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+				"/templates/{templateID}/": internalHttp.NewUpdateTemplateHandler(templateRepo),
+			})
+			defer server.Close()
+
+			httpexpect.New(t, server.URL).PUT("/templates/" + tt.templateID + "/").
+				WithJSON(orderReadyTemplate()).
+				Expect().
+				Status(tt.expectStatus)
+		})
+	}
+}
+
+func TestTemplateHandlers_ServeHTTP(t *testing.T) {
+	c := gomock.NewController(t)
+	templateRepo := mock.NewMockTemplate(c)
+	templateRepo.EXPECT().AllTemplates().Return([]*model.Template{orderReadyTemplate()}, nil)
+	templateRepo.EXPECT().Template("order_ready").Return(orderReadyTemplate(), nil).Times(2)
+	templateRepo.EXPECT().Template("unknown").Return(nil, nil)
+	templateRepo.EXPECT().Template("broken").Return(nil, errors.New("permission denied"))
+	templateRepo.EXPECT().RemoveTemplate("order_ready")
+
+	server := testHttp.New(map[string]internalHttp.AppHTTPHandler{
+		"/templates/":              internalHttp.NewTemplatesHandler(templateRepo),
+		"/templates/{templateID}/": internalHttp.NewTemplateHandler(templateRepo),
+		"/remove/{templateID}/":    internalHttp.NewRemoveTemplateHandler(templateRepo),
+	})
+	defer server.Close()
+	expect := httpexpect.New(t, server.URL)
+
+	expect.GET("/templates/").Expect().Status(http.StatusOK).JSON().Array().Length().Equal(1)
+	expect.GET("/templates/order_ready/").Expect().Status(http.StatusOK).
+		JSON().Object().Path("$.locales.ru").Equal("Заказ {{.order}} готов")
+	expect.GET("/templates/unknown/").Expect().Status(http.StatusNotFound)
+	expect.GET("/templates/broken/").Expect().Status(http.StatusInternalServerError)
+	expect.DELETE("/remove/order_ready/").Expect().Status(http.StatusNoContent)
+}
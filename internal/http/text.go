@@ -2,30 +2,46 @@ package http
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
+	"time"
 
-	"github.com/pkg/errors"
 	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	infrastructureWhatsapp "github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+	"github.com/r-erema/wapi/internal/repository"
 	"github.com/r-erema/wapi/internal/service"
 
 	"github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/pkg/errors"
 )
 
 // SendTextMessageHandler is responsible for sending text messages.
 type SendTextMessageHandler struct {
-	auth                  service.Authorizer
-	connectionsSupervisor service.Connections
-	marshal               *jsonInfra.MarshallCallback
+	auth         service.Authorizer
+	sender       *messageSender
+	queue        service.Enqueuer
+	scheduler    service.Scheduler
+	templateRepo repository.Template
 }
 
-// NewTextHandler creates SendTextMessageHandler.j'
+// NewTextHandler creates SendTextMessageHandler.
 func NewTextHandler(
 	authorizer service.Authorizer,
 	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+	msgRepo repository.Message,
+	queue service.Enqueuer,
+	scheduler service.Scheduler,
+	templateRepo repository.Template,
 	marshal *jsonInfra.MarshallCallback,
 ) *SendTextMessageHandler {
-	return &SendTextMessageHandler{auth: authorizer, connectionsSupervisor: connectionsSupervisor, marshal: marshal}
+	return &SendTextMessageHandler{
+		auth:         authorizer,
+		sender:       newMessageSender(connectionsSupervisor, jidNormalizer, msgRepo, marshal, "text"),
+		queue:        queue,
+		scheduler:    scheduler,
+		templateRepo: templateRepo,
+	}
 }
 
 // Handle sends text message to WhatsApp server.
@@ -41,56 +57,152 @@ func (handler *SendTextMessageHandler) Handle(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	sessConnDTO, err := handler.connectionsSupervisor.AuthenticatedConnectionForSession(msgReq.SessionID)
-	if err != nil {
+	text, appErr := messageText(handler.templateRepo, msgReq.Text, msgReq.TemplateID, msgReq.Locale, msgReq.Vars)
+	if appErr != nil {
+		return appErr
+	}
+	msgReq.Text = text
+
+	if msgReq.ChatID, appErr = handler.sender.chatJid(msgReq.ChatID); appErr != nil {
+		return appErr
+	}
+	if msgReq.Typing && (msgReq.SendAt != nil || msgReq.Async) {
 		return &AppError{
-			Error:       errors.Wrap(err, "session not registered in text handler"),
-			ResponseMsg: "session not registered",
+			Error:       errors.New("typing of async message requested in text handler"),
+			ResponseMsg: "typing is supported only by messages sent immediately",
 			Code:        http.StatusBadRequest,
 		}
 	}
-	wac := sessConnDTO.Wac()
-
-	message := whatsapp.TextMessage{
-		Info: whatsapp.MessageInfo{
-			RemoteJid: msgReq.ChatID,
-			SenderJid: wac.Info().Wid,
-		},
-		Text: msgReq.Text,
+	if msgReq.SendAt != nil {
+		return handler.schedule(w, &msgReq)
+	}
+	if msgReq.Async {
+		return handler.enqueue(w, &msgReq)
 	}
 
-	if _, err = wac.Send(message); err != nil {
-		return &AppError{
-			Error:       errors.Wrap(err, "sending message error in text handler"),
-			ResponseMsg: "sending message error",
-			Code:        http.StatusInternalServerError,
-		}
+	var typing time.Duration
+	if msgReq.Typing {
+		typing = typingDuration(msgReq.Text)
 	}
-	log.Printf("message sent to %s by session %s \n", msgReq.ChatID, msgReq.SessionID)
-	marshal := *handler.marshal
-	responseBody, err := marshal(&message)
+	return handler.sender.sendTyping(w, msgReq.SessionID, msgReq.ChatID, typing, func(info whatsapp.MessageInfo) (interface{}, *AppError) {
+		if msgReq.QuotedMessageID == "" && len(msgReq.Mentions) == 0 {
+			return whatsapp.TextMessage{Info: info, Text: msgReq.Text}, nil
+		}
+		return newReplyMessage(info, &msgReq), nil
+	})
+}
+
+// enqueue queues message to be sent asynchronously, id and status of queued message are written to response.
+func (handler *SendTextMessageHandler) enqueue(w http.ResponseWriter, msgReq *SendMessageRequest) *AppError {
+	message := newReplyMessage(whatsapp.MessageInfo{RemoteJid: msgReq.ChatID}, msgReq)
+	queuedMsg, err := handler.queue.Enqueue(msgReq.SessionID, message.Proto())
 	if err != nil {
 		return &AppError{
-			Error:       errors.Wrap(err, "error message marshaling in text handler"),
-			ResponseMsg: "error message marshaling",
+			Error:       errors.Wrap(err, "enqueueing message error in text handler"),
+			ResponseMsg: "enqueueing message error",
 			Code:        http.StatusInternalServerError,
 		}
 	}
-	w.Header().Set("Content-Type", "application/json")
-	if _, err = w.Write(responseBody); err != nil {
-		return &AppError{
-			Error:       errors.Wrap(err, "can't write body to response in text handler"),
-			ResponseMsg: "can't write body to response",
-			Code:        http.StatusInternalServerError,
+
+	return handler.sender.writeAccepted(w, &SendMessageResponse{
+		ID:        queuedMsg.ID,
+		SessionID: queuedMsg.SessionID,
+		ChatID:    queuedMsg.ChatID,
+		Timestamp: message.Info.Timestamp,
+		Status:    queuedMsg.Status,
+	})
+}
+
+// schedule schedules message to be sent at requested time.
+func (handler *SendTextMessageHandler) schedule(w http.ResponseWriter, msgReq *SendMessageRequest) *AppError {
+	message := newReplyMessage(whatsapp.MessageInfo{RemoteJid: msgReq.ChatID}, msgReq)
+	return handler.sender.schedule(w, handler.scheduler, msgReq.SessionID, message.Proto(), *msgReq.SendAt)
+}
+
+// replyMessage is a text message quoting other message and mentioning chat participants.
+// It's sent as proto message since whatsapp.TextMessage doesn't support mentions.
+type replyMessage struct {
+	whatsapp.TextMessage
+	Mentions []string
+}
+
+func newReplyMessage(info whatsapp.MessageInfo, msgReq *SendMessageRequest) replyMessage {
+	message := whatsapp.TextMessage{Info: info, Text: msgReq.Text}
+	if message.Info.Id == "" {
+		message.Info.Id = infrastructureWhatsapp.NewMessageID()
+	}
+	if message.Info.Timestamp == 0 {
+		message.Info.Timestamp = uint64(time.Now().Unix())
+	}
+	message.Info.FromMe = true
+	if msgReq.QuotedMessageID != "" {
+		participant := msgReq.QuotedParticipant
+		if participant == "" {
+			participant = msgReq.ChatID
+		}
+		message.ContextInfo = whatsapp.ContextInfo{
+			QuotedMessageID: msgReq.QuotedMessageID,
+			QuotedMessage:   &proto.Message{Conversation: &msgReq.QuotedText},
+			Participant:     participant,
 		}
 	}
+	return replyMessage{TextMessage: message, Mentions: msgReq.Mentions}
+}
 
-	return nil
+// Proto builds proto message of reply, it contains extended text with context info of quote and mentions
+// or plain text if there is neither quote nor mentions.
+func (m replyMessage) Proto() *proto.WebMessageInfo {
+	info := m.Info
+	status := proto.WebMessageInfo_WEB_MESSAGE_INFO_STATUS(info.Status)
+	text := m.Text
+	message := &proto.WebMessageInfo{
+		Key: &proto.MessageKey{
+			FromMe:    &info.FromMe,
+			RemoteJid: &info.RemoteJid,
+			Id:        &info.Id,
+		},
+		MessageTimestamp: &info.Timestamp,
+		Status:           &status,
+		Message:          &proto.Message{Conversation: &text},
+	}
+	if m.ContextInfo.QuotedMessageID == "" && len(m.Mentions) == 0 {
+		return message
+	}
+
+	contextInfo := &proto.ContextInfo{MentionedJid: m.Mentions}
+	if m.ContextInfo.QuotedMessageID != "" {
+		contextInfo.StanzaId = &m.ContextInfo.QuotedMessageID
+		contextInfo.Participant = &m.ContextInfo.Participant
+		contextInfo.QuotedMessage = m.ContextInfo.QuotedMessage
+	}
+	message.Message = &proto.Message{
+		ExtendedTextMessage: &proto.ExtendedTextMessage{
+			Text:        &text,
+			ContextInfo: contextInfo,
+		},
+	}
+	return message
 }
 
 // SendMessageRequest is the request for sending text message to WhatsApp.
+// Message replies to quoted message if its id is set, participant of quoted message is required in group chats,
+// mentions are JIDs of chat participants mentioned in text by @phone.
+// Async flag makes message to be queued and sent in background with retries,
+// message with sending time is scheduled and queued when the time comes.
+// Instead of text message may refer to template, its text of locale is rendered with variables.
+// Typing flag shows "typing..." in chat during time proportional to text length before sending.
 type SendMessageRequest struct {
-	ChatID    string `json:"chat_id"`
-	Text      string `json:"text"`
-	SessionID string `json:"session_name"`
+	ChatID            string            `json:"chat_id"`
+	Text              string            `json:"text"`
+	TemplateID        string            `json:"template_id"`
+	Locale            string            `json:"locale"`
+	Vars              map[string]string `json:"vars"`
+	SessionID         string            `json:"session_name"`
+	QuotedMessageID   string            `json:"quoted_message_id"`
+	QuotedParticipant string            `json:"quoted_participant"`
+	QuotedText        string            `json:"quoted_text"`
+	Mentions          []string          `json:"mentions"`
+	Async             bool              `json:"async"`
+	SendAt            *time.Time        `json:"send_at"`
+	Typing            bool              `json:"typing"`
 }
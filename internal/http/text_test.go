@@ -7,15 +7,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	internalHttp "github.com/r-erema/wapi/internal/http"
 	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
 	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
 	"github.com/r-erema/wapi/internal/service"
 	httpTest "github.com/r-erema/wapi/internal/testutil/http"
 	"github.com/r-erema/wapi/internal/testutil/mock"
 
 	"github.com/Rhymen/go-whatsapp"
+	waProto "github.com/Rhymen/go-whatsapp/binary/proto"
 	"github.com/gavv/httpexpect"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -23,14 +26,14 @@ import (
 )
 
 func TestNewTextHandler(t *testing.T) {
-	handler := internalHttp.NewTextHandler(mocksTextHandler(t))
+	handler := textHandler(t)(mocksTextHandler(t))
 	assert.NotNil(t, handler)
 }
 
 func TestSendTextMessageHandler_ServeHTTP(t *testing.T) {
 	tests := []struct {
 		name         string
-		mocksFactory func(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections, *jsonInfra.MarshallCallback)
+		mocksFactory func(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections, *service.JidNormalizer, *mock.MockMessage, *jsonInfra.MarshallCallback)
 		jsonRequest  func() interface{}
 		expectStatus int
 	}{
@@ -48,24 +51,32 @@ func TestSendTextMessageHandler_ServeHTTP(t *testing.T) {
 			},
 			expectStatus: http.StatusBadRequest,
 		},
+		{
+			name:         "Invalid chat id",
+			mocksFactory: mocksTextHandler,
+			jsonRequest: func() interface{} {
+				return &internalHttp.SendMessageRequest{ChatID: "+375 44 CALL-ME", Text: "Hello", SessionID: "_sid_"}
+			},
+			expectStatus: http.StatusBadRequest,
+		},
 		{
 			name: "Connection not found",
-			mocksFactory: func(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections, *jsonInfra.MarshallCallback) {
-				authorizer, _, marshal := mocksTextHandler(t)
+			mocksFactory: func(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections, *service.JidNormalizer, *mock.MockMessage, *jsonInfra.MarshallCallback) {
+				authorizer, _, jidNormalizer, msgRepo, marshal := mocksTextHandler(t)
 				c := gomock.NewController(t)
 				connections := mock.NewMockConnections(c)
 				connections.EXPECT().
 					AuthenticatedConnectionForSession(gomock.Any()).
 					Return(nil, &service.NotFoundError{})
-				return authorizer, connections, marshal
+				return authorizer, connections, jidNormalizer, msgRepo, marshal
 			},
 			jsonRequest:  messageRequest,
 			expectStatus: http.StatusBadRequest,
 		},
 		{
 			name: "Error message sending",
-			mocksFactory: func(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections, *jsonInfra.MarshallCallback) {
-				authorizer, _, marshal := mocksTextHandler(t)
+			mocksFactory: func(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections, *service.JidNormalizer, *mock.MockMessage, *jsonInfra.MarshallCallback) {
+				authorizer, _, jidNormalizer, msgRepo, marshal := mocksTextHandler(t)
 				c := gomock.NewController(t)
 				wac := mock.NewMockConn(c)
 				wac.EXPECT().Info().Return(&whatsapp.Info{Wid: "wid"})
@@ -76,19 +87,38 @@ func TestSendTextMessageHandler_ServeHTTP(t *testing.T) {
 					AuthenticatedConnectionForSession(gomock.Any()).
 					Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
 
-				return authorizer, connections, marshal
+				return authorizer, connections, jidNormalizer, msgRepo, marshal
 			},
 			jsonRequest:  messageRequest,
 			expectStatus: http.StatusInternalServerError,
 		},
+		{
+			name: "Rate limit exceeded",
+			mocksFactory: func(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections, *service.JidNormalizer, *mock.MockMessage, *jsonInfra.MarshallCallback) {
+				authorizer, _, jidNormalizer, msgRepo, marshal := mocksTextHandler(t)
+				c := gomock.NewController(t)
+				wac := mock.NewMockConn(c)
+				wac.EXPECT().Info().Return(&whatsapp.Info{Wid: "wid"})
+				wac.EXPECT().Send(gomock.Any()).Return("", &service.RateLimitError{RetryAfter: time.Millisecond * 1500})
+
+				connections := mock.NewMockConnections(c)
+				connections.EXPECT().
+					AuthenticatedConnectionForSession(gomock.Any()).
+					Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+
+				return authorizer, connections, jidNormalizer, msgRepo, marshal
+			},
+			jsonRequest:  messageRequest,
+			expectStatus: http.StatusTooManyRequests,
+		},
 		{
 			name: "Response marshaling error",
-			mocksFactory: func(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections, *jsonInfra.MarshallCallback) {
-				authorizer, connections, _ := mocksTextHandler(t)
+			mocksFactory: func(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections, *service.JidNormalizer, *mock.MockMessage, *jsonInfra.MarshallCallback) {
+				authorizer, connections, jidNormalizer, msgRepo, _ := mocksTextHandler(t)
 				marshal := jsonInfra.MarshallCallback(func(i interface{}) ([]byte, error) {
 					return nil, errors.New("marshaling error")
 				})
-				return authorizer, connections, &marshal
+				return authorizer, connections, jidNormalizer, msgRepo, &marshal
 			},
 			jsonRequest:  messageRequest,
 			expectStatus: http.StatusInternalServerError,
@@ -99,20 +129,23 @@ func TestSendTextMessageHandler_ServeHTTP(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{
-				"/send-message/": internalHttp.NewTextHandler(tt.mocksFactory(t)),
+				"/send-message/": textHandler(t)(tt.mocksFactory(t)),
 			})
 			defer server.Close()
 
 			expect := httpexpect.New(t, server.URL)
-			expect.POST("/send-message/").
+			response := expect.POST("/send-message/").
 				WithJSON(tt.jsonRequest()).
 				Expect().
 				Status(tt.expectStatus)
+			if tt.expectStatus == http.StatusTooManyRequests {
+				response.Header("Retry-After").Equal("2")
+			}
 		})
 	}
 }
 
-func mocksTextHandler(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections, *jsonInfra.MarshallCallback) {
+func mocksTextHandler(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections, *service.JidNormalizer, *mock.MockMessage, *jsonInfra.MarshallCallback) {
 	c := gomock.NewController(t)
 
 	wac := mock.NewMockConn(c)
@@ -125,13 +158,179 @@ func mocksTextHandler(t *testing.T) (*mock.MockAuthorizer, *mock.MockConnections
 		Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
 
 	marshal := jsonInfra.MarshallCallback(json.Marshal)
-	return mock.NewMockAuthorizer(c), connections, &marshal
+	return mock.NewMockAuthorizer(c), connections, service.NewJidNormalizer(""), sentStatusRepo(c), &marshal
+}
+
+// mocksSchedulingHandler provides mocks of handler sending messages immediately or scheduling them.
+func mocksSchedulingHandler(t *testing.T) (
+	*mock.MockAuthorizer,
+	*mock.MockConnections,
+	*service.JidNormalizer,
+	*mock.MockMessage,
+	*mock.MockScheduler,
+	*jsonInfra.MarshallCallback,
+) {
+	authorizer, connections, jidNormalizer, msgRepo, marshal := mocksTextHandler(t)
+	scheduler := mock.NewMockScheduler(gomock.NewController(t))
+	scheduler.EXPECT().
+		Schedule("_sid_", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(sessionID string, message *waProto.WebMessageInfo, at time.Time) (*model.ScheduledMessage, error) {
+			return &model.ScheduledMessage{
+				ID:        message.GetKey().GetId(),
+				SessionID: sessionID,
+				ChatID:    message.GetKey().GetRemoteJid(),
+				SendAt:    at,
+				Status:    model.ScheduledStatus,
+			}, nil
+		}).
+		AnyTimes()
+	return authorizer, connections, jidNormalizer, msgRepo, scheduler, marshal
+}
+
+func TestSendTextMessageHandler_Reply(t *testing.T) {
+	c := gomock.NewController(t)
+	var sent *waProto.WebMessageInfo
+	wac := mock.NewMockConn(c)
+	wac.EXPECT().Info().Return(&whatsapp.Info{Wid: "wid"})
+	wac.EXPECT().Send(gomock.Any()).DoAndReturn(func(msg interface{}) (string, error) {
+		sent = msg.(*waProto.WebMessageInfo)
+		return sent.GetKey().GetId(), nil
+	})
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession(gomock.Any()).
+		Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+	marshal := jsonInfra.MarshallCallback(json.Marshal)
+
+	server := httpTest.New(map[string]internalHttp.AppHTTPHandler{
+		"/send-message/": textHandler(t)(mock.NewMockAuthorizer(c), connections, service.NewJidNormalizer(""), sentStatusRepo(c), &marshal),
+	})
+	defer server.Close()
+
+	expect := httpexpect.New(t, server.URL)
+	response := expect.POST("/send-message/").
+		WithJSON(&internalHttp.SendMessageRequest{
+			ChatID:            "000000000000-1111111111@g.us",
+			Text:              "@375440000000 hello",
+			SessionID:         "_sid_",
+			QuotedMessageID:   "QUOTED_ID",
+			QuotedParticipant: "375441111111@s.whatsapp.net",
+			QuotedText:        "hi all",
+			Mentions:          []string{"375440000000@s.whatsapp.net"},
+		}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+
+	require.NotNil(t, sent)
+	response.ValueEqual("id", sent.GetKey().GetId())
+	response.ValueEqual("chat_id", "000000000000-1111111111@g.us")
+	response.ValueEqual("timestamp", sent.GetMessageTimestamp())
+	response.ValueEqual("status", model.SentDeliveryStatus)
+	assert.Equal(t, "000000000000-1111111111@g.us", sent.GetKey().GetRemoteJid())
+	text := sent.GetMessage().GetExtendedTextMessage()
+	assert.Equal(t, "@375440000000 hello", text.GetText())
+	assert.Equal(t, "QUOTED_ID", text.GetContextInfo().GetStanzaId())
+	assert.Equal(t, "375441111111@s.whatsapp.net", text.GetContextInfo().GetParticipant())
+	assert.Equal(t, "hi all", text.GetContextInfo().GetQuotedMessage().GetConversation())
+	assert.Equal(t, []string{"375440000000@s.whatsapp.net"}, text.GetContextInfo().GetMentionedJid())
+}
+
+func TestSendTextMessageHandler_Typing(t *testing.T) {
+	c := gomock.NewController(t)
+	wac := mock.NewMockConn(c)
+	gomock.InOrder(
+		wac.EXPECT().Presence("000000000000@s.whatsapp.net", whatsapp.PresenceComposing).Return(nil),
+		wac.EXPECT().Presence("000000000000@s.whatsapp.net", whatsapp.PresencePaused).Return(nil),
+		wac.EXPECT().Send(gomock.Any()).Return("MSG_ID", nil),
+	)
+	wac.EXPECT().Info().Return(&whatsapp.Info{Wid: "wid"})
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession("_sid_").
+		Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+	marshal := jsonInfra.MarshallCallback(json.Marshal)
+
+	server := httpTest.New(map[string]internalHttp.AppHTTPHandler{
+		"/send-message/": textHandler(t)(mock.NewMockAuthorizer(c), connections, service.NewJidNormalizer(""), sentStatusRepo(c), &marshal),
+	})
+	defer server.Close()
+
+	expect := httpexpect.New(t, server.URL)
+	started := time.Now()
+	expect.POST("/send-message/").
+		WithJSON(&internalHttp.SendMessageRequest{ChatID: "+000000000000", Text: "hello", SessionID: "_sid_", Typing: true}).
+		Expect().
+		Status(http.StatusOK)
+	assert.True(t, time.Since(started) >= 500*time.Millisecond)
+
+	expect.POST("/send-message/").
+		WithJSON(&internalHttp.SendMessageRequest{ChatID: "+000000000000", Text: "hello", SessionID: "_sid_", Typing: true, Async: true}).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+func TestSendTextMessageHandler_Async(t *testing.T) {
+	tests := []struct {
+		name         string
+		enqueueErr   error
+		expectStatus int
+	}{
+		{name: "OK", expectStatus: http.StatusAccepted},
+		{name: "Enqueueing error", enqueueErr: errors.New("redis is down"), expectStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			queue := mock.NewMockEnqueuer(c)
+			queue.EXPECT().
+				Enqueue("_sid_", gomock.Any()).
+				DoAndReturn(func(sessionID string, message *waProto.WebMessageInfo) (*model.QueuedMessage, error) {
+					assert.Equal(t, "000000000000@s.whatsapp.net", message.GetKey().GetRemoteJid())
+					assert.Equal(t, "hello", message.GetMessage().GetConversation())
+					return &model.QueuedMessage{ID: message.GetKey().GetId(), Status: model.QueuedStatus}, tt.enqueueErr
+				})
+			marshal := jsonInfra.MarshallCallback(json.Marshal)
+
+			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{
+				"/send-message/": internalHttp.NewTextHandler(
+					mock.NewMockAuthorizer(c),
+					mock.NewMockConnections(c),
+					service.NewJidNormalizer(""),
+					sentStatusRepo(c),
+					queue,
+					mock.NewMockScheduler(c),
+					mock.NewMockTemplate(c),
+					&marshal,
+				),
+			})
+			defer server.Close()
+
+			expect := httpexpect.New(t, server.URL)
+			response := expect.POST("/send-message/").
+				WithJSON(&internalHttp.SendMessageRequest{
+					ChatID:    "+000000000000",
+					Text:      "hello",
+					SessionID: "_sid_",
+					Async:     true,
+				}).
+				Expect().
+				Status(tt.expectStatus)
+			if tt.enqueueErr == nil {
+				response.JSON().Object().
+					ValueEqual("status", model.QueuedStatus).
+					NotContainsKey("attempts")
+			}
+		})
+	}
 }
 
 func TestTextHandlerFailWriteResponse(t *testing.T) {
-	handler := internalHttp.NewTextHandler(mocksTextHandler(t))
+	handler := textHandler(t)(mocksTextHandler(t))
 	w := mock.NewFailResponseRecorder(httptest.NewRecorder())
-	r, err := http.NewRequest("POST", "/send-message/", bytes.NewReader([]byte("{}")))
+	r, err := http.NewRequest("POST", "/send-message/", bytes.NewReader([]byte(`{"chat_id":"+000000000000"}`)))
 	require.Nil(t, err)
 	internalHttp.AppHandlerRunner{H: handler}.ServeHTTP(w, r)
 	assert.Equal(t, w.Status(), http.StatusInternalServerError)
@@ -144,3 +343,175 @@ func messageRequest() interface{} {
 		SessionID: "_sid_",
 	}
 }
+
+func textHandler(t *testing.T) func(
+	authorizer service.Authorizer,
+	connections service.Connections,
+	jidNormalizer *service.JidNormalizer,
+	msgRepo repository.Message,
+	marshal *jsonInfra.MarshallCallback,
+) *internalHttp.SendTextMessageHandler {
+	return func(
+		authorizer service.Authorizer,
+		connections service.Connections,
+		jidNormalizer *service.JidNormalizer,
+		msgRepo repository.Message,
+		marshal *jsonInfra.MarshallCallback,
+	) *internalHttp.SendTextMessageHandler {
+		c := gomock.NewController(t)
+		return internalHttp.NewTextHandler(
+			authorizer,
+			connections,
+			jidNormalizer,
+			msgRepo,
+			mock.NewMockEnqueuer(c),
+			mock.NewMockScheduler(c),
+			mock.NewMockTemplate(c),
+			marshal,
+		)
+	}
+}
+
+func TestSendTextMessageHandler_Schedule(t *testing.T) {
+	tests := []struct {
+		name         string
+		scheduleErr  error
+		expectStatus int
+	}{
+		{name: "OK", expectStatus: http.StatusAccepted},
+		{name: "Scheduling error", scheduleErr: errors.New("redis is down"), expectStatus: http.StatusInternalServerError},
+	}
+
+	sendAt := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			scheduler := mock.NewMockScheduler(c)
+			scheduler.EXPECT().
+				Schedule("_sid_", gomock.Any(), gomock.Any()).
+				DoAndReturn(func(sessionID string, message *waProto.WebMessageInfo, at time.Time) (*model.ScheduledMessage, error) {
+					assert.Equal(t, "hello", message.GetMessage().GetConversation())
+					assert.True(t, sendAt.Equal(at))
+					return &model.ScheduledMessage{
+						ID:        message.GetKey().GetId(),
+						SessionID: sessionID,
+						ChatID:    message.GetKey().GetRemoteJid(),
+						SendAt:    at,
+						Status:    model.ScheduledStatus,
+					}, tt.scheduleErr
+				})
+			marshal := jsonInfra.MarshallCallback(json.Marshal)
+
+			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{
+				"/send-message/": internalHttp.NewTextHandler(
+					mock.NewMockAuthorizer(c),
+					mock.NewMockConnections(c),
+					service.NewJidNormalizer(""),
+					sentStatusRepo(c),
+					mock.NewMockEnqueuer(c),
+					scheduler,
+					mock.NewMockTemplate(c),
+					&marshal,
+				),
+			})
+			defer server.Close()
+
+			expect := httpexpect.New(t, server.URL)
+			response := expect.POST("/send-message/").
+				WithJSON(&internalHttp.SendMessageRequest{
+					ChatID:    "+000000000000",
+					Text:      "hello",
+					SessionID: "_sid_",
+					SendAt:    &sendAt,
+				}).
+				Expect().
+				Status(tt.expectStatus)
+			if tt.scheduleErr == nil {
+				response.JSON().Object().
+					ValueEqual("status", model.ScheduledStatus).
+					ValueEqual("timestamp", sendAt.Unix())
+			}
+		})
+	}
+}
+
+func TestSendTextMessageHandler_Template(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      *internalHttp.SendMessageRequest
+		expectText   string
+		expectStatus int
+	}{
+		{
+			name:         "OK",
+			request:      &internalHttp.SendMessageRequest{TemplateID: "order_ready", Locale: "ru-RU", Vars: map[string]string{"order": "#42"}},
+			expectText:   "Заказ #42 готов",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Both text and template",
+			request:      &internalHttp.SendMessageRequest{Text: "hello", TemplateID: "order_ready"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Template not found",
+			request:      &internalHttp.SendMessageRequest{TemplateID: "unknown"},
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "Missing variable",
+			request:      &internalHttp.SendMessageRequest{TemplateID: "order_ready"},
+			expectStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			connections := mock.NewMockConnections(c)
+			if tt.expectStatus == http.StatusOK {
+				wac := mock.NewMockConn(c)
+				wac.EXPECT().Info().Return(&whatsapp.Info{Wid: "wid"})
+				wac.EXPECT().Send(gomock.Any()).DoAndReturn(func(msg interface{}) (string, error) {
+					sent := msg.(whatsapp.TextMessage)
+					assert.Equal(t, tt.expectText, sent.Text)
+					return sent.Info.Id, nil
+				})
+				connections.EXPECT().
+					AuthenticatedConnectionForSession("_sid_").
+					Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+			}
+			templateRepo := mock.NewMockTemplate(c)
+			templateRepo.EXPECT().Template("order_ready").Return(&model.Template{
+				ID:            "order_ready",
+				DefaultLocale: "en",
+				Locales:       map[string]string{"en": "Order {{.order}} is ready", "ru": "Заказ {{.order}} готов"},
+			}, nil).AnyTimes()
+			templateRepo.EXPECT().Template("unknown").Return(nil, nil).AnyTimes()
+			marshal := jsonInfra.MarshallCallback(json.Marshal)
+
+			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{
+				"/send-message/": internalHttp.NewTextHandler(
+					mock.NewMockAuthorizer(c),
+					connections,
+					service.NewJidNormalizer(""),
+					sentStatusRepo(c),
+					mock.NewMockEnqueuer(c),
+					mock.NewMockScheduler(c),
+					templateRepo,
+					&marshal,
+				),
+			})
+			defer server.Close()
+
+			tt.request.ChatID = "+000000000000"
+			tt.request.SessionID = "_sid_"
+			httpexpect.New(t, server.URL).POST("/send-message/").
+				WithJSON(tt.request).
+				Expect().
+				Status(tt.expectStatus)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"net/http"
+
+	httpInfra "github.com/r-erema/wapi/internal/infrastructure/http"
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/repository"
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/Rhymen/go-whatsapp"
+)
+
+// SendVideoHandler is responsible for sending videos.
+type SendVideoHandler struct {
+	auth   service.Authorizer
+	sender *mediaSender
+}
+
+// NewVideoHandler creates SendVideoHandler.
+func NewVideoHandler(
+	authorizer service.Authorizer,
+	connectionsSupervisor service.Connections,
+	jidNormalizer *service.JidNormalizer,
+	msgRepo repository.Message,
+	client httpInfra.Client,
+	marshal *jsonInfra.MarshallCallback,
+	maxUploadSize int64,
+) *SendVideoHandler {
+	return &SendVideoHandler{
+		auth:   authorizer,
+		sender: newMediaSender(connectionsSupervisor, jidNormalizer, msgRepo, client, marshal, "video", maxUploadSize),
+	}
+}
+
+// Handle sends message with client`s video to WhatsApp server.
+func (h *SendVideoHandler) Handle(w http.ResponseWriter, r *http.Request) *AppError {
+	var msgReq SendVideoRequest
+	upload, appErr := h.sender.decodeRequest(w, r, &msgReq)
+	if appErr != nil {
+		return appErr
+	}
+
+	source, appErr := h.sender.source(upload, msgReq.VideoBase64, msgReq.VideoURL)
+	if appErr != nil {
+		return appErr
+	}
+
+	return h.sender.send(w, msgReq.SessionID, msgReq.ChatID, source, func(info whatsapp.MessageInfo, content *mediaContent) interface{} {
+		return whatsapp.VideoMessage{
+			Info:        info,
+			Type:        detectMimeType(content.head, content.fileName, msgReq.MimeType),
+			Caption:     msgReq.Caption,
+			GifPlayback: msgReq.GifPlayback,
+			Content:     content,
+		}
+	})
+}
+
+// SendVideoRequest is the request for sending video to WhatsApp.
+type SendVideoRequest struct {
+	SessionID   string `json:"session_name"`
+	ChatID      string `json:"chat_id"`
+	VideoURL    string `json:"video_url"`
+	VideoBase64 string `json:"video_base64"`
+	Caption     string `json:"caption"`
+	MimeType    string `json:"mime_type"`
+	GifPlayback bool   `json:"gif_playback"`
+}
@@ -0,0 +1,47 @@
+package http_test
+
+import (
+	"testing"
+
+	internalHttp "github.com/r-erema/wapi/internal/http"
+	httpTest "github.com/r-erema/wapi/internal/testutil/http"
+
+	"github.com/gavv/httpexpect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVideoHandler(t *testing.T) {
+	handler := internalHttp.NewVideoHandler(mocks(t))
+	assert.NotNil(t, handler)
+}
+
+func TestSendVideoHandler(t *testing.T) {
+	tests := []testData{
+		ok(),
+		connectionNotFound(),
+		badImageURL(),
+		cantReadImageBody(),
+		errorImageSending(),
+		marshalingError(),
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			handler := internalHttp.NewVideoHandler(tt.imagesMocksFactory(t))
+			server := httpTest.New(map[string]internalHttp.AppHTTPHandler{"/send-video/": handler})
+			defer server.Close()
+
+			expect := httpexpect.New(t, server.URL)
+			expect.POST("/send-video/").
+				WithJSON(&internalHttp.SendVideoRequest{
+					SessionID: "_sid_",
+					ChatID:    "+000000000000",
+					VideoURL:  "https://host/video.mp4",
+					Caption:   "test video",
+				}).
+				Expect().
+				Status(tt.expectStatus)
+		})
+	}
+}
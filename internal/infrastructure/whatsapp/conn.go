@@ -1,14 +1,24 @@
 package whatsapp
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
 )
 
 // ErrMsg401 should emerge if login failed because of 401 response.
 const ErrMsg401 = "admin login responded with 401"
 
+// Suffixes of user JIDs, WhatsApp Web protocol uses its own suffix in queries and JSON events.
+const (
+	userJidSuffix    = "@s.whatsapp.net"
+	webUserJidSuffix = "@c.us"
+)
+
 // Conn is an object of connection with Whatsapp server.
 type Conn interface {
 	// Send sends messages to WhatsApp server.
@@ -25,12 +35,49 @@ type Conn interface {
 	Login(qrChan chan<- string) (whatsapp.Session, error)
 	// AddHandler registers messages handler.
 	AddHandler(handler whatsapp.Handler)
+	// Exist checks whether account of jid is registered on WhatsApp.
+	Exist(jid string) (bool, error)
+	// Read marks messages of chat up to message with id as read.
+	Read(jid, messageID string) error
+	// Presence sends presence of account, composing, recording and paused presences are sent to chat of jid.
+	Presence(jid string, presence whatsapp.Presence) error
+	// SubscribePresence subscribes to presence of account of jid, updates are received as JSON messages.
+	SubscribePresence(jid string) error
+	// ProfilePictureURL provides URL of profile picture thumbnail of account or group.
+	ProfilePictureURL(jid string) (string, error)
+	// Status provides "about" status of account.
+	Status(jid string) (string, error)
+	// LoadMessages loads up to count latest messages of chat.
+	LoadMessages(jid string, count int) ([]*proto.WebMessageInfo, error)
+	// Revoke deletes message sent by account for everyone in chat.
+	Revoke(jid, messageID string) error
+	// CreateGroup creates group with subject and participants, JID of created group is returned.
+	CreateGroup(subject string, participants []string) (string, []GroupParticipantStatus, error)
+	// GroupMetadata provides information about group and its participants.
+	GroupMetadata(jid string) (*GroupMetadata, error)
+	// AddGroupParticipants adds participants to group.
+	AddGroupParticipants(jid string, participants []string) ([]GroupParticipantStatus, error)
+	// RemoveGroupParticipants removes participants from group.
+	RemoveGroupParticipants(jid string, participants []string) ([]GroupParticipantStatus, error)
+	// PromoteGroupParticipants makes participants admins of group.
+	PromoteGroupParticipants(jid string, participants []string) ([]GroupParticipantStatus, error)
+	// DemoteGroupParticipants revokes admin rights of participants of group.
+	DemoteGroupParticipants(jid string, participants []string) ([]GroupParticipantStatus, error)
+	// SetGroupSubject changes subject of group.
+	SetGroupSubject(jid, subject string) error
+	// SetGroupDescription changes description of group.
+	SetGroupDescription(jid, description string) error
+	// LeaveGroup leaves group.
+	LeaveGroup(jid string) error
+	// GroupInviteLink provides link inviting to group.
+	GroupInviteLink(jid string) (string, error)
 }
 
 // RhymenConn is an object of connection with Whatsapp server
 // implemented using github.com/Rhymen/go-whatsapp package.
 type RhymenConn struct {
-	wac *whatsapp.Conn
+	wac     *whatsapp.Conn
+	timeout time.Duration
 }
 
 // NewRhymenConn creates connection object with WhatsApp server.
@@ -39,7 +86,7 @@ func NewRhymenConn(timeout time.Duration) (*RhymenConn, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &RhymenConn{wac: wac}, nil
+	return &RhymenConn{wac: wac, timeout: timeout}, nil
 }
 
 // Send sends messages to WhatsApp server.
@@ -76,3 +123,64 @@ func (r *RhymenConn) Login(qrChan chan<- string) (whatsapp.Session, error) {
 func (r *RhymenConn) AddHandler(handler whatsapp.Handler) {
 	r.wac.AddHandler(handler)
 }
+
+// Exist checks whether account of jid is registered on WhatsApp.
+func (r *RhymenConn) Exist(jid string) (bool, error) {
+	ch, err := r.wac.Exist(toWebJids([]string{jid})[0])
+	if err != nil {
+		return false, err
+	}
+	response, err := r.await(ch, "exist")
+	if err != nil {
+		return false, err
+	}
+	var resp struct {
+		Status int `json:"status"`
+	}
+	if err = json.Unmarshal([]byte(response), &resp); err != nil {
+		return false, fmt.Errorf("error decoding exist response: %v", err)
+	}
+	switch resp.Status {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, &StatusError{Query: "exist", Status: resp.Status}
+	}
+}
+
+// Read marks messages of chat up to message with id as read, response of server isn't awaited.
+func (r *RhymenConn) Read(jid, messageID string) error {
+	_, err := r.wac.Read(jid, messageID)
+	return err
+}
+
+// Presence sends presence of account, composing, recording and paused presences are sent to chat of jid,
+// response of server isn't awaited.
+func (r *RhymenConn) Presence(jid string, presence whatsapp.Presence) error {
+	_, err := r.wac.Presence(jid, presence)
+	return err
+}
+
+// SubscribePresence subscribes to presence of account of jid, updates are received as JSON messages.
+func (r *RhymenConn) SubscribePresence(jid string) error {
+	ch, err := r.wac.SubscribePresence(toWebJids([]string{jid})[0])
+	if err != nil {
+		return err
+	}
+	response, err := r.await(ch, "subscribe presence")
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Status int `json:"status"`
+	}
+	if err = json.Unmarshal([]byte(response), &resp); err != nil {
+		return fmt.Errorf("error decoding subscribe presence response: %v", err)
+	}
+	if resp.Status != http.StatusOK {
+		return &StatusError{Query: "subscribe presence", Status: resp.Status}
+	}
+	return nil
+}
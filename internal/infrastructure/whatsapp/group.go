@@ -0,0 +1,293 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+// InviteLinkPrefix is a prefix of links inviting to groups, it's followed by invite code.
+const InviteLinkPrefix = "https://chat.whatsapp.com/"
+
+// StatusError is an error of query responded by WhatsApp server with not successful status.
+type StatusError struct {
+	Query  string
+	Status int
+}
+
+// Error returns error message.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s query responded with %d", e.Query, e.Status)
+}
+
+// GroupMetadata is information about group and its participants.
+type GroupMetadata struct {
+	Jid          string             `json:"id"`
+	Subject      string             `json:"subject"`
+	Description  string             `json:"description"`
+	Owner        string             `json:"owner"`
+	CreatedAt    time.Time          `json:"created_at"`
+	Participants []GroupParticipant `json:"participants"`
+}
+
+// GroupParticipant is a participant of group.
+type GroupParticipant struct {
+	Jid          string `json:"id"`
+	IsAdmin      bool   `json:"is_admin"`
+	IsSuperAdmin bool   `json:"is_super_admin"`
+}
+
+// GroupParticipantStatus is a result of changing participant of group, code is 200 if participant is changed.
+type GroupParticipantStatus struct {
+	Jid  string `json:"id"`
+	Code int    `json:"code"`
+}
+
+// groupResponse is a response of WhatsApp server to group actions.
+type groupResponse struct {
+	Status       int                      `json:"status"`
+	Gid          string                   `json:"gid"`
+	Participants []map[string]interface{} `json:"participants"`
+}
+
+// participantStatuses converts statuses of participants, each status is a map of participant JID to its code.
+func (r *groupResponse) participantStatuses() []GroupParticipantStatus {
+	statuses := make([]GroupParticipantStatus, 0, len(r.Participants))
+	for _, participant := range r.Participants {
+		for jid, status := range participant {
+			code := http.StatusOK
+			if fields, ok := status.(map[string]interface{}); ok {
+				if parsed, err := strconv.Atoi(fmt.Sprint(fields["code"])); err == nil {
+					code = parsed
+				}
+			}
+			statuses = append(statuses, GroupParticipantStatus{Jid: NormalizeUserJid(jid), Code: code})
+		}
+	}
+	return statuses
+}
+
+// CreateGroup creates group with subject and participants, JID of created group is returned.
+func (r *RhymenConn) CreateGroup(subject string, participants []string) (string, []GroupParticipantStatus, error) {
+	ch, err := r.wac.CreateGroup(subject, toWebJids(participants))
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err := r.groupResponse(ch, "create group")
+	if err != nil {
+		return "", nil, err
+	}
+	return resp.Gid, resp.participantStatuses(), nil
+}
+
+// groupMetadataResponse is a response of WhatsApp server to group metadata query.
+type groupMetadataResponse struct {
+	Status       int    `json:"status"`
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	Subject      string `json:"subject"`
+	Creation     int64  `json:"creation"`
+	Desc         string `json:"desc"`
+	DescID       string `json:"descId"`
+	Participants []struct {
+		ID           string `json:"id"`
+		IsAdmin      bool   `json:"isAdmin"`
+		IsSuperAdmin bool   `json:"isSuperAdmin"`
+	} `json:"participants"`
+}
+
+// GroupMetadata provides information about group and its participants.
+func (r *RhymenConn) GroupMetadata(jid string) (*GroupMetadata, error) {
+	resp, err := r.groupMetadata(jid)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &GroupMetadata{
+		Jid:          resp.ID,
+		Subject:      resp.Subject,
+		Description:  resp.Desc,
+		Owner:        NormalizeUserJid(resp.Owner),
+		CreatedAt:    time.Unix(resp.Creation, 0).UTC(),
+		Participants: make([]GroupParticipant, 0, len(resp.Participants)),
+	}
+	for _, participant := range resp.Participants {
+		metadata.Participants = append(metadata.Participants, GroupParticipant{
+			Jid:          NormalizeUserJid(participant.ID),
+			IsAdmin:      participant.IsAdmin,
+			IsSuperAdmin: participant.IsSuperAdmin,
+		})
+	}
+	return metadata, nil
+}
+
+// AddGroupParticipants adds participants to group.
+func (r *RhymenConn) AddGroupParticipants(jid string, participants []string) ([]GroupParticipantStatus, error) {
+	return r.participantsResponse(r.wac.AddMember(jid, toWebJids(participants)))
+}
+
+// RemoveGroupParticipants removes participants from group.
+func (r *RhymenConn) RemoveGroupParticipants(jid string, participants []string) ([]GroupParticipantStatus, error) {
+	return r.participantsResponse(r.wac.RemoveMember(jid, toWebJids(participants)))
+}
+
+// PromoteGroupParticipants makes participants admins of group.
+func (r *RhymenConn) PromoteGroupParticipants(jid string, participants []string) ([]GroupParticipantStatus, error) {
+	return r.participantsResponse(r.wac.SetAdmin(jid, toWebJids(participants)))
+}
+
+// DemoteGroupParticipants revokes admin rights of participants of group.
+func (r *RhymenConn) DemoteGroupParticipants(jid string, participants []string) ([]GroupParticipantStatus, error) {
+	return r.participantsResponse(r.wac.RemoveAdmin(jid, toWebJids(participants)))
+}
+
+// SetGroupSubject changes subject of group.
+func (r *RhymenConn) SetGroupSubject(jid, subject string) error {
+	ch, err := r.wac.UpdateGroupSubject(subject, jid)
+	if err != nil {
+		return err
+	}
+	_, err = r.groupResponse(ch, "group subject")
+	return err
+}
+
+// SetGroupDescription changes description of group, empty description removes it.
+// The go-whatsapp package has no request for it, so group action node is built by hand,
+// it refers to id of current description taken from group metadata.
+func (r *RhymenConn) SetGroupDescription(jid, description string) error {
+	current, err := r.groupMetadata(jid)
+	if err != nil {
+		return err
+	}
+	descNode := binary.Node{
+		Description: "description",
+		Attributes:  map[string]string{"id": NewMessageID()},
+	}
+	if current.DescID != "" {
+		descNode.Attributes["prev"] = current.DescID
+	}
+	if description == "" {
+		descNode.Attributes["delete"] = "true"
+	} else {
+		descNode.Content = []byte(description)
+	}
+
+	tag, epoch := messageTag(r.wac)
+	node := binary.Node{
+		Description: "action",
+		Attributes:  map[string]string{"type": "set", "epoch": epoch},
+		Content: []binary.Node{{
+			Description: "group",
+			Attributes: map[string]string{
+				"author": r.wac.Info.Wid,
+				"id":     tag,
+				"type":   "description",
+				"jid":    jid,
+			},
+			Content: []binary.Node{descNode},
+		}},
+	}
+	ch, err := writeBinary(r.wac, node, groupMetric, ignoreFlag, tag)
+	if err != nil {
+		return err
+	}
+	_, err = r.groupResponse(ch, "group description")
+	return err
+}
+
+// LeaveGroup leaves group.
+func (r *RhymenConn) LeaveGroup(jid string) error {
+	ch, err := r.wac.LeaveGroup(jid)
+	if err != nil {
+		return err
+	}
+	_, err = r.groupResponse(ch, "leave group")
+	return err
+}
+
+// GroupInviteLink provides link inviting to group, only admins can get it.
+func (r *RhymenConn) GroupInviteLink(jid string) (string, error) {
+	code, err := r.wac.GroupInviteLink(jid)
+	if err != nil {
+		return "", err
+	}
+	return InviteLinkPrefix + code, nil
+}
+
+func (r *RhymenConn) groupMetadata(jid string) (*groupMetadataResponse, error) {
+	ch, err := r.wac.GetGroupMetaData(jid)
+	if err != nil {
+		return nil, err
+	}
+	response, err := r.await(ch, "group metadata")
+	if err != nil {
+		return nil, err
+	}
+	var resp groupMetadataResponse
+	if err = json.Unmarshal([]byte(response), &resp); err != nil {
+		return nil, fmt.Errorf("error decoding group metadata response: %v", err)
+	}
+	if resp.Status != 0 && resp.Status != http.StatusOK {
+		return nil, &StatusError{Query: "group metadata", Status: resp.Status}
+	}
+	return &resp, nil
+}
+
+func (r *RhymenConn) participantsResponse(ch <-chan string, err error) ([]GroupParticipantStatus, error) {
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.groupResponse(ch, "group participants")
+	if err != nil {
+		return nil, err
+	}
+	return resp.participantStatuses(), nil
+}
+
+func (r *RhymenConn) groupResponse(ch <-chan string, query string) (*groupResponse, error) {
+	response, err := r.await(ch, query)
+	if err != nil {
+		return nil, err
+	}
+	var resp groupResponse
+	if err = json.Unmarshal([]byte(response), &resp); err != nil {
+		return nil, fmt.Errorf("error decoding %s response: %v", query, err)
+	}
+	if resp.Status != http.StatusOK {
+		return nil, &StatusError{Query: query, Status: resp.Status}
+	}
+	return &resp, nil
+}
+
+// await waits for response of query during connection timeout.
+func (r *RhymenConn) await(ch <-chan string, query string) (string, error) {
+	select {
+	case response := <-ch:
+		return response, nil
+	case <-time.After(r.timeout):
+		return "", fmt.Errorf("%s query timed out", query)
+	}
+}
+
+// toWebJids converts JIDs of users to format of WhatsApp Web protocol.
+func toWebJids(jids []string) []string {
+	webJids := make([]string, len(jids))
+	for i, jid := range jids {
+		webJids[i] = strings.Replace(jid, userJidSuffix, webUserJidSuffix, 1)
+	}
+	return webJids
+}
+
+// NormalizeUserJid converts JID of user in format of WhatsApp Web protocol, e.g. `375447034810@c.us`,
+// to format used in messages, e.g. `375447034810@s.whatsapp.net`. Other JIDs are returned as is.
+func NormalizeUserJid(jid string) string {
+	if !strings.HasSuffix(jid, webUserJidSuffix) {
+		return jid
+	}
+	return strings.TrimSuffix(jid, webUserJidSuffix) + userJidSuffix
+}
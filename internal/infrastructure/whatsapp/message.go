@@ -0,0 +1,73 @@
+package whatsapp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+)
+
+// LoadMessages loads up to count latest messages of chat during connection timeout.
+func (r *RhymenConn) LoadMessages(jid string, count int) ([]*proto.WebMessageInfo, error) {
+	type loaded struct {
+		node *binary.Node
+		err  error
+	}
+	// go-whatsapp waits for response of the query without timeout, so it's awaited in background.
+	ch := make(chan loaded, 1)
+	go func() {
+		node, err := r.wac.LoadMessages(toWebJids([]string{jid})[0], "", count)
+		ch <- loaded{node: node, err: err}
+	}()
+	var node *binary.Node
+	select {
+	case result := <-ch:
+		if result.err != nil {
+			return nil, result.err
+		}
+		node = result.node
+	case <-time.After(r.timeout):
+		return nil, fmt.Errorf("load messages query of chat %s timed out", jid)
+	}
+	messages := make([]*proto.WebMessageInfo, 0, count)
+	if node == nil {
+		return messages, nil
+	}
+	content, _ := node.Content.([]interface{})
+	for _, item := range content {
+		if msg, ok := item.(*proto.WebMessageInfo); ok {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
+// Revoke deletes message sent by account for everyone in chat, it's done by sending revoke protocol message.
+func (r *RhymenConn) Revoke(jid, messageID string) error {
+	fromMe := true
+	id := NewMessageID()
+	timestamp := uint64(time.Now().Unix())
+	status := proto.WebMessageInfo_PENDING
+	revokeType := proto.ProtocolMessage_REVOKE
+	_, err := r.wac.Send(&proto.WebMessageInfo{
+		Key:              &proto.MessageKey{FromMe: &fromMe, RemoteJid: &jid, Id: &id},
+		MessageTimestamp: &timestamp,
+		Status:           &status,
+		Message: &proto.Message{ProtocolMessage: &proto.ProtocolMessage{
+			Key:  &proto.MessageKey{FromMe: &fromMe, RemoteJid: &jid, Id: &messageID},
+			Type: &revokeType,
+		}},
+	})
+	return err
+}
+
+// NewMessageID generates id of outgoing message the same way go-whatsapp does it.
+func NewMessageID() string {
+	id := make([]byte, 10)
+	_, _ = rand.Read(id)
+	return strings.ToUpper(hex.EncodeToString(id))
+}
@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ProfilePictureURL provides URL of profile picture thumbnail of account or group,
+// empty URL is returned if there is no picture or it's hidden by privacy settings.
+func (r *RhymenConn) ProfilePictureURL(jid string) (string, error) {
+	ch, err := r.wac.GetProfilePicThumb(toWebJids([]string{jid})[0])
+	if err != nil {
+		return "", err
+	}
+	response, err := r.await(ch, "profile picture")
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		URL    string `json:"eurl"`
+		Status int    `json:"status"`
+	}
+	if err = json.Unmarshal([]byte(response), &resp); err != nil {
+		return "", fmt.Errorf("error decoding profile picture response: %v", err)
+	}
+	if resp.URL != "" {
+		return resp.URL, nil
+	}
+	if isHiddenStatus(resp.Status) {
+		return "", nil
+	}
+	return "", &StatusError{Query: "profile picture", Status: resp.Status}
+}
+
+// Status provides "about" status of account, empty status is returned if it's hidden by privacy settings.
+func (r *RhymenConn) Status(jid string) (string, error) {
+	ch, err := r.wac.GetStatus(toWebJids([]string{jid})[0])
+	if err != nil {
+		return "", err
+	}
+	response, err := r.await(ch, "status")
+	if err != nil {
+		return "", err
+	}
+	// Status field contains either text of status or code of error.
+	var resp struct {
+		Status json.RawMessage `json:"status"`
+	}
+	if err = json.Unmarshal([]byte(response), &resp); err != nil {
+		return "", fmt.Errorf("error decoding status response: %v", err)
+	}
+	var status string
+	if err = json.Unmarshal(resp.Status, &status); err == nil {
+		return status, nil
+	}
+	var code int
+	if err = json.Unmarshal(resp.Status, &code); err != nil {
+		return "", fmt.Errorf("error decoding status response: %v", err)
+	}
+	if isHiddenStatus(code) {
+		return "", nil
+	}
+	return "", &StatusError{Query: "status", Status: code}
+}
+
+// isHiddenStatus checks whether status code of query means that requested information is absent or hidden.
+func isHiddenStatus(status int) bool {
+	return status == http.StatusNotFound || status == http.StatusUnauthorized
+}
@@ -0,0 +1,30 @@
+package whatsapp
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+	_ "unsafe" // Required by go:linkname.
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+// Metric and flag of binary messages of WhatsApp Web protocol, values are the same as in go-whatsapp package.
+const (
+	groupMetric = 10
+	ignoreFlag  = 1 << 7
+)
+
+// writeBinary writes binary node to WhatsApp server by unexported writer of go-whatsapp connection,
+// it's used for requests the package has no methods for. Response of server is sent to returned channel.
+//
+//go:linkname writeBinary github.com/Rhymen/go-whatsapp.(*Conn).writeBinary
+func writeBinary(wac *whatsapp.Conn, node binary.Node, metric, flag byte, messageTag string) (<-chan string, error)
+
+// messageTag builds tag of binary message and its epoch the way go-whatsapp does it: by count of written messages.
+func messageTag(wac *whatsapp.Conn) (tag, epoch string) {
+	count := reflect.ValueOf(wac).Elem().FieldByName("msgCount").Int()
+	return fmt.Sprintf("%d.--%d", time.Now().Unix(), count), strconv.FormatInt(count, 10)
+}
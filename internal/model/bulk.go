@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Statuses of bulk jobs.
+const (
+	RunningBulkStatus   = "running"   // Some messages of job wait to be sent.
+	CompletedBulkStatus = "completed" // All messages of job are sent or failed.
+	CancelledBulkStatus = "cancelled" // Messages of job waiting to be sent are cancelled.
+	PartialBulkStatus   = "partial"   // Queueing of job messages failed partway, messages that weren't queued are failed.
+)
+
+// BulkJob is a sending of messages to a list of recipients via queue of session.
+type BulkJob struct {
+	ID         string          `json:"id"`
+	SessionID  string          `json:"session_name"`
+	Status     string          `json:"status"`
+	Progress   BulkProgress    `json:"progress"`
+	Recipients []BulkRecipient `json:"recipients"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// BulkProgress contains numbers of job messages by their statuses.
+type BulkProgress struct {
+	Total     int `json:"total"`
+	Pending   int `json:"pending"` // Queued or retrying messages.
+	Sent      int `json:"sent"`
+	Failed    int `json:"failed"`
+	Cancelled int `json:"cancelled"`
+}
+
+// BulkRecipient is a result of sending job message to recipient, status is a status of queued message.
+type BulkRecipient struct {
+	ChatID    string `json:"chat_id"`
+	MessageID string `json:"message_id"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Contact is an entry of WhatsApp account address book, push name is a name set by contact itself.
+type Contact struct {
+	Jid       string `json:"id"`
+	Name      string `json:"name"`
+	ShortName string `json:"short_name"`
+	PushName  string `json:"push_name"`
+}
+
+// Matches checks whether any name or jid of contact contains search string, case insensitive.
+func (c *Contact) Matches(search string) bool {
+	return containsFold(search, c.Jid, c.Name, c.ShortName, c.PushName)
+}
+
+// Chat is a conversation of WhatsApp account with a user or a group.
+type Chat struct {
+	Jid           string    `json:"id"`
+	Name          string    `json:"name"`
+	IsGroup       bool      `json:"is_group"`
+	UnreadCount   int       `json:"unread_count"`
+	LastMessageAt time.Time `json:"last_message_at"`
+	Muted         bool      `json:"muted"`
+	Spam          bool      `json:"spam"`
+}
+
+// Matches checks whether name or jid of chat contains search string, case insensitive.
+func (c *Chat) Matches(search string) bool {
+	return containsFold(search, c.Jid, c.Name)
+}
+
+func containsFold(search string, values ...string) bool {
+	search = strings.ToLower(search)
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), search) {
+			return true
+		}
+	}
+	return false
+}
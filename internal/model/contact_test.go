@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContact_Matches(t *testing.T) {
+	contact := &Contact{Jid: "375290000001@s.whatsapp.net", Name: "John Smith", ShortName: "John", PushName: "Johnny"}
+	assert.True(t, contact.Matches("smith"))
+	assert.True(t, contact.Matches("JOHNNY"))
+	assert.True(t, contact.Matches("37529"))
+	assert.False(t, contact.Matches("alice"))
+}
+
+func TestChat_Matches(t *testing.T) {
+	chat := &Chat{Jid: "375290000001-1589212345@g.us", Name: "Family"}
+	assert.True(t, chat.Matches("fam"))
+	assert.True(t, chat.Matches("1589212345"))
+	assert.False(t, chat.Matches("work"))
+}
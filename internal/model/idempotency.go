@@ -0,0 +1,8 @@
+package model
+
+// IdempotentResponse is a stored response of request with idempotency key, it's replayed for duplicate requests.
+type IdempotentResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
@@ -0,0 +1,10 @@
+package model
+
+// NumberCheck is a result of checking whether phone number is registered on WhatsApp,
+// error is set if number is invalid or it couldn't be checked.
+type NumberCheck struct {
+	Phone  string `json:"phone"`
+	Jid    string `json:"jid,omitempty"`
+	Exists bool   `json:"exists"`
+	Error  string `json:"error,omitempty"`
+}
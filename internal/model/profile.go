@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Profile is a public profile of WhatsApp account as seen by session, picture URL and status are empty
+// if they're absent or hidden by privacy settings of account.
+type Profile struct {
+	Jid        string    `json:"jid"`
+	PictureURL string    `json:"picture_url"`
+	Status     string    `json:"status"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
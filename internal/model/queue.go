@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Statuses of queued messages.
+const (
+	QueuedStatus    = "queued"    // Message waits to be sent.
+	RetryingStatus  = "retrying"  // Sending failed, message waits to be sent again.
+	SentStatus      = "sent"      // Message is sent to WhatsApp server.
+	FailedStatus    = "failed"    // All attempts of sending failed.
+	CancelledStatus = "cancelled" // Message is removed from queue before sending.
+)
+
+// QueuedMessage is an outgoing message queued to be sent asynchronously.
+type QueuedMessage struct {
+	ID        string     `json:"id"`
+	SessionID string     `json:"session_name"`
+	ChatID    string     `json:"chat_id"`
+	Status    string     `json:"status"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error,omitempty"`
+	RetryAt   *time.Time `json:"retry_at,omitempty"` // Time of next attempt of retried message.
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Message   []byte     `json:"-"` // Serialized proto of WhatsApp message.
+}
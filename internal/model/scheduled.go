@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// ScheduledStatus is a status of message waiting for its sending time,
+// when time comes message is queued and gets QueuedStatus, cancelled message gets CancelledStatus.
+const ScheduledStatus = "scheduled"
+
+// ScheduledMessage is an outgoing message to be sent at specified time.
+type ScheduledMessage struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_name"`
+	ChatID    string    `json:"chat_id"`
+	SendAt    time.Time `json:"send_at"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Message   []byte    `json:"-"` // Serialized proto of WhatsApp message.
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Delivery statuses of sent messages.
+const (
+	ErrorDeliveryStatus     = "error"
+	PendingDeliveryStatus   = "pending"
+	SentDeliveryStatus      = "sent"      // Message is received by WhatsApp server.
+	DeliveredDeliveryStatus = "delivered" // Message is delivered to recipient's device.
+	ReadDeliveryStatus      = "read"
+	PlayedDeliveryStatus    = "played" // Voice note or video is played by recipient.
+)
+
+// Delivery statuses ranked by delivery progress, error is ranked highest, so it always wins and isn't changed later.
+var deliveryStatusRanks = map[string]int{
+	PendingDeliveryStatus:   1,
+	SentDeliveryStatus:      2,
+	DeliveredDeliveryStatus: 3,
+	ReadDeliveryStatus:      4,
+	PlayedDeliveryStatus:    5,
+	ErrorDeliveryStatus:     6,
+}
+
+// MessageStatus is a delivery status of sent message along with time each status is reached at.
+type MessageStatus struct {
+	ID         string               `json:"id"`
+	SessionID  string               `json:"session_name"`
+	ChatID     string               `json:"chat_id"`
+	Status     string               `json:"status"`
+	Timestamps map[string]time.Time `json:"timestamps"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+}
+
+// NewMessageStatus creates message status which hasn't reached any delivery status yet.
+func NewMessageStatus(id, sessionID, chatID string) *MessageStatus {
+	return &MessageStatus{ID: id, SessionID: sessionID, ChatID: chatID, Timestamps: make(map[string]time.Time)}
+}
+
+// Advance moves message to status reached at time, false is returned if status doesn't advance delivery progress.
+func (s *MessageStatus) Advance(status string, at time.Time) bool {
+	rank, ok := deliveryStatusRanks[status]
+	if !ok || rank <= deliveryStatusRanks[s.Status] {
+		return false
+	}
+	if s.Timestamps == nil {
+		s.Timestamps = make(map[string]time.Time)
+	}
+	s.Status = status
+	s.Timestamps[status] = at
+	s.UpdatedAt = time.Now()
+	return true
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageStatus_Advance(t *testing.T) {
+	at := time.Unix(1600000000, 0)
+	status := NewMessageStatus("MSG_ID", "_sid_", "375290000001@s.whatsapp.net")
+
+	assert.True(t, status.Advance(SentDeliveryStatus, at))
+	assert.True(t, status.Advance(ReadDeliveryStatus, at))
+	assert.False(t, status.Advance(DeliveredDeliveryStatus, at), "late ack doesn't move status backwards")
+	assert.False(t, status.Advance("unknown", at))
+	assert.Equal(t, ReadDeliveryStatus, status.Status)
+
+	assert.True(t, status.Advance(ErrorDeliveryStatus, at), "error wins over any status")
+	assert.False(t, status.Advance(PlayedDeliveryStatus, at))
+	assert.Equal(t, ErrorDeliveryStatus, status.Status)
+	assert.Equal(t, at, status.Timestamps[ErrorDeliveryStatus])
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var templateIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Template is a text message template, its variants per locale are Go templates rendered with variables, e.g.
+// `Hello, {{.name}}! Your order {{.order}} is ready`.
+type Template struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	DefaultLocale string            `json:"default_locale"`
+	Locales       map[string]string `json:"locales"` // Template text per locale, e.g. `en`, `en-US`.
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// Validate checks template has valid id, text of default locale and all its texts are valid Go templates.
+func (t *Template) Validate() error {
+	if err := ValidateTemplateID(t.ID); err != nil {
+		return err
+	}
+	if _, ok := t.Locales[t.DefaultLocale]; !ok {
+		return errors.Errorf("template has no text of default locale `%s`", t.DefaultLocale)
+	}
+	for locale, text := range t.Locales {
+		if _, err := parseText(text); err != nil {
+			return errors.Wrapf(err, "invalid text of locale `%s`", locale)
+		}
+	}
+	return nil
+}
+
+// ValidateTemplateID checks template id consists of latin letters, digits, `_` and `-`.
+func ValidateTemplateID(templateID string) error {
+	if !templateIDPattern.MatchString(templateID) {
+		return errors.Errorf("template id `%s` must consist of latin letters, digits, `_` and `-`", templateID)
+	}
+	return nil
+}
+
+// Text provides template text of locale, text of its language (e.g. `en` for `en-US`) is used
+// if there is no text of locale, text of default locale is used if there is no text of language.
+func (t *Template) Text(locale string) string {
+	if text, ok := t.Locales[locale]; ok {
+		return text
+	}
+	language := strings.SplitN(strings.Replace(locale, "_", "-", 1), "-", 2)[0]
+	if text, ok := t.Locales[language]; ok {
+		return text
+	}
+	return t.Locales[t.DefaultLocale]
+}
+
+// Render renders template text of locale with variables.
+func (t *Template) Render(locale string, vars map[string]string) (string, error) {
+	return RenderText(t.Text(locale), vars)
+}
+
+// RenderText renders text as Go template with variables, all variables used in text must be set.
+func RenderText(text string, vars map[string]string) (string, error) {
+	tmpl, err := parseText(text)
+	if err != nil {
+		return "", err
+	}
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, vars); err != nil {
+		return "", errors.Wrap(err, "can't render text")
+	}
+	return rendered.String(), nil
+}
+
+func parseText(text string) (*template.Template, error) {
+	tmpl, err := template.New("text").Option("missingkey=error").Parse(text)
+	return tmpl, errors.Wrap(err, "can't parse text")
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		tmpl      *Template
+		expectErr bool
+	}{
+		{
+			name: "OK",
+			tmpl: &Template{ID: "order_ready", DefaultLocale: "en", Locales: map[string]string{"en": "Hi {{.name}}"}},
+		},
+		{
+			name:      "Invalid id",
+			tmpl:      &Template{ID: "../order", DefaultLocale: "en", Locales: map[string]string{"en": "Hi"}},
+			expectErr: true,
+		},
+		{
+			name:      "No text of default locale",
+			tmpl:      &Template{ID: "order_ready", DefaultLocale: "ru", Locales: map[string]string{"en": "Hi"}},
+			expectErr: true,
+		},
+		{
+			name:      "Invalid text",
+			tmpl:      &Template{ID: "order_ready", DefaultLocale: "en", Locales: map[string]string{"en": "Hi {{.name"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tmpl.Validate()
+			assert.Equal(t, tt.expectErr, err != nil)
+		})
+	}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	tmpl := &Template{
+		ID:            "order_ready",
+		DefaultLocale: "en",
+		Locales: map[string]string{
+			"en":    "Your order {{.order}} is ready",
+			"en-GB": "Your order {{.order}} is ready, {{.name}}",
+			"ru":    "Ваш заказ {{.order}} готов",
+		},
+	}
+	vars := map[string]string{"order": "#42", "name": "John"}
+
+	for locale, expected := range map[string]string{
+		"":      "Your order #42 is ready",
+		"en-GB": "Your order #42 is ready, John",
+		"ru_RU": "Ваш заказ #42 готов",
+		"de":    "Your order #42 is ready",
+	} {
+		text, err := tmpl.Render(locale, vars)
+		require.Nil(t, err)
+		assert.Equal(t, expected, text)
+	}
+
+	_, err := tmpl.Render("en-GB", map[string]string{"order": "#42"})
+	assert.NotNil(t, err)
+}
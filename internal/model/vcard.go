@@ -0,0 +1,165 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// VCard is a contact card of contact messages.
+type VCard struct {
+	FullName     string       `json:"full_name"`
+	FirstName    string       `json:"first_name,omitempty"`
+	LastName     string       `json:"last_name,omitempty"`
+	Organization string       `json:"organization,omitempty"`
+	Title        string       `json:"title,omitempty"`
+	Phones       []VCardPhone `json:"phones"`
+	Emails       []string     `json:"emails,omitempty"`
+}
+
+// VCardPhone is a phone number of contact card.
+type VCardPhone struct {
+	Number string `json:"number"`
+	Type   string `json:"type,omitempty"`
+	WaID   string `json:"wa_id,omitempty"` // WhatsApp id of phone number owner.
+}
+
+// ParseVCard parses contact card in vCard format, unknown properties are skipped.
+func ParseVCard(vcard string) *VCard {
+	card := &VCard{Phones: []VCardPhone{}}
+	for _, line := range unfoldVCardLines(vcard) {
+		sepIndex := strings.Index(line, ":")
+		if sepIndex < 0 {
+			continue
+		}
+		params := strings.Split(line[:sepIndex], ";")
+		value := line[sepIndex+1:]
+
+		name := strings.ToUpper(params[0])
+		if groupIndex := strings.Index(name, "."); groupIndex >= 0 {
+			name = name[groupIndex+1:]
+		}
+
+		switch name {
+		case "FN":
+			card.FullName = unescapeVCardValue(value)
+		case "N":
+			names := splitVCardValue(value)
+			card.LastName = names[0]
+			if len(names) > 1 {
+				card.FirstName = names[1]
+			}
+		case "ORG":
+			card.Organization = strings.Join(splitVCardValue(value), " ")
+		case "TITLE":
+			card.Title = unescapeVCardValue(value)
+		case "TEL":
+			card.Phones = append(card.Phones, vCardPhone(params[1:], unescapeVCardValue(value)))
+		case "EMAIL":
+			card.Emails = append(card.Emails, unescapeVCardValue(value))
+		}
+	}
+	return card
+}
+
+// String formats contact card in vCard 3.0 format.
+func (v *VCard) String() string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCARD\nVERSION:3.0\n")
+	b.WriteString("N:" + escapeVCardValue(v.LastName) + ";" + escapeVCardValue(v.FirstName) + ";;;\n")
+	b.WriteString("FN:" + escapeVCardValue(v.FullName) + "\n")
+	if v.Organization != "" {
+		b.WriteString("ORG:" + escapeVCardValue(v.Organization) + "\n")
+	}
+	if v.Title != "" {
+		b.WriteString("TITLE:" + escapeVCardValue(v.Title) + "\n")
+	}
+	for _, phone := range v.Phones {
+		b.WriteString("TEL")
+		if phone.Type != "" {
+			b.WriteString(";type=" + strings.ToUpper(vCardParamValue(phone.Type)))
+		}
+		if phone.WaID != "" {
+			b.WriteString(";waid=" + vCardParamValue(phone.WaID))
+		}
+		b.WriteString(":" + escapeVCardValue(phone.Number) + "\n")
+	}
+	for _, email := range v.Emails {
+		b.WriteString("EMAIL:" + escapeVCardValue(email) + "\n")
+	}
+	b.WriteString("END:VCARD")
+	return b.String()
+}
+
+func vCardPhone(params []string, number string) VCardPhone {
+	phone := VCardPhone{Number: number}
+	for _, param := range params {
+		keyValue := strings.SplitN(param, "=", 2)
+		if len(keyValue) != 2 {
+			phone.Type = strings.ToLower(param)
+			continue
+		}
+		switch strings.ToUpper(keyValue[0]) {
+		case "TYPE":
+			phone.Type = strings.ToLower(keyValue[1])
+		case "WAID":
+			phone.WaID = keyValue[1]
+		}
+	}
+	return phone
+}
+
+func unfoldVCardLines(vcard string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(vcard, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func splitVCardValue(value string) []string {
+	var parts []string
+	var part strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			part.WriteString(unescapeVCardValue(`\` + string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ';':
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteRune(r)
+		}
+	}
+	return append(parts, part.String())
+}
+
+func unescapeVCardValue(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// escapeVCardValue escapes value of property, any line break including CR is escaped as newline
+// since it would split property otherwise.
+func escapeVCardValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\r\n", `\n`, "\r", `\n`, "\n", `\n`, ",", `\,`, ";", `\;`).Replace(value)
+}
+
+// vCardParamValue removes characters which can't be escaped in parameter values:
+// control characters and separators of parameters and values.
+func vCardParamValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`;:,"`, r) {
+			return -1
+		}
+		return r
+	}, value)
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVCard(t *testing.T) {
+	vcard := "BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"N:Doe;John;;;\r\n" +
+		"FN:John Doe\r\n" +
+		"ORG:Wapi\\, Inc.;Support\r\n" +
+		"TITLE:Support agent\r\n" +
+		"item1.TEL;waid=375447034810:+375 44 703-48-10\r\n" +
+		"item1.X-ABLabel:Mobile\r\n" +
+		"TEL;TYPE=WORK:+375 17 000-00-00\r\n" +
+		"EMAIL;TYPE=INTERNET:john.doe@exam\r\n" +
+		" ple.com\r\n" +
+		"END:VCARD"
+
+	assert.Equal(t, &VCard{
+		FullName:     "John Doe",
+		FirstName:    "John",
+		LastName:     "Doe",
+		Organization: "Wapi, Inc. Support",
+		Title:        "Support agent",
+		Phones: []VCardPhone{
+			{Number: "+375 44 703-48-10", WaID: "375447034810"},
+			{Number: "+375 17 000-00-00", Type: "work"},
+		},
+		Emails: []string{"john.doe@example.com"},
+	}, ParseVCard(vcard))
+}
+
+func TestParseEmptyVCard(t *testing.T) {
+	assert.Equal(t, &VCard{Phones: []VCardPhone{}}, ParseVCard(""))
+}
+
+func TestVCard_String(t *testing.T) {
+	card := &VCard{
+		FullName:     "John Doe",
+		FirstName:    "John",
+		LastName:     "Doe",
+		Organization: "Wapi, Inc.",
+		Phones:       []VCardPhone{{Number: "+375 44 703-48-10", Type: "cell", WaID: "375447034810"}},
+		Emails:       []string{"john.doe@example.com"},
+	}
+
+	assert.Equal(t, "BEGIN:VCARD\n"+
+		"VERSION:3.0\n"+
+		"N:Doe;John;;;\n"+
+		"FN:John Doe\n"+
+		"ORG:Wapi\\, Inc.\n"+
+		"TEL;type=CELL;waid=375447034810:+375 44 703-48-10\n"+
+		"EMAIL:john.doe@example.com\n"+
+		"END:VCARD", card.String())
+	assert.Equal(t, card, ParseVCard(card.String()))
+}
+
+func TestVCard_StringParams(t *testing.T) {
+	card := &VCard{
+		FullName: "John Doe",
+		Phones:   []VCardPhone{{Number: "+375 44 703-48-10", Type: "cell\nEMAIL:evil@example.com", WaID: "375447034810;type=work:1"}},
+	}
+
+	assert.Equal(t, "BEGIN:VCARD\n"+
+		"VERSION:3.0\n"+
+		"N:;;;;\n"+
+		"FN:John Doe\n"+
+		"TEL;type=CELLEMAILEVIL@EXAMPLE.COM;waid=375447034810type=work1:+375 44 703-48-10\n"+
+		"END:VCARD", card.String())
+	assert.Empty(t, ParseVCard(card.String()).Emails)
+}
+
+func TestVCard_StringLineBreaks(t *testing.T) {
+	card := &VCard{
+		FullName:     "John Doe\r\nEMAIL:evil@example.com",
+		Organization: "Wapi\rTEL:+000",
+		Phones:       []VCardPhone{{Number: "+375 44 703-48-10"}},
+	}
+
+	assert.Equal(t, "BEGIN:VCARD\n"+
+		"VERSION:3.0\n"+
+		"N:;;;;\n"+
+		"FN:John Doe\\nEMAIL:evil@example.com\n"+
+		"ORG:Wapi\\nTEL:+000\n"+
+		"TEL:+375 44 703-48-10\n"+
+		"END:VCARD", card.String())
+	parsed := ParseVCard(card.String())
+	assert.Empty(t, parsed.Emails)
+	assert.Len(t, parsed.Phones, 1)
+}
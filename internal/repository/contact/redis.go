@@ -0,0 +1,128 @@
+package contact
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/pkg/errors"
+	"github.com/r-erema/wapi/internal/model"
+)
+
+// RedisRepository caches information about WhatsApp accounts via Redis.
+type RedisRepository struct {
+	client              *redis.Client
+	storeExpirationTime time.Duration
+}
+
+// NewRedis creates redis repository.
+func NewRedis(host string) (*RedisRepository, error) {
+	redisClient := redis.NewClient(&redis.Options{Addr: host})
+	if _, err := redisClient.Ping().Result(); err != nil {
+		return nil, err
+	}
+	return &RedisRepository{client: redisClient, storeExpirationTime: time.Hour * 24 * 30}, nil
+}
+
+// SaveNumberExists stores whether account of jid is registered on WhatsApp for ttl.
+func (r *RedisRepository) SaveNumberExists(jid string, exists bool, ttl time.Duration) error {
+	return r.client.Set(numberExistsKey(jid), strconv.FormatBool(exists), ttl).Err()
+}
+
+// NumberExists retrieves whether account of jid is registered on WhatsApp, nil is returned if it isn't stored.
+func (r *RedisRepository) NumberExists(jid string) (*bool, error) {
+	value, err := r.client.Get(numberExistsKey(jid)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	exists, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &exists, nil
+}
+
+// SaveContacts replaces address book of session.
+func (r *RedisRepository) SaveContacts(sessionID string, contacts []model.Contact) error {
+	data, err := json.Marshal(contacts)
+	if err != nil {
+		return errors.Wrap(err, "can't marshal contacts")
+	}
+	return r.client.Set(contactsKey(sessionID), data, r.storeExpirationTime).Err()
+}
+
+// Contacts retrieves address book of session.
+func (r *RedisRepository) Contacts(sessionID string) ([]model.Contact, error) {
+	var contacts []model.Contact
+	if err := r.get(contactsKey(sessionID), &contacts); err != nil {
+		return nil, errors.Wrap(err, "can't get contacts")
+	}
+	return contacts, nil
+}
+
+// SaveChats replaces chats list of session.
+func (r *RedisRepository) SaveChats(sessionID string, chats []model.Chat) error {
+	data, err := json.Marshal(chats)
+	if err != nil {
+		return errors.Wrap(err, "can't marshal chats")
+	}
+	return r.client.Set(chatsKey(sessionID), data, r.storeExpirationTime).Err()
+}
+
+// Chats retrieves chats list of session.
+func (r *RedisRepository) Chats(sessionID string) ([]model.Chat, error) {
+	var chats []model.Chat
+	if err := r.get(chatsKey(sessionID), &chats); err != nil {
+		return nil, errors.Wrap(err, "can't get chats")
+	}
+	return chats, nil
+}
+
+// SaveProfile stores profile of account seen by session for ttl.
+func (r *RedisRepository) SaveProfile(sessionID string, profile *model.Profile, ttl time.Duration) error {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return errors.Wrap(err, "can't marshal profile")
+	}
+	return r.client.Set(profileKey(sessionID, profile.Jid), data, ttl).Err()
+}
+
+// Profile retrieves profile of account seen by session, nil is returned if it isn't stored.
+func (r *RedisRepository) Profile(sessionID, jid string) (*model.Profile, error) {
+	var profile *model.Profile
+	if err := r.get(profileKey(sessionID, jid), &profile); err != nil {
+		return nil, errors.Wrap(err, "can't get profile")
+	}
+	return profile, nil
+}
+
+func (r *RedisRepository) get(key string, value interface{}) error {
+	data, err := r.client.Get(key).Bytes()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+func numberExistsKey(jid string) string {
+	return "wapi_number_exists:" + jid
+}
+
+func contactsKey(sessionID string) string {
+	return "wapi_contacts:" + sessionID
+}
+
+func chatsKey(sessionID string) string {
+	return "wapi_chats:" + sessionID
+}
+
+func profileKey(sessionID, jid string) string {
+	return "wapi_profile:" + sessionID + ":" + jid
+}
@@ -0,0 +1,38 @@
+package media
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+const mediaFilePerm = 0644
+
+// FileSystemMedia stores media content in filesystem.
+type FileSystemMedia struct {
+	mediaStoragePath string
+	baseURL          string
+}
+
+// NewFileSystem creates File System Repository.
+func NewFileSystem(mediaStoragePath, baseURL string) (*FileSystemMedia, error) {
+	if _, err := os.Stat(mediaStoragePath); os.IsNotExist(err) {
+		err := os.MkdirAll(mediaStoragePath, os.ModePerm)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &FileSystemMedia{mediaStoragePath: mediaStoragePath, baseURL: baseURL}, nil
+}
+
+// SaveMedia stores media content and returns URL it's available by.
+func (f FileSystemMedia) SaveMedia(fileName string, content []byte) (string, error) {
+	if err := ioutil.WriteFile(f.resolveMediaFilePath(fileName), content, mediaFilePerm); err != nil {
+		return "", err
+	}
+	return f.baseURL + fileName, nil
+}
+
+func (f FileSystemMedia) resolveMediaFilePath(fileName string) string {
+	return fmt.Sprintf("%s/%s", f.mediaStoragePath, fileName)
+}
@@ -0,0 +1,29 @@
+package media
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSystemMedia_SaveMedia(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wapi_media")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	repo, err := NewFileSystem(dir+"/media", "https://wapi.host/get-media/")
+	require.Nil(t, err)
+
+	url, err := repo.SaveMedia("MSG_ID.jpg", []byte("image"))
+	require.Nil(t, err)
+	assert.Equal(t, "https://wapi.host/get-media/MSG_ID.jpg", url)
+
+	content, err := ioutil.ReadFile(dir + "/media/MSG_ID.jpg")
+	require.Nil(t, err)
+	assert.Equal(t, []byte("image"), content)
+}
@@ -1,12 +1,19 @@
 package message
 
 import (
+	"encoding/json"
 	"strconv"
 	"time"
 
+	"github.com/r-erema/wapi/internal/model"
+
 	"github.com/go-redis/redis"
+	"github.com/pkg/errors"
 )
 
+// Max attempts of updating message status, status is updated again if it's changed concurrently.
+const maxStatusUpdateAttempts = 10
+
 // RedisRepository stores messages metadata via Redis.
 type RedisRepository struct {
 	client              *redis.Client
@@ -43,6 +50,134 @@ func (r *RedisRepository) MessageTime(msgID string) (*time.Time, error) {
 	return &t, nil
 }
 
+// LockIdempotencyKey marks request with idempotency key as being processed,
+// false is returned if key is already locked.
+func (r *RedisRepository) LockIdempotencyKey(key string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(idempotencyLockKey(key), 1, ttl).Result()
+}
+
+// UnlockIdempotencyKey releases lock of idempotency key.
+func (r *RedisRepository) UnlockIdempotencyKey(key string) error {
+	return r.client.Del(idempotencyLockKey(key)).Err()
+}
+
+// SaveIdempotentResponse stores response of request with idempotency key.
+func (r *RedisRepository) SaveIdempotentResponse(key string, response *model.IdempotentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(idempotentResponseKey(key), data, ttl).Err()
+}
+
+// IdempotentResponse retrieves stored response of request with idempotency key, nil is returned if it isn't found.
+func (r *RedisRepository) IdempotentResponse(key string) (*model.IdempotentResponse, error) {
+	data, err := r.client.Get(idempotentResponseKey(key)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var response model.IdempotentResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateMessageStatus atomically applies update to stored delivery status of message sent by session,
+// current status is nil if it isn't stored yet. Status returned by update is saved, nil means nothing to save.
+// Saved status is returned, it's nil if nothing is saved.
+func (r *RedisRepository) UpdateMessageStatus(
+	sessionID,
+	msgID string,
+	update func(current *model.MessageStatus) *model.MessageStatus,
+) (*model.MessageStatus, error) {
+	key := statusKey(sessionID, msgID)
+	var saved *model.MessageStatus
+	transaction := func(tx *redis.Tx) error {
+		saved = nil
+		current, err := decodeMessageStatus(tx.Get(key).Bytes())
+		if err != nil {
+			return err
+		}
+		status := update(current)
+		if status == nil {
+			return nil
+		}
+		data, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(func(pipe redis.Pipeliner) error {
+			return pipe.Set(key, data, r.storeExpirationTime).Err()
+		})
+		if err == nil {
+			saved = status
+		}
+		return err
+	}
+
+	for attempt := 0; attempt < maxStatusUpdateAttempts; attempt++ {
+		err := r.client.Watch(transaction, key)
+		if err != redis.TxFailedErr {
+			return saved, err
+		}
+	}
+	return nil, errors.Errorf("status of message `%s` is concurrently updated too often", msgID)
+}
+
+// MessageStatus retrieves delivery status of message sent by session, nil is returned if it isn't found.
+func (r *RedisRepository) MessageStatus(sessionID, msgID string) (*model.MessageStatus, error) {
+	return decodeMessageStatus(r.client.Get(statusKey(sessionID, msgID)).Bytes())
+}
+
+func decodeMessageStatus(data []byte, err error) (*model.MessageStatus, error) {
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var status model.MessageStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SaveLastMessageTimestamp stores timestamp of last incoming message of session sent to webhook.
+func (r *RedisRepository) SaveLastMessageTimestamp(sessionID string, timestamp uint64) error {
+	return r.client.Set(lastTimestampKey(sessionID), timestamp, r.storeExpirationTime).Err()
+}
+
+// LastMessageTimestamp retrieves timestamp of last incoming message of session sent to webhook,
+// 0 is returned if it isn't found.
+func (r *RedisRepository) LastMessageTimestamp(sessionID string) (uint64, error) {
+	timestamp, err := r.client.Get(lastTimestampKey(sessionID)).Uint64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return timestamp, err
+}
+
 func timeKey(msgID string) string {
 	return "msg_timestamp:" + msgID
 }
+
+func statusKey(sessionID, msgID string) string {
+	return "wapi_message_status:" + sessionID + ":" + msgID
+}
+
+func lastTimestampKey(sessionID string) string {
+	return "wapi_last_message_timestamp:" + sessionID
+}
+
+func idempotencyLockKey(key string) string {
+	return "wapi_idempotency_lock:" + key
+}
+
+func idempotentResponseKey(key string) string {
+	return "wapi_idempotent_response:" + key
+}
@@ -0,0 +1,207 @@
+package queue
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+
+	"github.com/go-redis/redis"
+	"github.com/pkg/errors"
+)
+
+const sessionsKey = "wapi_queue_sessions"
+
+// RedisRepository stores queues of outgoing messages and bulk jobs via Redis.
+type RedisRepository struct {
+	client              *redis.Client
+	storeExpirationTime time.Duration
+}
+
+// storedMessage is a queued message along with its content stored in Redis.
+type storedMessage struct {
+	model.QueuedMessage
+	Proto []byte `json:"proto"`
+}
+
+// NewRedis creates redis repository.
+func NewRedis(host string) (*RedisRepository, error) {
+	redisClient := redis.NewClient(&redis.Options{Addr: host})
+	if _, err := redisClient.Ping().Result(); err != nil {
+		return nil, err
+	}
+	return &RedisRepository{client: redisClient, storeExpirationTime: time.Hour * 24 * 30}, nil
+}
+
+// Enqueue stores message and pushes it to the tail of session's queue.
+func (r *RedisRepository) Enqueue(msg *model.QueuedMessage) error {
+	if err := r.SaveQueuedMessage(msg); err != nil {
+		return err
+	}
+	_, err := r.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SAdd(sessionsKey, msg.SessionID)
+		pipe.LPush(queueKey(msg.SessionID), msg.ID)
+		return nil
+	})
+	return errors.Wrap(err, "can't push message to queue")
+}
+
+// Next pops message from the head of session's queue and marks it as being processed,
+// it blocks until message is available or timeout expires, nil message is returned on timeout.
+func (r *RedisRepository) Next(sessionID string, timeout time.Duration) (*model.QueuedMessage, error) {
+	msgID, err := r.client.BRPopLPush(queueKey(sessionID), processingKey(sessionID), timeout).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "can't pop message from queue")
+	}
+	msg, err := r.QueuedMessage(msgID)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, r.client.LRem(processingKey(sessionID), 0, msgID).Err()
+	}
+	return msg, nil
+}
+
+// Done removes processed message from session's queue.
+func (r *RedisRepository) Done(msg *model.QueuedMessage) error {
+	return r.client.LRem(processingKey(msg.SessionID), 0, msg.ID).Err()
+}
+
+// Retry stores message which sending failed and moves it from processing to retries of session,
+// it's returned to session's queue when its retry time comes.
+func (r *RedisRepository) Retry(msg *model.QueuedMessage) error {
+	if msg.RetryAt == nil {
+		return errors.Errorf("retry time of message `%s` isn't set", msg.ID)
+	}
+	if err := r.SaveQueuedMessage(msg); err != nil {
+		return err
+	}
+	_, err := r.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(retryKey(msg.SessionID), redis.Z{Score: float64(msg.RetryAt.UnixNano()), Member: msg.ID})
+		pipe.LRem(processingKey(msg.SessionID), 0, msg.ID)
+		return nil
+	})
+	return errors.Wrap(err, "can't move message to retries")
+}
+
+// RequeueDue pushes retried messages of session which retry time is before given time to the tail of its queue.
+func (r *RedisRepository) RequeueDue(sessionID string, until time.Time) error {
+	msgIDs, err := r.client.ZRangeByScore(retryKey(sessionID), redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(until.UnixNano(), 10),
+	}).Result()
+	if err != nil {
+		return errors.Wrap(err, "can't read due retries")
+	}
+	for _, msgID := range msgIDs {
+		_, err := r.client.TxPipelined(func(pipe redis.Pipeliner) error {
+			pipe.ZRem(retryKey(sessionID), msgID)
+			pipe.LPush(queueKey(sessionID), msgID)
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "can't requeue message `%s`", msgID)
+		}
+	}
+	return nil
+}
+
+// SaveQueuedMessage updates stored message.
+func (r *RedisRepository) SaveQueuedMessage(msg *model.QueuedMessage) error {
+	data, err := json.Marshal(storedMessage{QueuedMessage: *msg, Proto: msg.Message})
+	if err != nil {
+		return errors.Wrap(err, "can't marshal queued message")
+	}
+	return r.client.Set(messageKey(msg.ID), data, r.storeExpirationTime).Err()
+}
+
+// QueuedMessage retrieves message by its id, nil is returned if message isn't found.
+func (r *RedisRepository) QueuedMessage(msgID string) (*model.QueuedMessage, error) {
+	data, err := r.client.Get(messageKey(msgID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored storedMessage
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, errors.Wrap(err, "can't unmarshal queued message")
+	}
+	stored.QueuedMessage.Message = stored.Proto
+	return &stored.QueuedMessage, nil
+}
+
+// RestoreQueues returns messages which processing was interrupted back to the heads of queues
+// and provides ids of sessions having queues.
+func (r *RedisRepository) RestoreQueues() ([]string, error) {
+	sessionIDs, err := r.client.SMembers(sessionsKey).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read sessions having queues")
+	}
+	for _, sessionID := range sessionIDs {
+		for {
+			msgID, err := r.client.RPop(processingKey(sessionID)).Result()
+			if err == redis.Nil {
+				break
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "can't restore queue of session `%s`", sessionID)
+			}
+			if err := r.client.RPush(queueKey(sessionID), msgID).Err(); err != nil {
+				return nil, errors.Wrapf(err, "can't restore queue of session `%s`", sessionID)
+			}
+		}
+	}
+	return sessionIDs, nil
+}
+
+// SaveBulkJob stores job.
+func (r *RedisRepository) SaveBulkJob(job *model.BulkJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return errors.Wrap(err, "can't marshal bulk job")
+	}
+	return r.client.Set(bulkJobKey(job.ID), data, r.storeExpirationTime).Err()
+}
+
+// BulkJob retrieves job by its id, nil is returned if job isn't found.
+func (r *RedisRepository) BulkJob(jobID string) (*model.BulkJob, error) {
+	data, err := r.client.Get(bulkJobKey(jobID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job model.BulkJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, errors.Wrap(err, "can't unmarshal bulk job")
+	}
+	return &job, nil
+}
+
+func messageKey(msgID string) string {
+	return "wapi_queued_message:" + msgID
+}
+
+func queueKey(sessionID string) string {
+	return "wapi_queue:" + sessionID
+}
+
+func processingKey(sessionID string) string {
+	return "wapi_queue_processing:" + sessionID
+}
+
+func retryKey(sessionID string) string {
+	return "wapi_queue_retry:" + sessionID
+}
+
+func bulkJobKey(jobID string) string {
+	return "wapi_bulk_job:" + jobID
+}
@@ -12,6 +12,30 @@ type Message interface {
 	SaveMessageTime(msgID string, time time.Time) error
 	// MessageTime retrieves message time from repository.
 	MessageTime(msgID string) (*time.Time, error)
+	// LockIdempotencyKey marks request with idempotency key as being processed,
+	// false is returned if key is already locked.
+	LockIdempotencyKey(key string, ttl time.Duration) (bool, error)
+	// UnlockIdempotencyKey releases lock of idempotency key.
+	UnlockIdempotencyKey(key string) error
+	// SaveIdempotentResponse stores response of request with idempotency key.
+	SaveIdempotentResponse(key string, response *model.IdempotentResponse, ttl time.Duration) error
+	// IdempotentResponse retrieves stored response of request with idempotency key, nil is returned if it isn't found.
+	IdempotentResponse(key string) (*model.IdempotentResponse, error)
+	// UpdateMessageStatus atomically applies update to stored delivery status of message sent by session,
+	// current status is nil if it isn't stored yet. Status returned by update is saved, nil means nothing to save.
+	// Saved status is returned, it's nil if nothing is saved.
+	UpdateMessageStatus(
+		sessionID,
+		msgID string,
+		update func(current *model.MessageStatus) *model.MessageStatus,
+	) (*model.MessageStatus, error)
+	// MessageStatus retrieves delivery status of message sent by session, nil is returned if it isn't found.
+	MessageStatus(sessionID, msgID string) (*model.MessageStatus, error)
+	// SaveLastMessageTimestamp stores timestamp of last incoming message of session sent to webhook.
+	SaveLastMessageTimestamp(sessionID string, timestamp uint64) error
+	// LastMessageTimestamp retrieves timestamp of last incoming message of session sent to webhook,
+	// 0 is returned if it isn't found.
+	LastMessageTimestamp(sessionID string) (uint64, error)
 }
 
 // Session stores sessions metadata.
@@ -25,3 +49,87 @@ type Session interface {
 	// RemoveSession removes session from repository.
 	RemoveSession(sessionID string) error
 }
+
+// Media stores content of media messages.
+type Media interface {
+	// SaveMedia stores media content and returns URL it's available by.
+	SaveMedia(fileName string, content []byte) (string, error)
+}
+
+// Queue stores outgoing messages queued to be sent asynchronously, each session has its own queue.
+type Queue interface {
+	// Enqueue stores message and pushes it to the tail of session's queue.
+	Enqueue(msg *model.QueuedMessage) error
+	// Next pops message from the head of session's queue and marks it as being processed,
+	// it blocks until message is available or timeout expires, nil message is returned on timeout.
+	Next(sessionID string, timeout time.Duration) (*model.QueuedMessage, error)
+	// Done removes processed message from session's queue.
+	Done(msg *model.QueuedMessage) error
+	// Retry stores message which sending failed and moves it from processing to retries of session,
+	// it's returned to session's queue when its retry time comes.
+	Retry(msg *model.QueuedMessage) error
+	// RequeueDue pushes retried messages of session which retry time is before given time to the tail of its queue.
+	RequeueDue(sessionID string, until time.Time) error
+	// SaveQueuedMessage updates stored message.
+	SaveQueuedMessage(msg *model.QueuedMessage) error
+	// QueuedMessage retrieves message by its id, nil is returned if message isn't found.
+	QueuedMessage(msgID string) (*model.QueuedMessage, error)
+	// RestoreQueues returns messages which processing was interrupted back to the heads of queues
+	// and provides ids of sessions having queues.
+	RestoreQueues() ([]string, error)
+}
+
+// BulkJob stores jobs of sending messages to lists of recipients.
+type BulkJob interface {
+	// SaveBulkJob stores job.
+	SaveBulkJob(job *model.BulkJob) error
+	// BulkJob retrieves job by its id, nil is returned if job isn't found.
+	BulkJob(jobID string) (*model.BulkJob, error)
+}
+
+// Schedule stores messages scheduled to be sent at specified time.
+type Schedule interface {
+	// Schedule stores message and adds it to schedule at its sending time.
+	Schedule(msg *model.ScheduledMessage) error
+	// Unschedule removes message from schedule, false is returned if message isn't scheduled,
+	// so only one of concurrent callers succeeds.
+	Unschedule(msgID string) (bool, error)
+	// DueMessageIDs provides ids of scheduled messages which sending time is before given time.
+	DueMessageIDs(until time.Time) ([]string, error)
+	// SaveScheduledMessage updates stored message.
+	SaveScheduledMessage(msg *model.ScheduledMessage) error
+	// ScheduledMessage retrieves message by its id, nil is returned if message isn't found.
+	ScheduledMessage(msgID string) (*model.ScheduledMessage, error)
+}
+
+// Template stores text message templates.
+type Template interface {
+	// SaveTemplate stores template.
+	SaveTemplate(template *model.Template) error
+	// Template retrieves template by its id, nil is returned if template isn't found.
+	Template(templateID string) (*model.Template, error)
+	// AllTemplates retrieves all templates sorted by id.
+	AllTemplates() ([]*model.Template, error)
+	// RemoveTemplate removes template.
+	RemoveTemplate(templateID string) error
+}
+
+// Contact caches information about WhatsApp accounts.
+type Contact interface {
+	// SaveNumberExists stores whether account of jid is registered on WhatsApp for ttl.
+	SaveNumberExists(jid string, exists bool, ttl time.Duration) error
+	// NumberExists retrieves whether account of jid is registered on WhatsApp, nil is returned if it isn't stored.
+	NumberExists(jid string) (*bool, error)
+	// SaveContacts replaces address book of session.
+	SaveContacts(sessionID string, contacts []model.Contact) error
+	// Contacts retrieves address book of session.
+	Contacts(sessionID string) ([]model.Contact, error)
+	// SaveChats replaces chats list of session.
+	SaveChats(sessionID string, chats []model.Chat) error
+	// Chats retrieves chats list of session.
+	Chats(sessionID string) ([]model.Chat, error)
+	// SaveProfile stores profile of account seen by session for ttl.
+	SaveProfile(sessionID string, profile *model.Profile, ttl time.Duration) error
+	// Profile retrieves profile of account seen by session, nil is returned if it isn't stored.
+	Profile(sessionID, jid string) (*model.Profile, error)
+}
@@ -0,0 +1,95 @@
+package schedule
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+
+	"github.com/go-redis/redis"
+	"github.com/pkg/errors"
+)
+
+// scheduleKey is a key of sorted set of scheduled message ids scored by their sending time.
+const scheduleKey = "wapi_schedule"
+
+// RedisRepository stores scheduled messages via Redis.
+type RedisRepository struct {
+	client              *redis.Client
+	storeExpirationTime time.Duration
+}
+
+// storedMessage is a scheduled message along with its content stored in Redis.
+type storedMessage struct {
+	model.ScheduledMessage
+	Proto []byte `json:"proto"`
+}
+
+// NewRedis creates redis repository.
+func NewRedis(host string) (*RedisRepository, error) {
+	redisClient := redis.NewClient(&redis.Options{Addr: host})
+	if _, err := redisClient.Ping().Result(); err != nil {
+		return nil, err
+	}
+	return &RedisRepository{client: redisClient, storeExpirationTime: time.Hour * 24 * 30}, nil
+}
+
+// Schedule stores message and adds it to schedule at its sending time.
+func (r *RedisRepository) Schedule(msg *model.ScheduledMessage) error {
+	if err := r.SaveScheduledMessage(msg); err != nil {
+		return err
+	}
+	err := r.client.ZAdd(scheduleKey, redis.Z{Score: float64(msg.SendAt.Unix()), Member: msg.ID}).Err()
+	return errors.Wrap(err, "can't add message to schedule")
+}
+
+// Unschedule removes message from schedule, false is returned if message isn't scheduled,
+// so only one of concurrent callers succeeds.
+func (r *RedisRepository) Unschedule(msgID string) (bool, error) {
+	removed, err := r.client.ZRem(scheduleKey, msgID).Result()
+	if err != nil {
+		return false, errors.Wrap(err, "can't remove message from schedule")
+	}
+	return removed > 0, nil
+}
+
+// DueMessageIDs provides ids of scheduled messages which sending time is before given time.
+func (r *RedisRepository) DueMessageIDs(until time.Time) ([]string, error) {
+	ids, err := r.client.ZRangeByScore(scheduleKey, redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(until.Unix(), 10),
+	}).Result()
+	return ids, errors.Wrap(err, "can't read due messages of schedule")
+}
+
+// SaveScheduledMessage updates stored message, it's kept at least for storing time after its sending time.
+func (r *RedisRepository) SaveScheduledMessage(msg *model.ScheduledMessage) error {
+	data, err := json.Marshal(storedMessage{ScheduledMessage: *msg, Proto: msg.Message})
+	if err != nil {
+		return errors.Wrap(err, "can't marshal scheduled message")
+	}
+	expiration := time.Until(msg.SendAt) + r.storeExpirationTime
+	return r.client.Set(messageKey(msg.ID), data, expiration).Err()
+}
+
+// ScheduledMessage retrieves message by its id, nil is returned if message isn't found.
+func (r *RedisRepository) ScheduledMessage(msgID string) (*model.ScheduledMessage, error) {
+	data, err := r.client.Get(messageKey(msgID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored storedMessage
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, errors.Wrap(err, "can't unmarshal scheduled message")
+	}
+	stored.ScheduledMessage.Message = stored.Proto
+	return &stored.ScheduledMessage, nil
+}
+
+func messageKey(msgID string) string {
+	return "wapi_scheduled_message:" + msgID
+}
@@ -0,0 +1,106 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/r-erema/wapi/internal/model"
+)
+
+const (
+	templateFileExt  = ".json"
+	templateFilePerm = 0644
+)
+
+// FileSystemTemplate stores templates in filesystem.
+type FileSystemTemplate struct {
+	templateStoragePath string
+}
+
+// NewFileSystem creates File System Repository.
+func NewFileSystem(templateStoragePath string) (*FileSystemTemplate, error) {
+	if _, err := os.Stat(templateStoragePath); os.IsNotExist(err) {
+		err := os.MkdirAll(templateStoragePath, os.ModePerm)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &FileSystemTemplate{templateStoragePath: templateStoragePath}, nil
+}
+
+// SaveTemplate stores template.
+func (f FileSystemTemplate) SaveTemplate(template *model.Template) error {
+	filePath, err := f.resolveTemplateFilePath(template.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, data, templateFilePerm)
+}
+
+// Template retrieves template by its id, nil is returned if template isn't found
+// including the case of invalid id which template can't have.
+func (f FileSystemTemplate) Template(templateID string) (*model.Template, error) {
+	filePath, err := f.resolveTemplateFilePath(templateID)
+	if err != nil {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	template := &model.Template{}
+	if err := json.Unmarshal(data, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// AllTemplates retrieves all templates sorted by id.
+func (f FileSystemTemplate) AllTemplates() ([]*model.Template, error) {
+	files, err := ioutil.ReadDir(f.templateStoragePath)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]*model.Template, 0, len(files))
+	for _, file := range files {
+		if path.Ext(file.Name()) != templateFileExt {
+			continue
+		}
+		template, err := f.Template(strings.TrimSuffix(file.Name(), templateFileExt))
+		if err != nil {
+			return nil, err
+		}
+		if template != nil {
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+// RemoveTemplate removes template.
+func (f FileSystemTemplate) RemoveTemplate(templateID string) error {
+	filePath, err := f.resolveTemplateFilePath(templateID)
+	if err != nil {
+		return err
+	}
+	return os.Remove(filePath)
+}
+
+// resolveTemplateFilePath resolves path of template file, id is validated to keep the path inside storage directory.
+func (f FileSystemTemplate) resolveTemplateFilePath(templateID string) (string, error) {
+	if err := model.ValidateTemplateID(templateID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s%s", f.templateStoragePath, templateID, templateFileExt), nil
+}
@@ -0,0 +1,68 @@
+package template
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/r-erema/wapi/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSystemTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wapi_templates")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	repo, err := NewFileSystem(dir + "/templates")
+	require.Nil(t, err)
+
+	for _, id := range []string{"payment_due", "order_ready"} {
+		require.Nil(t, repo.SaveTemplate(&model.Template{
+			ID:            id,
+			DefaultLocale: "en",
+			Locales:       map[string]string{"en": "Hi {{.name}}"},
+		}))
+	}
+
+	template, err := repo.Template("order_ready")
+	require.Nil(t, err)
+	assert.Equal(t, "Hi {{.name}}", template.Locales["en"])
+
+	templates, err := repo.AllTemplates()
+	require.Nil(t, err)
+	require.Len(t, templates, 2)
+	assert.Equal(t, "order_ready", templates[0].ID)
+	assert.Equal(t, "payment_due", templates[1].ID)
+
+	require.Nil(t, repo.RemoveTemplate("order_ready"))
+	template, err = repo.Template("order_ready")
+	require.Nil(t, err)
+	assert.Nil(t, template)
+}
+
+func TestFileSystemTemplateInvalidID(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wapi_templates")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	repo, err := NewFileSystem(dir + "/templates")
+	require.Nil(t, err)
+	require.Nil(t, ioutil.WriteFile(dir+"/secret.json", []byte(`{"id":"secret"}`), 0644))
+
+	assert.NotNil(t, repo.SaveTemplate(&model.Template{ID: "../secret"}))
+
+	template, err := repo.Template("../secret")
+	require.Nil(t, err)
+	assert.Nil(t, template)
+
+	assert.NotNil(t, repo.RemoveTemplate("../secret"))
+	_, err = os.Stat(dir + "/secret.json")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+	"github.com/r-erema/wapi/internal/model"
+)
+
+// Ack levels sent by WhatsApp mapped on delivery statuses.
+var ackStatuses = map[int]string{
+	-1: model.ErrorDeliveryStatus,
+	0:  model.PendingDeliveryStatus,
+	1:  model.SentDeliveryStatus,
+	2:  model.DeliveredDeliveryStatus,
+	3:  model.ReadDeliveryStatus,
+	4:  model.PlayedDeliveryStatus,
+}
+
+// Ack event of message(s), `id` is a string for "ack" command and an array for "acks" command.
+type ackEvent struct {
+	Cmd         string          `json:"cmd"`
+	ID          json.RawMessage `json:"id"`
+	Ack         int             `json:"ack"`
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	Participant string          `json:"participant"`
+	T           int64           `json:"t"`
+}
+
+// HandleJsonMessage handles JSON events: tracks delivery statuses of messages sent by session
+// and notifies webhook about changes of groups and presences of subscribed contacts.
+func (h *Handler) HandleJsonMessage(message string) { // nolint
+	var event []json.RawMessage
+	if err := json.Unmarshal([]byte(message), &event); err != nil || len(event) < 2 {
+		return
+	}
+	var eventType string
+	if err := json.Unmarshal(event[0], &eventType); err != nil {
+		return
+	}
+	switch eventType {
+	case "Msg", "MsgInfo":
+		h.handleAck(event[1])
+	case "Chat":
+		h.handleChatAction(event[1])
+	case "Presence":
+		h.handlePresence(event[1])
+	}
+}
+
+// Tracks delivery status of messages by ack event.
+func (h *Handler) handleAck(event json.RawMessage) {
+	var ack ackEvent
+	if err := json.Unmarshal(event, &ack); err != nil || (ack.Cmd != "ack" && ack.Cmd != "acks") {
+		return
+	}
+
+	status, ok := ackStatuses[ack.Ack]
+	if !ok || !h.isOwnJid(ack.To) {
+		return
+	}
+
+	var ids []string
+	if err := json.Unmarshal(ack.ID, &ids); err != nil {
+		var id string
+		if err = json.Unmarshal(ack.ID, &id); err != nil {
+			log.Printf("can't parse ack msg id `%s`: %v\n", ack.ID, err)
+			return
+		}
+		ids = []string{id}
+	}
+
+	at := time.Now()
+	if ack.T > 0 {
+		at = time.Unix(ack.T, 0)
+	}
+	for _, id := range ids {
+		h.updateMessageStatus(id, whatsapp.NormalizeUserJid(ack.From), whatsapp.NormalizeUserJid(ack.Participant), status, at)
+	}
+}
+
+// Stores new status of message and notifies webhook, statuses not advancing delivery progress are ignored.
+func (h *Handler) updateMessageStatus(msgID, chatID, participant, status string, at time.Time) {
+	msgStatus, err := h.messageRepo.UpdateMessageStatus(
+		h.Session.SessionID,
+		msgID,
+		func(current *model.MessageStatus) *model.MessageStatus {
+			if current == nil {
+				current = model.NewMessageStatus(msgID, h.Session.SessionID, chatID)
+			}
+			if !current.Advance(status, at) {
+				return nil
+			}
+			return current
+		},
+	)
+	if err != nil {
+		log.Printf("can't save status of msg `%s`: %v\n", msgID, err)
+		return
+	}
+	if msgStatus == nil {
+		return
+	}
+
+	h.postToWebhook(&MessageStatusPayload{
+		Type:        MessageStatusPayloadType,
+		ID:          msgID,
+		ChatID:      msgStatus.ChatID,
+		Participant: participant,
+		SessionID:   h.Session.SessionID,
+		Status:      status,
+		Timestamp:   uint64(at.Unix()),
+	})
+}
+
+// Checks whether jid belongs to account of session.
+func (h *Handler) isOwnJid(jid string) bool {
+	if jid == "" || h.Session.WhatsAppSession == nil {
+		return false
+	}
+	return jidUser(jid) == jidUser(h.Session.WhatsAppSession.Wid)
+}
+
+func jidUser(jid string) string {
+	return strings.SplitN(jid, "@", 2)[0]
+}
@@ -0,0 +1,133 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleJsonMessage(t *testing.T) {
+	tests := []struct {
+		name          string
+		message       string
+		stored        *model.MessageStatus
+		expectStatus  string
+		expectChatID  string
+		expectUpdates int
+	}{
+		{
+			name:          "New status",
+			message:       `["Msg",{"cmd":"ack","id":"MSG_ID","ack":2,"from":"375440000001@c.us","to":"375440000000@c.us","t":1600000000}]`,
+			expectStatus:  model.DeliveredDeliveryStatus,
+			expectChatID:  "375440000001@s.whatsapp.net",
+			expectUpdates: 1,
+		},
+		{
+			name:    "Status advanced once by repeated acks",
+			message: `["MsgInfo",{"cmd":"acks","id":["MSG_ID","MSG_ID"],"ack":3,"from":"375440000001@c.us","to":"375440000000@c.us","t":1600000000}]`,
+			stored: &model.MessageStatus{
+				ID:         "MSG_ID",
+				ChatID:     "375440000001@s.whatsapp.net",
+				Status:     model.SentDeliveryStatus,
+				Timestamps: map[string]time.Time{model.SentDeliveryStatus: time.Unix(1500000000, 0)},
+			},
+			expectStatus:  model.ReadDeliveryStatus,
+			expectChatID:  "375440000001@s.whatsapp.net",
+			expectUpdates: 1,
+		},
+		{
+			name:    "Status not advanced",
+			message: `["Msg",{"cmd":"ack","id":"MSG_ID","ack":2,"from":"375440000001@c.us","to":"375440000000@c.us","t":1600000000}]`,
+			stored: &model.MessageStatus{
+				ID:         "MSG_ID",
+				Status:     model.ReadDeliveryStatus,
+				Timestamps: map[string]time.Time{},
+			},
+		},
+		{
+			name:    "Error after read",
+			message: `["Msg",{"cmd":"ack","id":"MSG_ID","ack":-1,"from":"375440000001@c.us","to":"375440000000@c.us","t":1600000000}]`,
+			stored: &model.MessageStatus{
+				ID:         "MSG_ID",
+				ChatID:     "375440000001@s.whatsapp.net",
+				Status:     model.ReadDeliveryStatus,
+				Timestamps: map[string]time.Time{},
+			},
+			expectStatus:  model.ErrorDeliveryStatus,
+			expectChatID:  "375440000001@s.whatsapp.net",
+			expectUpdates: 1,
+		},
+		{
+			name:    "Ack of incoming message",
+			message: `["Msg",{"cmd":"ack","id":"MSG_ID","ack":3,"from":"375440000000@c.us","to":"375440000001@c.us","t":1600000000}]`,
+		},
+		{
+			name:    "Not an ack",
+			message: `["Cmd",{"type":"disconnect","kind":"replaced"}]`,
+		},
+		{
+			name:    "Invalid JSON",
+			message: `["Msg",`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			stored := tt.stored
+			msgRepo := mock.NewMockMessage(c)
+			msgRepo.EXPECT().UpdateMessageStatus("_sid_", "MSG_ID", gomock.Any()).
+				DoAndReturn(func(_, _ string, update func(*model.MessageStatus) *model.MessageStatus) (*model.MessageStatus, error) {
+					if status := update(stored); status != nil {
+						stored = status
+						return status, nil
+					}
+					return nil, nil
+				}).
+				MinTimes(tt.expectUpdates)
+
+			var payload service.MessageStatusPayload
+			client := mock.NewMockClient(c)
+			client.EXPECT().
+				Post("webhook/url/_sid_", "application/json", gomock.Any()).
+				DoAndReturn(func(url, contentType string, body io.Reader) (*http.Response, error) {
+					require.Nil(t, json.NewDecoder(body).Decode(&payload))
+					return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+				}).
+				Times(tt.expectUpdates)
+
+			m := jsonInfra.MarshallCallback(json.Marshal)
+			sess := &model.WapiSession{SessionID: "_sid_", WhatsAppSession: &whatsapp.Session{Wid: "375440000000@c.us"}}
+			h := service.NewMsgHandler(nil, sess, msgRepo, nil, nil, nil, client, &m, 0, "webhook/url/")
+			h.HandleJsonMessage(tt.message)
+
+			if tt.expectUpdates == 0 {
+				return
+			}
+			assert.Equal(t, tt.expectStatus, stored.Status)
+			assert.Equal(t, tt.expectChatID, stored.ChatID)
+			assert.Equal(t, time.Unix(1600000000, 0), stored.Timestamps[tt.expectStatus])
+			assert.Equal(t, service.MessageStatusPayloadType, payload.Type)
+			assert.Equal(t, tt.expectStatus, payload.Status)
+			assert.Equal(t, "_sid_", payload.SessionID)
+			assert.Equal(t, uint64(1600000000), payload.Timestamp)
+		})
+	}
+}
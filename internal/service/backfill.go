@@ -0,0 +1,57 @@
+package service
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+)
+
+// HandleChatList catches up on messages received while session wasn't listening, chat list is received after each login.
+// Latest messages of chats updated after init timestamp are loaded and handled as incoming ones,
+// messages already sent to webhook are skipped.
+func (h *Handler) HandleChatList(chats []whatsapp.Chat) {
+	if h.BackfillCount == 0 {
+		return
+	}
+	for i := range chats {
+		lastMessageTime, err := strconv.ParseUint(chats[i].LastMessageTime, 10, 64)
+		if err != nil || lastMessageTime <= h.InitTimestamp {
+			continue
+		}
+		messages, err := h.Connection.LoadMessages(chats[i].Jid, h.BackfillCount)
+		if err != nil {
+			log.Printf("can't load messages of chat `%s` of session `%s`: %v\n", chats[i].Jid, h.Session.SessionID, err)
+			continue
+		}
+		log.Printf("catching up on %d messages of chat `%s` of session `%s`", len(messages), chats[i].Jid, h.Session.SessionID)
+		for _, msg := range messages {
+			h.handleLoadedMessage(msg)
+		}
+	}
+}
+
+// handleLoadedMessage dispatches loaded message to handler of its type.
+func (h *Handler) handleLoadedMessage(msg *proto.WebMessageInfo) {
+	switch m := whatsapp.ParseProtoMessage(msg).(type) {
+	case whatsapp.TextMessage:
+		h.HandleTextMessage(&m)
+	case whatsapp.ImageMessage:
+		h.HandleImageMessage(m)
+	case whatsapp.DocumentMessage:
+		h.HandleDocumentMessage(m)
+	case whatsapp.AudioMessage:
+		h.HandleAudioMessage(m)
+	case whatsapp.VideoMessage:
+		h.HandleVideoMessage(m)
+	case whatsapp.LocationMessage:
+		h.HandleLocationMessage(m)
+	case whatsapp.LiveLocationMessage:
+		h.HandleLiveLocationMessage(m)
+	case whatsapp.ContactMessage:
+		h.HandleContactMessage(m)
+	default:
+		h.HandleRawMessage(msg)
+	}
+}
@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"testing"
+
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleChatList(t *testing.T) {
+	const initTimestamp = 1000
+	tests := []struct {
+		name          string
+		backfillCount int
+		chats         []whatsapp.Chat
+		loadErr       error
+		expectLoad    bool
+	}{
+		{
+			name:          "Catching up",
+			backfillCount: 5,
+			chats: []whatsapp.Chat{
+				{Jid: "375000000001@s.whatsapp.net", LastMessageTime: strconv.Itoa(initTimestamp + 20)},
+				{Jid: "375000000002@s.whatsapp.net", LastMessageTime: strconv.Itoa(initTimestamp - 20)},
+				{Jid: "375000000003@s.whatsapp.net"},
+			},
+			expectLoad: true,
+		},
+		{
+			name:          "Loading error",
+			backfillCount: 5,
+			chats:         []whatsapp.Chat{{Jid: "375000000001@s.whatsapp.net", LastMessageTime: strconv.Itoa(initTimestamp + 20)}},
+			loadErr:       errors.New("connection closed"),
+			expectLoad:    true,
+		},
+		{
+			name:  "Backfill disabled",
+			chats: []whatsapp.Chat{{Jid: "375000000001@s.whatsapp.net", LastMessageTime: strconv.Itoa(initTimestamp + 20)}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			conn := mock.NewMockConn(c)
+			msgRepo := mock.NewMockMessage(c)
+			client := mock.NewMockClient(c)
+
+			if tt.expectLoad {
+				conn.EXPECT().
+					LoadMessages("375000000001@s.whatsapp.net", tt.backfillCount).
+					Return([]*proto.WebMessageInfo{
+						loadedTextMessage("OLD_MSG_ID", initTimestamp-10, "old"),
+						loadedTextMessage("NEW_MSG_ID", initTimestamp+10, "new"),
+					}, tt.loadErr)
+			}
+			if tt.expectLoad && tt.loadErr == nil {
+				msgRepo.EXPECT().MessageTime("wapi_sent_message:OLD_MSG_ID").Return(nil, errors.New("message not found"))
+				msgRepo.EXPECT().MessageTime("wapi_sent_message:NEW_MSG_ID").Return(nil, errors.New("message not found"))
+				msgRepo.EXPECT().SaveMessageTime("wapi_sent_message:NEW_MSG_ID", gomock.Any()).Return(nil)
+				msgRepo.EXPECT().SaveLastMessageTimestamp("_sid_", uint64(initTimestamp+10)).Return(nil)
+				client.EXPECT().
+					Post("/webhook_url/_sid_", gomock.Any(), gomock.Any()).
+					Return(&http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil)
+			}
+
+			marshal := jsonInfra.MarshallCallback(json.Marshal)
+			h := service.NewMsgHandler(
+				conn,
+				&model.WapiSession{SessionID: "_sid_", WhatsAppSession: &whatsapp.Session{}},
+				msgRepo,
+				nil,
+				mock.NewMockConnections(c),
+				mock.NewMockSession(c),
+				client,
+				&marshal,
+				initTimestamp,
+				"/webhook_url/",
+			)
+			h.BackfillCount = tt.backfillCount
+			h.HandleChatList(tt.chats)
+		})
+	}
+}
+
+func loadedTextMessage(id string, timestamp uint64, text string) *proto.WebMessageInfo {
+	chatID, fromMe := "375000000001@s.whatsapp.net", false
+	return &proto.WebMessageInfo{
+		Key:              &proto.MessageKey{Id: &id, RemoteJid: &chatID, FromMe: &fromMe},
+		MessageTimestamp: &timestamp,
+		Message:          &proto.Message{Conversation: &text},
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+
+	waProto "github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/pkg/errors"
+)
+
+// BulkSender sends messages to lists of recipients as jobs.
+type BulkSender interface {
+	// Send queues messages to be sent by session and creates job tracking them,
+	// if queueing fails partway job with already queued messages is returned along with error.
+	Send(sessionID string, messages []*waProto.WebMessageInfo) (*model.BulkJob, error)
+	// Job provides job with actual progress, nil is returned if job isn't found.
+	Job(jobID string) (*model.BulkJob, error)
+	// Cancel cancels messages of job waiting to be sent, nil is returned if job isn't found.
+	Cancel(jobID string) (*model.BulkJob, error)
+}
+
+// BulkQueue sends messages of jobs via queue of session, so they are paced by rate limit of session
+// and failed sending is retried. Progress of job is collected from statuses of its queued messages.
+type BulkQueue struct {
+	queue     Enqueuer
+	queueRepo repository.Queue
+	jobRepo   repository.BulkJob
+}
+
+// NewBulkQueue creates BulkQueue.
+func NewBulkQueue(queue Enqueuer, queueRepo repository.Queue, jobRepo repository.BulkJob) *BulkQueue {
+	return &BulkQueue{queue: queue, queueRepo: queueRepo, jobRepo: jobRepo}
+}
+
+// Send queues messages to be sent by session and creates job tracking them,
+// if queueing fails partway job with already queued messages is returned along with error.
+func (b *BulkQueue) Send(sessionID string, messages []*waProto.WebMessageInfo) (*model.BulkJob, error) {
+	jobID, err := newJobID()
+	if err != nil {
+		return nil, errors.Wrap(err, "can't generate job id")
+	}
+	now := time.Now()
+	job := &model.BulkJob{
+		ID:         jobID,
+		SessionID:  sessionID,
+		Status:     model.RunningBulkStatus,
+		Recipients: make([]model.BulkRecipient, 0, len(messages)),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	// Job is stored before messages are queued, so it's available while first messages are being sent.
+	if err := b.jobRepo.SaveBulkJob(job); err != nil {
+		return nil, errors.Wrap(err, "can't save bulk job")
+	}
+
+	for i, message := range messages {
+		queuedMsg, err := b.queue.Enqueue(sessionID, message)
+		if err != nil {
+			return b.failQueueing(job, messages[i:], errors.Wrapf(err, "can't enqueue message of bulk job `%s`", jobID))
+		}
+		job.Recipients = append(job.Recipients, model.BulkRecipient{
+			ChatID:    queuedMsg.ChatID,
+			MessageID: queuedMsg.ID,
+			Status:    queuedMsg.Status,
+		})
+	}
+	updateProgress(job)
+	if err := b.jobRepo.SaveBulkJob(job); err != nil {
+		return nil, errors.Wrap(err, "can't save bulk job")
+	}
+	return job, nil
+}
+
+// failQueueing stores job which messages are queued partially, so already queued messages are still tracked
+// and can be cancelled, messages that weren't queued are marked failed.
+func (b *BulkQueue) failQueueing(job *model.BulkJob, unqueued []*waProto.WebMessageInfo, err error) (*model.BulkJob, error) {
+	for _, message := range unqueued {
+		job.Recipients = append(job.Recipients, model.BulkRecipient{
+			ChatID: message.GetKey().GetRemoteJid(),
+			Status: model.FailedStatus,
+			Error:  err.Error(),
+		})
+	}
+	job.Status = model.PartialBulkStatus
+	updateProgress(job)
+	job.UpdatedAt = time.Now()
+	if saveErr := b.jobRepo.SaveBulkJob(job); saveErr != nil {
+		return nil, errors.Wrapf(saveErr, "can't save partially queued bulk job after error: %v", err)
+	}
+	return job, err
+}
+
+// Job provides job with actual progress, nil is returned if job isn't found.
+func (b *BulkQueue) Job(jobID string) (*model.BulkJob, error) {
+	job, err := b.jobRepo.BulkJob(jobID)
+	if err != nil || job == nil {
+		return nil, errors.Wrap(err, "can't read bulk job")
+	}
+	if err := b.refresh(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Cancel cancels messages of job waiting to be sent or retried, nil is returned if job isn't found.
+// Queue checks status of message before each attempt, so only message which sending is in progress can't be stopped,
+// it's counted as sent or failed by its result.
+func (b *BulkQueue) Cancel(jobID string) (*model.BulkJob, error) {
+	job, err := b.jobRepo.BulkJob(jobID)
+	if err != nil || job == nil {
+		return nil, errors.Wrap(err, "can't read bulk job")
+	}
+	for _, recipient := range job.Recipients {
+		if isFinalStatus(recipient.Status) {
+			continue
+		}
+		msg, err := b.queueRepo.QueuedMessage(recipient.MessageID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't read message `%s` of bulk job", recipient.MessageID)
+		}
+		if msg == nil || (msg.Status != model.QueuedStatus && msg.Status != model.RetryingStatus) {
+			continue
+		}
+		msg.Status = model.CancelledStatus
+		msg.UpdatedAt = time.Now()
+		if err := b.queueRepo.SaveQueuedMessage(msg); err != nil {
+			return nil, errors.Wrapf(err, "can't cancel message `%s` of bulk job", recipient.MessageID)
+		}
+	}
+	job.Status = model.CancelledBulkStatus
+	if err := b.refresh(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Updates results of recipients by statuses of queued messages and stores job.
+func (b *BulkQueue) refresh(job *model.BulkJob) error {
+	for i := range job.Recipients {
+		recipient := &job.Recipients[i]
+		// Message cancelled while it was being sent gets result of sending, so its status is re-read too.
+		if isFinalStatus(recipient.Status) && recipient.Status != model.CancelledStatus {
+			continue
+		}
+		msg, err := b.queueRepo.QueuedMessage(recipient.MessageID)
+		if err != nil {
+			return errors.Wrapf(err, "can't read message `%s` of bulk job", recipient.MessageID)
+		}
+		if msg == nil {
+			continue
+		}
+		recipient.Status = msg.Status
+		recipient.Error = msg.LastError
+	}
+	updateProgress(job)
+	job.UpdatedAt = time.Now()
+	return errors.Wrap(b.jobRepo.SaveBulkJob(job), "can't save bulk job")
+}
+
+func updateProgress(job *model.BulkJob) {
+	progress := model.BulkProgress{Total: len(job.Recipients)}
+	for _, recipient := range job.Recipients {
+		switch recipient.Status {
+		case model.SentStatus:
+			progress.Sent++
+		case model.FailedStatus:
+			progress.Failed++
+		case model.CancelledStatus:
+			progress.Cancelled++
+		default:
+			progress.Pending++
+		}
+	}
+	job.Progress = progress
+	if job.Status == model.RunningBulkStatus && progress.Pending == 0 {
+		job.Status = model.CompletedBulkStatus
+	}
+}
+
+func isFinalStatus(status string) bool {
+	return status == model.SentStatus || status == model.FailedStatus || status == model.CancelledStatus
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
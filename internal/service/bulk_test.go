@@ -0,0 +1,175 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	waProto "github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkQueue_Send(t *testing.T) {
+	c := gomock.NewController(t)
+	queue := mock.NewMockEnqueuer(c)
+	queue.EXPECT().
+		Enqueue("_sid_", gomock.Any()).
+		DoAndReturn(func(sessionID string, message *waProto.WebMessageInfo) (*model.QueuedMessage, error) {
+			return &model.QueuedMessage{
+				ID:     message.GetKey().GetId(),
+				ChatID: message.GetKey().GetRemoteJid(),
+				Status: model.QueuedStatus,
+			}, nil
+		}).
+		Times(2)
+	jobRepo := mock.NewMockBulkJob(c)
+	jobRepo.EXPECT().SaveBulkJob(gomock.Any()).Times(2)
+
+	bulk := service.NewBulkQueue(queue, mock.NewMockQueue(c), jobRepo)
+	job, err := bulk.Send("_sid_", []*waProto.WebMessageInfo{bulkMessage("MSG_1", "+000000000001"), bulkMessage("MSG_2", "+000000000002")})
+	require.Nil(t, err)
+	assert.NotEmpty(t, job.ID)
+	assert.Equal(t, "_sid_", job.SessionID)
+	assert.Equal(t, model.RunningBulkStatus, job.Status)
+	assert.Equal(t, model.BulkProgress{Total: 2, Pending: 2}, job.Progress)
+	assert.Equal(t, []model.BulkRecipient{
+		{ChatID: "+000000000001", MessageID: "MSG_1", Status: model.QueuedStatus},
+		{ChatID: "+000000000002", MessageID: "MSG_2", Status: model.QueuedStatus},
+	}, job.Recipients)
+}
+
+func TestBulkQueue_SendEnqueueError(t *testing.T) {
+	c := gomock.NewController(t)
+	queue := mock.NewMockEnqueuer(c)
+	gomock.InOrder(
+		queue.EXPECT().Enqueue("_sid_", gomock.Any()).Return(&model.QueuedMessage{ID: "MSG_1", ChatID: "+000000000001", Status: model.QueuedStatus}, nil),
+		queue.EXPECT().Enqueue("_sid_", gomock.Any()).Return(nil, errors.New("redis is down")),
+	)
+	var saved *model.BulkJob
+	jobRepo := mock.NewMockBulkJob(c)
+	jobRepo.EXPECT().SaveBulkJob(gomock.Any()).Do(func(job *model.BulkJob) { saved = job }).Times(2)
+
+	bulk := service.NewBulkQueue(queue, mock.NewMockQueue(c), jobRepo)
+	job, err := bulk.Send("_sid_", []*waProto.WebMessageInfo{
+		bulkMessage("MSG_1", "+000000000001"),
+		bulkMessage("MSG_2", "+000000000002"),
+		bulkMessage("MSG_3", "+000000000003"),
+	})
+	assert.NotNil(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, job, saved)
+	assert.Equal(t, model.PartialBulkStatus, job.Status)
+	assert.Equal(t, model.BulkProgress{Total: 3, Pending: 1, Failed: 2}, job.Progress)
+	assert.Equal(t, model.BulkRecipient{ChatID: "+000000000001", MessageID: "MSG_1", Status: model.QueuedStatus}, job.Recipients[0])
+	assert.Equal(t, "+000000000003", job.Recipients[2].ChatID)
+	assert.Equal(t, model.FailedStatus, job.Recipients[2].Status)
+	assert.Contains(t, job.Recipients[2].Error, "redis is down")
+}
+
+func TestBulkQueue_SendEnqueueAndSavingError(t *testing.T) {
+	c := gomock.NewController(t)
+	queue := mock.NewMockEnqueuer(c)
+	queue.EXPECT().Enqueue("_sid_", gomock.Any()).Return(nil, errors.New("redis is down"))
+	jobRepo := mock.NewMockBulkJob(c)
+	gomock.InOrder(
+		jobRepo.EXPECT().SaveBulkJob(gomock.Any()),
+		jobRepo.EXPECT().SaveBulkJob(gomock.Any()).Return(errors.New("redis is down")),
+	)
+
+	bulk := service.NewBulkQueue(queue, mock.NewMockQueue(c), jobRepo)
+	job, err := bulk.Send("_sid_", []*waProto.WebMessageInfo{bulkMessage("MSG_1", "+000000000001")})
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+}
+
+func TestBulkQueue_Job(t *testing.T) {
+	c := gomock.NewController(t)
+	jobRepo := mock.NewMockBulkJob(c)
+	jobRepo.EXPECT().BulkJob("_job_id_").Return(runningJob(), nil)
+	jobRepo.EXPECT().SaveBulkJob(gomock.Any())
+	queueRepo := mock.NewMockQueue(c)
+	queueRepo.EXPECT().QueuedMessage("MSG_2").Return(&model.QueuedMessage{ID: "MSG_2", Status: model.FailedStatus, LastError: "device is offline"}, nil)
+	queueRepo.EXPECT().QueuedMessage("MSG_3").Return(&model.QueuedMessage{ID: "MSG_3", Status: model.SentStatus}, nil)
+
+	job, err := service.NewBulkQueue(mock.NewMockEnqueuer(c), queueRepo, jobRepo).Job("_job_id_")
+	require.Nil(t, err)
+	assert.Equal(t, model.CompletedBulkStatus, job.Status)
+	assert.Equal(t, model.BulkProgress{Total: 3, Sent: 2, Failed: 1}, job.Progress)
+	assert.Equal(t, "device is offline", job.Recipients[1].Error)
+}
+
+func TestBulkQueue_JobNotFound(t *testing.T) {
+	c := gomock.NewController(t)
+	jobRepo := mock.NewMockBulkJob(c)
+	jobRepo.EXPECT().BulkJob("_job_id_").Return(nil, nil)
+
+	job, err := service.NewBulkQueue(mock.NewMockEnqueuer(c), mock.NewMockQueue(c), jobRepo).Job("_job_id_")
+	assert.Nil(t, job)
+	assert.Nil(t, err)
+}
+
+func TestBulkQueue_Cancel(t *testing.T) {
+	c := gomock.NewController(t)
+	jobRepo := mock.NewMockBulkJob(c)
+	jobRepo.EXPECT().BulkJob("_job_id_").Return(runningJob(), nil)
+	jobRepo.EXPECT().SaveBulkJob(gomock.Any())
+	retrying := &model.QueuedMessage{ID: "MSG_2", Status: model.RetryingStatus}
+	queued := &model.QueuedMessage{ID: "MSG_3", Status: model.QueuedStatus}
+	queueRepo := mock.NewMockQueue(c)
+	queueRepo.EXPECT().QueuedMessage("MSG_2").Return(retrying, nil).Times(2)
+	queueRepo.EXPECT().QueuedMessage("MSG_3").Return(queued, nil).Times(2)
+	queueRepo.EXPECT().SaveQueuedMessage(retrying)
+	queueRepo.EXPECT().SaveQueuedMessage(queued)
+
+	job, err := service.NewBulkQueue(mock.NewMockEnqueuer(c), queueRepo, jobRepo).Cancel("_job_id_")
+	require.Nil(t, err)
+	assert.Equal(t, model.CancelledBulkStatus, job.Status)
+	assert.Equal(t, model.CancelledStatus, retrying.Status)
+	assert.Equal(t, model.CancelledStatus, queued.Status)
+	assert.Equal(t, model.BulkProgress{Total: 3, Sent: 1, Cancelled: 2}, job.Progress)
+}
+
+func TestBulkQueue_CancelledWhileSending(t *testing.T) {
+	c := gomock.NewController(t)
+	cancelled := runningJob()
+	cancelled.Status = model.CancelledBulkStatus
+	cancelled.Recipients[1].Status = model.CancelledStatus
+	cancelled.Recipients[2].Status = model.CancelledStatus
+	jobRepo := mock.NewMockBulkJob(c)
+	jobRepo.EXPECT().BulkJob("_job_id_").Return(cancelled, nil)
+	jobRepo.EXPECT().SaveBulkJob(gomock.Any())
+	queueRepo := mock.NewMockQueue(c)
+	queueRepo.EXPECT().QueuedMessage("MSG_2").Return(&model.QueuedMessage{ID: "MSG_2", Status: model.SentStatus}, nil)
+	queueRepo.EXPECT().QueuedMessage("MSG_3").Return(&model.QueuedMessage{ID: "MSG_3", Status: model.CancelledStatus}, nil)
+
+	job, err := service.NewBulkQueue(mock.NewMockEnqueuer(c), queueRepo, jobRepo).Job("_job_id_")
+	require.Nil(t, err)
+	assert.Equal(t, model.CancelledBulkStatus, job.Status)
+	assert.Equal(t, model.BulkProgress{Total: 3, Sent: 2, Cancelled: 1}, job.Progress)
+}
+
+func runningJob() *model.BulkJob {
+	return &model.BulkJob{
+		ID:        "_job_id_",
+		SessionID: "_sid_",
+		Status:    model.RunningBulkStatus,
+		Recipients: []model.BulkRecipient{
+			{ChatID: "+000000000001", MessageID: "MSG_1", Status: model.SentStatus},
+			{ChatID: "+000000000002", MessageID: "MSG_2", Status: model.QueuedStatus},
+			{ChatID: "+000000000003", MessageID: "MSG_3", Status: model.QueuedStatus},
+		},
+	}
+}
+
+func bulkMessage(id, jid string) *waProto.WebMessageInfo {
+	text := "hello"
+	return &waProto.WebMessageInfo{
+		Key:     &waProto.MessageKey{Id: &id, RemoteJid: &jid},
+		Message: &waProto.Message{Conversation: &text},
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	infrastructureWhatsapp "github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+
+	"github.com/Rhymen/go-whatsapp"
+)
+
+// ContactsHandler caches address book and chats list which WhatsApp server sends after login.
+type ContactsHandler struct {
+	sessionID   string
+	contactRepo repository.Contact
+}
+
+// NewContactsHandler creates contacts and chats lists handler of session.
+func NewContactsHandler(sessionID string, contactRepo repository.Contact) *ContactsHandler {
+	return &ContactsHandler{sessionID: sessionID, contactRepo: contactRepo}
+}
+
+// HandleError ignores connection errors, they're handled by messages handler.
+func (h *ContactsHandler) HandleError(error) {}
+
+// HandleContactList stores address book of session sorted by name.
+func (h *ContactsHandler) HandleContactList(waContacts []whatsapp.Contact) {
+	contacts := make([]model.Contact, 0, len(waContacts))
+	for _, c := range waContacts {
+		contacts = append(contacts, model.Contact{
+			Jid:       infrastructureWhatsapp.NormalizeUserJid(c.Jid),
+			Name:      c.Name,
+			ShortName: c.Short,
+			PushName:  c.Notify,
+		})
+	}
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return strings.ToLower(contactTitle(&contacts[i])) < strings.ToLower(contactTitle(&contacts[j]))
+	})
+	if err := h.contactRepo.SaveContacts(h.sessionID, contacts); err != nil {
+		log.Printf("error saving contacts of session `%s`: %v", h.sessionID, err)
+	}
+}
+
+// HandleChatList stores chats list of session, most recent chats go first.
+func (h *ContactsHandler) HandleChatList(waChats []whatsapp.Chat) {
+	chats := make([]model.Chat, 0, len(waChats))
+	for _, c := range waChats {
+		jid := infrastructureWhatsapp.NormalizeUserJid(c.Jid)
+		unread, _ := strconv.Atoi(c.Unread)
+		chat := model.Chat{
+			Jid:         jid,
+			Name:        c.Name,
+			IsGroup:     strings.HasSuffix(jid, GroupJidSuffix),
+			UnreadCount: unread,
+			Muted:       c.IsMuted != "" && c.IsMuted != "0",
+			Spam:        c.IsMarkedSpam == "true",
+		}
+		if timestamp, err := strconv.ParseInt(c.LastMessageTime, 10, 64); err == nil && timestamp > 0 {
+			chat.LastMessageAt = time.Unix(timestamp, 0).UTC()
+		}
+		chats = append(chats, chat)
+	}
+	sort.SliceStable(chats, func(i, j int) bool {
+		return chats[i].LastMessageAt.After(chats[j].LastMessageAt)
+	})
+	if err := h.contactRepo.SaveChats(h.sessionID, chats); err != nil {
+		log.Printf("error saving chats of session `%s`: %v", h.sessionID, err)
+	}
+}
+
+func contactTitle(contact *model.Contact) string {
+	switch {
+	case contact.Name != "":
+		return contact.Name
+	case contact.PushName != "":
+		return contact.PushName
+	}
+	return contact.Jid
+}
@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/golang/mock/gomock"
+)
+
+func TestContactsHandler_HandleContactList(t *testing.T) {
+	c := gomock.NewController(t)
+	contactRepo := mock.NewMockContact(c)
+	contactRepo.EXPECT().SaveContacts("_sid_", []model.Contact{
+		{Jid: "375000000002@s.whatsapp.net", Name: "Alice", ShortName: "Al"},
+		{Jid: "375000000001@s.whatsapp.net", PushName: "bob"},
+		{Jid: "375000000003-1589212345@g.us", Name: "Family"},
+	})
+
+	handler := service.NewContactsHandler("_sid_", contactRepo)
+	handler.HandleContactList([]whatsapp.Contact{
+		{Jid: "375000000003-1589212345@g.us", Name: "Family"},
+		{Jid: "375000000001@c.us", Notify: "bob"},
+		{Jid: "375000000002@c.us", Name: "Alice", Short: "Al"},
+	})
+}
+
+func TestContactsHandler_HandleChatList(t *testing.T) {
+	c := gomock.NewController(t)
+	contactRepo := mock.NewMockContact(c)
+	contactRepo.EXPECT().SaveChats("_sid_", []model.Chat{
+		{
+			Jid:           "375000000003-1589212345@g.us",
+			Name:          "Family",
+			IsGroup:       true,
+			UnreadCount:   3,
+			LastMessageAt: time.Unix(1589212400, 0).UTC(),
+			Muted:         true,
+		},
+		{
+			Jid:           "375000000001@s.whatsapp.net",
+			Name:          "Bob",
+			LastMessageAt: time.Unix(1589212345, 0).UTC(),
+			Spam:          true,
+		},
+	})
+
+	handler := service.NewContactsHandler("_sid_", contactRepo)
+	handler.HandleChatList([]whatsapp.Chat{
+		{Jid: "375000000001@c.us", Name: "Bob", Unread: "0", LastMessageTime: "1589212345", IsMuted: "0", IsMarkedSpam: "true"},
+		{
+			Jid:             "375000000003-1589212345@g.us",
+			Name:            "Family",
+			Unread:          "3",
+			LastMessageTime: "1589212400",
+			IsMuted:         "1589300000",
+			IsMarkedSpam:    "false",
+		},
+	})
+}
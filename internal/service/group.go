@@ -0,0 +1,93 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+)
+
+// Actions of group notifications mapped on types of events sent to webhook.
+var groupActionPayloadTypes = map[string]string{
+	"create":   GroupCreatedPayloadType,
+	"add":      GroupParticipantAddedPayloadType,
+	"invite":   GroupParticipantAddedPayloadType,
+	"remove":   GroupParticipantRemovedPayloadType,
+	"leave":    GroupParticipantLeftPayloadType,
+	"promote":  GroupParticipantPromotedPayloadType,
+	"demote":   GroupParticipantDemotedPayloadType,
+	"subject":  GroupSubjectChangedPayloadType,
+	"desc_add": GroupDescriptionChangedPayloadType,
+	"desc_rem": GroupDescriptionChangedPayloadType,
+}
+
+// Chat event, group notification is an "action" command with data of action name, its author and details.
+type chatEvent struct {
+	Cmd  string            `json:"cmd"`
+	ID   string            `json:"id"`
+	Data []json.RawMessage `json:"data"`
+}
+
+// Details of group notification, participants are set for actions with participants.
+type groupActionDetails struct {
+	Participants []string `json:"participants"`
+	Subject      string   `json:"subject"`
+	Desc         string   `json:"desc"`
+	T            int64    `json:"t"`
+	SubjectTime  int64    `json:"s_t"`
+}
+
+// Notifies webhook about change of group made by action of chat event.
+func (h *Handler) handleChatAction(event json.RawMessage) {
+	var chat chatEvent
+	if err := json.Unmarshal(event, &chat); err != nil || chat.Cmd != "action" || len(chat.Data) < 2 {
+		return
+	}
+	if !strings.HasSuffix(chat.ID, GroupJidSuffix) {
+		return
+	}
+	var action, author string
+	if err := json.Unmarshal(chat.Data[0], &action); err != nil {
+		return
+	}
+	payloadType, ok := groupActionPayloadTypes[action]
+	if !ok {
+		return
+	}
+	_ = json.Unmarshal(chat.Data[1], &author)
+
+	var details groupActionDetails
+	if len(chat.Data) > 2 {
+		if err := json.Unmarshal(chat.Data[2], &details); err != nil {
+			// Participants are sent without details object by some actions.
+			if err = json.Unmarshal(chat.Data[2], &details.Participants); err != nil {
+				log.Printf("can't parse details of group action `%s`: %v\n", action, err)
+				return
+			}
+		}
+	}
+
+	at := time.Now()
+	if details.T > 0 {
+		at = time.Unix(details.T, 0)
+	} else if details.SubjectTime > 0 {
+		at = time.Unix(details.SubjectTime, 0)
+	}
+	payload := &GroupEventPayload{
+		Type:        payloadType,
+		GroupID:     chat.ID,
+		SessionID:   h.Session.SessionID,
+		Author:      whatsapp.NormalizeUserJid(author),
+		Subject:     details.Subject,
+		Description: details.Desc,
+		Timestamp:   uint64(at.Unix()),
+	}
+	for _, participant := range details.Participants {
+		payload.Participants = append(payload.Participants, whatsapp.NormalizeUserJid(participant))
+	}
+	if h.postToWebhook(payload) {
+		log.Printf("group event `%s` of group `%s` sent by session `%s`", payloadType, chat.ID, h.Session.SessionID)
+	}
+}
@@ -0,0 +1,114 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleJsonMessage_GroupEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		message       string
+		expectPayload *service.GroupEventPayload
+	}{
+		{
+			name: "Participants added",
+			message: `["Chat",{"cmd":"action","id":"375440000000-1600000000@g.us",` +
+				`"data":["add","375440000000@c.us",{"participants":["375440000001@c.us","375440000002@c.us"],"t":1600000001}]}]`,
+			expectPayload: &service.GroupEventPayload{
+				Type:         service.GroupParticipantAddedPayloadType,
+				GroupID:      "375440000000-1600000000@g.us",
+				SessionID:    "_sid_",
+				Author:       "375440000000@s.whatsapp.net",
+				Participants: []string{"375440000001@s.whatsapp.net", "375440000002@s.whatsapp.net"},
+				Timestamp:    1600000001,
+			},
+		},
+		{
+			name:    "Participant removed",
+			message: `["Chat",{"cmd":"action","id":"375440000000-1600000000@g.us","data":["remove","375440000000@c.us",["375440000001@c.us"]]}]`,
+			expectPayload: &service.GroupEventPayload{
+				Type:         service.GroupParticipantRemovedPayloadType,
+				GroupID:      "375440000000-1600000000@g.us",
+				SessionID:    "_sid_",
+				Author:       "375440000000@s.whatsapp.net",
+				Participants: []string{"375440000001@s.whatsapp.net"},
+			},
+		},
+		{
+			name: "Subject changed",
+			message: `["Chat",{"cmd":"action","id":"375440000000-1600000000@g.us",` +
+				`"data":["subject","375440000001@c.us",{"subject":"VIP support","s_t":1600000002,"s_o":"375440000001@c.us"}]}]`,
+			expectPayload: &service.GroupEventPayload{
+				Type:      service.GroupSubjectChangedPayloadType,
+				GroupID:   "375440000000-1600000000@g.us",
+				SessionID: "_sid_",
+				Author:    "375440000001@s.whatsapp.net",
+				Subject:   "VIP support",
+				Timestamp: 1600000002,
+			},
+		},
+		{
+			name:    "Unknown action",
+			message: `["Chat",{"cmd":"action","id":"375440000000-1600000000@g.us","data":["restrict","375440000001@c.us",{}]}]`,
+		},
+		{
+			name:    "Action of user chat",
+			message: `["Chat",{"cmd":"action","id":"375440000001@c.us","data":["add","375440000000@c.us",["375440000001@c.us"]]}]`,
+		},
+		{
+			name:    "Invalid details",
+			message: `["Chat",{"cmd":"action","id":"375440000000-1600000000@g.us","data":["add","375440000000@c.us","details"]}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			expectPosts := 0
+			if tt.expectPayload != nil {
+				expectPosts = 1
+			}
+			var payload service.GroupEventPayload
+			client := mock.NewMockClient(c)
+			client.EXPECT().
+				Post("webhook/url/_sid_", "application/json", gomock.Any()).
+				DoAndReturn(func(url, contentType string, body io.Reader) (*http.Response, error) {
+					require.Nil(t, json.NewDecoder(body).Decode(&payload))
+					return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+				}).
+				Times(expectPosts)
+
+			m := jsonInfra.MarshallCallback(json.Marshal)
+			sess := &model.WapiSession{SessionID: "_sid_", WhatsAppSession: &whatsapp.Session{Wid: "375440000000@c.us"}}
+			h := service.NewMsgHandler(nil, sess, mock.NewMockMessage(c), nil, nil, nil, client, &m, 0, "webhook/url/")
+			h.HandleJsonMessage(tt.message)
+
+			if tt.expectPayload == nil {
+				return
+			}
+			if tt.expectPayload.Timestamp == 0 {
+				assert.NotZero(t, payload.Timestamp)
+				payload.Timestamp = 0
+			}
+			assert.Equal(t, *tt.expectPayload, payload)
+		})
+	}
+}
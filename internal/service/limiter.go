@@ -0,0 +1,92 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+)
+
+// RateLimitError is returned when sending of message exceeds rate limit of session.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit of sending messages exceeded, retry after %s", e.RetryAfter)
+}
+
+// TokenBucket limits rate of events, bucket is refilled with constant rate up to its burst size
+// and each event takes one token.
+type TokenBucket struct {
+	rate    float64 // Tokens per second.
+	burst   float64
+	tokens  float64
+	updated time.Time
+	mu      sync.Mutex
+}
+
+// NewTokenBucket creates full TokenBucket.
+func NewTokenBucket(ratePerMinute, burst int) *TokenBucket {
+	return &TokenBucket{
+		rate:    float64(ratePerMinute) / 60,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		updated: time.Now(),
+	}
+}
+
+// Take takes token from bucket, if bucket is empty, duration until next token is available is returned.
+func (b *TokenBucket) Take() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.updated).Seconds()*b.rate)
+	b.updated = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// RateLimitedConn is a connection limiting rate of sent messages,
+// random jitter is added to retry delay so senders waiting for limit don't retry at once.
+type RateLimitedConn struct {
+	whatsapp.Conn
+	bucket *TokenBucket
+	jitter time.Duration
+}
+
+// Send sends messages to WhatsApp server if rate limit isn't exceeded, otherwise RateLimitError is returned.
+func (c *RateLimitedConn) Send(msg interface{}) (string, error) {
+	if ok, wait := c.bucket.Take(); !ok {
+		if c.jitter > 0 {
+			wait += time.Duration(rand.Int63n(int64(c.jitter)))
+		}
+		return "", &RateLimitError{RetryAfter: wait}
+	}
+	return c.Conn.Send(msg)
+}
+
+// RateLimitedConnector creates connections limiting rate of sent messages, each connection has its own limit.
+// Rate isn't limited if RatePerMinute is zero.
+type RateLimitedConnector struct {
+	Connector
+	RatePerMinute int
+	Burst         int
+	Jitter        time.Duration
+}
+
+// Connect connects to the WhatsApp server and wraps connection by rate limiter.
+func (c RateLimitedConnector) Connect(timeout time.Duration) (whatsapp.Conn, error) {
+	wac, err := c.Connector.Connect(timeout)
+	if err != nil || c.RatePerMinute <= 0 {
+		return wac, err
+	}
+	return &RateLimitedConn{Conn: wac, bucket: NewTokenBucket(c.RatePerMinute, c.Burst), jitter: c.Jitter}, nil
+}
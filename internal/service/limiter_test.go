@@ -0,0 +1,66 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_Take(t *testing.T) {
+	bucket := service.NewTokenBucket(60, 2)
+
+	ok, _ := bucket.Take()
+	assert.True(t, ok)
+	ok, _ = bucket.Take()
+	assert.True(t, ok)
+
+	ok, wait := bucket.Take()
+	assert.False(t, ok)
+	assert.True(t, wait > 0 && wait <= time.Second, wait)
+}
+
+func TestRateLimitedConnector_Connect(t *testing.T) {
+	c := gomock.NewController(t)
+	wac := mock.NewMockConn(c)
+	wac.EXPECT().Send(gomock.Any()).Return("MSG_ID", nil).Times(1)
+	connector := mock.NewMockConnector(c)
+	connector.EXPECT().Connect(gomock.Any()).Return(wac, nil).Times(2)
+
+	unlimited, err := service.RateLimitedConnector{Connector: connector}.Connect(time.Second)
+	require.Nil(t, err)
+	assert.Equal(t, wac, unlimited)
+
+	limited, err := service.RateLimitedConnector{
+		Connector:     connector,
+		RatePerMinute: 1,
+		Burst:         1,
+		Jitter:        time.Second,
+	}.Connect(time.Second)
+	require.Nil(t, err)
+
+	id, err := limited.Send("message")
+	require.Nil(t, err)
+	assert.Equal(t, "MSG_ID", id)
+
+	_, err = limited.Send("message")
+	var limitErr *service.RateLimitError
+	require.True(t, errors.As(err, &limitErr))
+	assert.True(t, limitErr.RetryAfter > time.Second*58 && limitErr.RetryAfter <= time.Second*61, limitErr.RetryAfter)
+}
+
+func TestRateLimitedConnector_ConnectError(t *testing.T) {
+	c := gomock.NewController(t)
+	connector := mock.NewMockConnector(c)
+	connector.EXPECT().Connect(gomock.Any()).Return(nil, errors.New("connection error"))
+
+	wac, err := service.RateLimitedConnector{Connector: connector, RatePerMinute: 1, Burst: 1}.Connect(time.Second)
+	assert.Nil(t, wac)
+	assert.NotNil(t, err)
+}
@@ -28,7 +28,10 @@ type WebHook struct {
 	auth                  Authorizer
 	webhookURL            string
 	msgRepo               repository.Message
+	contactRepo           repository.Contact
+	mediaDownloader       *MediaDownloader
 	client                httpInfra.Client
+	backfillCount         int
 	interruptChan         chan os.Signal
 }
 
@@ -39,7 +42,10 @@ func NewWebHook(
 	authorizer Authorizer,
 	webhookURL string,
 	msgRepo repository.Message,
+	contactRepo repository.Contact,
+	mediaDownloader *MediaDownloader,
 	client httpInfra.Client,
+	backfillCount int,
 	interruptChan chan os.Signal,
 ) *WebHook {
 	return &WebHook{
@@ -48,7 +54,10 @@ func NewWebHook(
 		auth:                  authorizer,
 		webhookURL:            webhookURL,
 		msgRepo:               msgRepo,
+		contactRepo:           contactRepo,
+		mediaDownloader:       mediaDownloader,
 		client:                client,
+		backfillCount:         backfillCount,
 		interruptChan:         interruptChan,
 	}
 }
@@ -71,17 +80,21 @@ func (l *WebHook) ListenForSession(sessionID string, wg *sync.WaitGroup) (gracef
 	log.Printf("start listening messages for sessionRepo `%s`, bound login: `%s`", session.SessionID, session.WhatsAppSession.Wid)
 
 	marshal := jsonInfra.MarshallCallback(json.Marshal)
-	wac.AddHandler(NewMsgHandler(
+	msgHandler := NewMsgHandler(
 		wac,
 		session,
 		l.msgRepo,
+		l.mediaDownloader,
 		l.connectionsSupervisor,
 		l.sessionRepo,
 		l.client,
 		&marshal,
-		uint64(time.Now().Unix()),
+		l.initTimestamp(session.SessionID),
 		l.webhookURL,
-	))
+	)
+	msgHandler.BackfillCount = l.backfillCount
+	wac.AddHandler(msgHandler)
+	wac.AddHandler(NewContactsHandler(session.SessionID, l.contactRepo))
 
 	signal.Notify(l.interruptChan, os.Interrupt, syscall.SIGTERM)
 	wg.Done()
@@ -99,3 +112,21 @@ func (l *WebHook) ListenForSession(sessionID string, wg *sync.WaitGroup) (gracef
 	}
 	return true, nil
 }
+
+// initTimestamp provides timestamp after which incoming messages are handled,
+// messages received since last message sent to webhook are caught up if backfill is enabled.
+func (l *WebHook) initTimestamp(sessionID string) uint64 {
+	now := uint64(time.Now().Unix())
+	if l.backfillCount == 0 {
+		return now
+	}
+	lastTimestamp, err := l.msgRepo.LastMessageTimestamp(sessionID)
+	if err != nil {
+		log.Printf("can't get last msg timestamp of session `%s`, catching up is skipped: %v\n", sessionID, err)
+		return now
+	}
+	if lastTimestamp == 0 || lastTimestamp > now {
+		return now
+	}
+	return lastTimestamp
+}
@@ -29,7 +29,10 @@ type listenerMocksFactory func(t *testing.T) (
 	service.Authorizer,
 	string,
 	repository.Message,
+	repository.Contact,
+	*service.MediaDownloader,
 	httpInfra.Client,
+	int,
 	chan os.Signal,
 )
 
@@ -55,14 +58,17 @@ func sessionAlreadyListening() listenerTestData {
 			service.Connections,
 			service.Authorizer,
 			string, repository.Message,
+			repository.Contact,
+			*service.MediaDownloader,
 			httpInfra.Client,
+			int,
 			chan os.Signal,
 		) {
-			sessRepo, _, auth, wh, msgRepo, client, interruptCh := listenerMocks(t)
+			sessRepo, _, auth, wh, msgRepo, contactRepo, mediaDownloader, client, backfillCount, interruptCh := listenerMocks(t)
 			c := gomock.NewController(t)
 			connSV := mock.NewMockConnections(c)
 			connSV.EXPECT().AuthenticatedConnectionForSession(gomock.Any()).Return(nil, nil)
-			return sessRepo, connSV, auth, wh, msgRepo, client, interruptCh
+			return sessRepo, connSV, auth, wh, msgRepo, contactRepo, mediaDownloader, client, backfillCount, interruptCh
 		},
 		ignoreInterrupt: true,
 		waitErr:         true,
@@ -77,14 +83,17 @@ func loginFailed() listenerTestData {
 			service.Connections,
 			service.Authorizer,
 			string, repository.Message,
+			repository.Contact,
+			*service.MediaDownloader,
 			httpInfra.Client,
+			int,
 			chan os.Signal,
 		) {
-			sessRepo, connSV, _, wh, msgRepo, client, interruptCh := listenerMocks(t)
+			sessRepo, connSV, _, wh, msgRepo, contactRepo, mediaDownloader, client, backfillCount, interruptCh := listenerMocks(t)
 			c := gomock.NewController(t)
 			auth := mock.NewMockAuthorizer(c)
 			auth.EXPECT().Login(gomock.Any()).Return(nil, nil, errors.New("login failed"))
-			return sessRepo, connSV, auth, wh, msgRepo, client, interruptCh
+			return sessRepo, connSV, auth, wh, msgRepo, contactRepo, mediaDownloader, client, backfillCount, interruptCh
 		},
 		ignoreInterrupt: true,
 		waitErr:         true,
@@ -99,15 +108,18 @@ func discconnectFailed() listenerTestData {
 			service.Connections,
 			service.Authorizer,
 			string, repository.Message,
+			repository.Contact,
+			*service.MediaDownloader,
 			httpInfra.Client,
+			int,
 			chan os.Signal,
 		) {
-			sessRepo, connSV, _, wh, msgRepo, client, interruptCh := listenerMocks(t)
+			sessRepo, connSV, _, wh, msgRepo, contactRepo, mediaDownloader, client, backfillCount, interruptCh := listenerMocks(t)
 
 			c := gomock.NewController(t)
 
 			conn := mock.NewMockConn(c)
-			conn.EXPECT().AddHandler(gomock.Any())
+			conn.EXPECT().AddHandler(gomock.Any()).Times(2)
 			conn.EXPECT().Disconnect().Return(whatsapp.Session{}, errors.New("disconnect error"))
 
 			sess := &model.WapiSession{SessionID: "_sid_", WhatsAppSession: &whatsapp.Session{Wid: "_wid_"}}
@@ -115,7 +127,7 @@ func discconnectFailed() listenerTestData {
 			auth := mock.NewMockAuthorizer(c)
 			auth.EXPECT().Login(gomock.Any()).Return(conn, sess, nil)
 
-			return sessRepo, connSV, auth, wh, msgRepo, client, interruptCh
+			return sessRepo, connSV, auth, wh, msgRepo, contactRepo, mediaDownloader, client, backfillCount, interruptCh
 		},
 		ignoreInterrupt: false,
 		waitErr:         true,
@@ -130,14 +142,17 @@ func sessionWritingFailed() listenerTestData {
 			service.Connections,
 			service.Authorizer,
 			string, repository.Message,
+			repository.Contact,
+			*service.MediaDownloader,
 			httpInfra.Client,
+			int,
 			chan os.Signal,
 		) {
-			_, connSV, auth, wh, msgRepo, client, interruptCh := listenerMocks(t)
+			_, connSV, auth, wh, msgRepo, contactRepo, mediaDownloader, client, backfillCount, interruptCh := listenerMocks(t)
 			c := gomock.NewController(t)
 			sessRepo := mock.NewMockSession(c)
 			sessRepo.EXPECT().WriteSession(gomock.Any()).Return(errors.New("writing error"))
-			return sessRepo, connSV, auth, wh, msgRepo, client, interruptCh
+			return sessRepo, connSV, auth, wh, msgRepo, contactRepo, mediaDownloader, client, backfillCount, interruptCh
 		},
 		ignoreInterrupt: false,
 		waitErr:         true,
@@ -156,8 +171,8 @@ func TestWebHook_ListenForSession(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			sessRepo, connSV, auth, wh, msgRepo, client, interruptCh := tt.mocksFactory(t)
-			listener := service.NewWebHook(sessRepo, connSV, auth, wh, msgRepo, client, interruptCh)
+			sessRepo, connSV, auth, wh, msgRepo, contactRepo, mediaDownloader, client, backfillCount, interruptCh := tt.mocksFactory(t)
+			listener := service.NewWebHook(sessRepo, connSV, auth, wh, msgRepo, contactRepo, mediaDownloader, client, backfillCount, interruptCh)
 			var err error
 			wg := sync.WaitGroup{}
 			wg.Add(1)
@@ -190,7 +205,10 @@ func listenerMocks(t *testing.T) (
 	auth service.Authorizer,
 	_ string,
 	_ repository.Message,
+	_ repository.Contact,
+	_ *service.MediaDownloader,
 	_ httpInfra.Client,
+	_ int,
 	_ chan os.Signal,
 ) {
 	c := gomock.NewController(t)
@@ -203,7 +221,7 @@ func listenerMocks(t *testing.T) (
 	connSupervisor = cs
 
 	conn := mock.NewMockConn(c)
-	conn.EXPECT().AddHandler(gomock.Any())
+	conn.EXPECT().AddHandler(gomock.Any()).Times(2)
 	conn.EXPECT().Disconnect().Return(whatsapp.Session{}, nil)
 
 	sess := &model.WapiSession{SessionID: "_sid_", WhatsAppSession: &whatsapp.Session{Wid: "_wid_"}}
@@ -217,6 +235,42 @@ func listenerMocks(t *testing.T) (
 		auth,
 		"/webhook_url/",
 		mock.NewMockMessage(c),
+		mock.NewMockContact(c),
+		service.NewMediaDownloader(mock.NewMockMedia(c), 0),
 		mock.NewMockClient(c),
+		0,
 		make(chan os.Signal)
 }
+
+func TestWebHook_ListenForSession_Backfill(t *testing.T) {
+	sessRepo, connSV, _, wh, _, contactRepo, mediaDownloader, client, _, interruptCh := listenerMocks(t)
+	c := gomock.NewController(t)
+
+	msgRepo := mock.NewMockMessage(c)
+	msgRepo.EXPECT().LastMessageTimestamp("_sid_").Return(uint64(1000), nil)
+
+	var msgHandler *service.Handler
+	conn := mock.NewMockConn(c)
+	conn.EXPECT().AddHandler(gomock.Any()).Do(func(handler whatsapp.Handler) {
+		if h, ok := handler.(*service.Handler); ok {
+			msgHandler = h
+		}
+	}).Times(2)
+	conn.EXPECT().Disconnect().Return(whatsapp.Session{}, nil)
+	auth := mock.NewMockAuthorizer(c)
+	auth.EXPECT().
+		Login("_sid_").
+		Return(conn, &model.WapiSession{SessionID: "_sid_", WhatsAppSession: &whatsapp.Session{Wid: "_wid_"}}, nil)
+
+	listener := service.NewWebHook(sessRepo, connSV, auth, wh, msgRepo, contactRepo, mediaDownloader, client, 5, interruptCh)
+	go func() {
+		interruptCh <- os.Interrupt
+	}()
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	_, err := listener.ListenForSession("_sid_", wg)
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1000), msgHandler.InitTimestamp)
+	assert.Equal(t, 5, msgHandler.BackfillCount)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"encoding/base64"
+	"mime"
+
+	"github.com/r-erema/wapi/internal/repository"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/pkg/errors"
+)
+
+// MediaMessage is a message containing downloadable media content.
+type MediaMessage interface {
+	// Download retrieves media content.
+	Download() ([]byte, error)
+}
+
+// MediaDownloader downloads media content of incoming messages and stores it.
+type MediaDownloader struct {
+	mediaRepo    repository.Media
+	maxMediaSize uint64
+}
+
+// NewMediaDownloader creates MediaDownloader.
+// If media repository isn't set, media content is put in payload in base64.
+// Zero max media size means media size is unlimited.
+func NewMediaDownloader(mediaRepo repository.Media, maxMediaSize uint64) *MediaDownloader {
+	return &MediaDownloader{mediaRepo: mediaRepo, maxMediaSize: maxMediaSize}
+}
+
+// Download downloads media content of message and builds media payload.
+// Media exceeding max media size by its declared length isn't downloaded and is marked as too large,
+// downloading of media without declared length fails since its content can't be checked by the client.
+func (d *MediaDownloader) Download(msg MediaMessage, info *whatsapp.MessageInfo, mimeType string) (MediaPayload, error) {
+	media := MediaPayload{MimeType: mimeType, Size: mediaFileLength(info.Source)}
+	if d.maxMediaSize > 0 && media.Size > d.maxMediaSize {
+		media.TooLarge = true
+		return media, nil
+	}
+
+	content, err := msg.Download()
+	if err != nil {
+		return media, errors.Wrap(err, "can't download media")
+	}
+	media.Size = uint64(len(content))
+
+	if d.mediaRepo == nil {
+		media.MediaBase64 = base64.StdEncoding.EncodeToString(content)
+		return media, nil
+	}
+
+	url, err := d.mediaRepo.SaveMedia(mediaFileName(info.Id, mimeType), content)
+	if err != nil {
+		return media, errors.Wrap(err, "can't store media")
+	}
+	media.MediaURL = url
+	return media, nil
+}
+
+func mediaFileName(msgID, mimeType string) string {
+	extensions, err := mime.ExtensionsByType(mimeType)
+	if err != nil || len(extensions) == 0 {
+		return msgID
+	}
+	return msgID + extensions[0]
+}
+
+func mediaFileLength(source *proto.WebMessageInfo) uint64 {
+	msg := source.GetMessage()
+	switch {
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetFileLength()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetFileLength()
+	case msg.GetAudioMessage() != nil:
+		return msg.GetAudioMessage().GetFileLength()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetFileLength()
+	}
+	return 0
+}
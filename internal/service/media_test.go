@@ -0,0 +1,101 @@
+package service_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/r-erema/wapi/internal/repository"
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaDownloader_Download(t *testing.T) {
+	fileLength := uint64(2048)
+	tests := []struct {
+		name        string
+		mediaRepo   func(t *testing.T) repository.Media
+		msg         *mock.MediaMessage
+		info        *whatsapp.MessageInfo
+		expectMedia service.MediaPayload
+		expectErr   bool
+	}{
+		{
+			name:      "Media in base64",
+			mediaRepo: func(t *testing.T) repository.Media { return nil },
+			msg:       &mock.MediaMessage{Content: []byte("content")},
+			info:      &whatsapp.MessageInfo{Id: "MSG_ID"},
+			expectMedia: service.MediaPayload{
+				MimeType:    "image/png",
+				Size:        7,
+				MediaBase64: base64.StdEncoding.EncodeToString([]byte("content")),
+			},
+		},
+		{
+			name: "Media stored in repository",
+			mediaRepo: func(t *testing.T) repository.Media {
+				c := gomock.NewController(t)
+				mediaRepo := mock.NewMockMedia(c)
+				mediaRepo.EXPECT().SaveMedia("MSG_ID.png", []byte("content")).Return("https://wapi.host/get-media/MSG_ID.png", nil)
+				return mediaRepo
+			},
+			msg:  &mock.MediaMessage{Content: []byte("content")},
+			info: &whatsapp.MessageInfo{Id: "MSG_ID"},
+			expectMedia: service.MediaPayload{
+				MimeType: "image/png",
+				Size:     7,
+				MediaURL: "https://wapi.host/get-media/MSG_ID.png",
+			},
+		},
+		{
+			name:      "Media too large",
+			mediaRepo: func(t *testing.T) repository.Media { return nil },
+			msg:       &mock.MediaMessage{Err: errors.New("must not be downloaded")},
+			info: &whatsapp.MessageInfo{
+				Id: "MSG_ID",
+				Source: &proto.WebMessageInfo{Message: &proto.Message{
+					ImageMessage: &proto.ImageMessage{FileLength: &fileLength},
+				}},
+			},
+			expectMedia: service.MediaPayload{MimeType: "image/png", Size: fileLength, TooLarge: true},
+		},
+		{
+			name:      "Downloading error",
+			mediaRepo: func(t *testing.T) repository.Media { return nil },
+			msg:       &mock.MediaMessage{Err: errors.New("downloading error")},
+			info:      &whatsapp.MessageInfo{Id: "MSG_ID"},
+			expectErr: true,
+		},
+		{
+			name: "Storing error",
+			mediaRepo: func(t *testing.T) repository.Media {
+				c := gomock.NewController(t)
+				mediaRepo := mock.NewMockMedia(c)
+				mediaRepo.EXPECT().SaveMedia(gomock.Any(), gomock.Any()).Return("", errors.New("storing error"))
+				return mediaRepo
+			},
+			msg:       &mock.MediaMessage{Content: []byte("content")},
+			info:      &whatsapp.MessageInfo{Id: "MSG_ID"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			downloader := service.NewMediaDownloader(tt.mediaRepo(t), 1024)
+			media, err := downloader.Download(tt.msg, tt.info, "image/png")
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectMedia, media)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	httpInfra "github.com/r-erema/wapi/internal/infrastructure/http"
@@ -24,12 +25,17 @@ type Handler struct {
 	Connection            infrastructureWhatsapp.Conn
 	Session               *model.WapiSession
 	messageRepo           repository.Message
+	mediaDownloader       *MediaDownloader
 	connectionsSupervisor Connections
 	storedSession         repository.Session
 	client                httpInfra.Client
 	marshal               *jsonInfra.MarshallCallback
 	InitTimestamp         uint64
 	WebhookURL            string
+	// BackfillCount is a number of latest messages of each chat loaded after login to catch up, 0 disables it.
+	BackfillCount      int
+	lastTimestamp      uint64
+	lastTimestampMutex sync.Mutex
 }
 
 // NewMsgHandler creates errors and messages handler.
@@ -37,6 +43,7 @@ func NewMsgHandler(
 	connection infrastructureWhatsapp.Conn,
 	wapiSession *model.WapiSession,
 	messageRepo repository.Message,
+	mediaDownloader *MediaDownloader,
 	connectionsSupervisor Connections,
 	sessionRepo repository.Session,
 	client httpInfra.Client,
@@ -48,6 +55,7 @@ func NewMsgHandler(
 		Connection:            connection,
 		Session:               wapiSession,
 		messageRepo:           messageRepo,
+		mediaDownloader:       mediaDownloader,
 		InitTimestamp:         initTimestamp,
 		WebhookURL:            webhookURL,
 		connectionsSupervisor: connectionsSupervisor,
@@ -112,46 +120,195 @@ func (h *Handler) HandleError(err error) {
 
 // HandleTextMessage sends message to webhook and stores it in repository.
 func (h *Handler) HandleTextMessage(msg *whatsapp.TextMessage) {
-	if h.InitTimestamp == 0 {
-		h.InitTimestamp = uint64(time.Now().Unix())
+	h.handleMessage(&msg, &msg.Info)
+}
+
+// HandleImageMessage downloads image and sends message to webhook.
+func (h *Handler) HandleImageMessage(msg whatsapp.ImageMessage) {
+	media, ok := h.handleMediaMessage(&msg, &msg.Info, msg.Type)
+	if !ok {
+		return
 	}
+	h.sendToWebhook(&ImageMessagePayload{
+		MessagePayload: newMessagePayload(ImagePayloadType, &msg.Info),
+		MediaPayload:   media,
+		Caption:        msg.Caption,
+		Thumbnail:      msg.Thumbnail,
+	}, &msg.Info)
+}
 
-	if !h.isMessageAllowedToHandle(msg) {
+// HandleDocumentMessage downloads document and sends message to webhook.
+func (h *Handler) HandleDocumentMessage(msg whatsapp.DocumentMessage) {
+	media, ok := h.handleMediaMessage(&msg, &msg.Info, msg.Type)
+	if !ok {
 		return
 	}
+	h.sendToWebhook(&DocumentMessagePayload{
+		MessagePayload: newMessagePayload(DocumentPayloadType, &msg.Info),
+		MediaPayload:   media,
+		FileName:       msg.FileName,
+		Title:          msg.Title,
+		PageCount:      msg.PageCount,
+		Thumbnail:      msg.Thumbnail,
+	}, &msg.Info)
+}
 
-	log.Printf("got msg to handle from `%v`, destination `%v`", msg.Info.RemoteJid, h.Session.WhatsAppSession.Wid)
+// HandleAudioMessage downloads audio or voice note and sends message to webhook.
+func (h *Handler) HandleAudioMessage(msg whatsapp.AudioMessage) {
+	media, ok := h.handleMediaMessage(&msg, &msg.Info, msg.Type)
+	if !ok {
+		return
+	}
+	h.sendToWebhook(&AudioMessagePayload{
+		MessagePayload: newMessagePayload(AudioPayloadType, &msg.Info),
+		MediaPayload:   media,
+		Duration:       msg.Length,
+		Ptt:            msg.Ptt || msg.Info.Source.GetMessage().GetAudioMessage().GetPtt(),
+	}, &msg.Info)
+}
 
-	marshal := *h.marshal
-	requestBody, err := marshal(&msg)
-	if err != nil {
-		log.Println("error msg marshaling", err)
+// HandleVideoMessage downloads video and sends message to webhook.
+func (h *Handler) HandleVideoMessage(msg whatsapp.VideoMessage) {
+	media, ok := h.handleMediaMessage(&msg, &msg.Info, msg.Type)
+	if !ok {
+		return
+	}
+	h.sendToWebhook(&VideoMessagePayload{
+		MessagePayload: newMessagePayload(VideoPayloadType, &msg.Info),
+		MediaPayload:   media,
+		Caption:        msg.Caption,
+		Duration:       msg.Length,
+		GifPlayback:    msg.GifPlayback,
+		Thumbnail:      msg.Thumbnail,
+	}, &msg.Info)
+}
+
+// HandleLocationMessage sends location message to webhook.
+func (h *Handler) HandleLocationMessage(msg whatsapp.LocationMessage) {
+	h.handleMessage(&LocationMessagePayload{
+		MessagePayload: newMessagePayload(LocationPayloadType, &msg.Info),
+		Latitude:       msg.DegreesLatitude,
+		Longitude:      msg.DegreesLongitude,
+		Name:           msg.Name,
+		Address:        msg.Address,
+		URL:            msg.Url,
+		Thumbnail:      msg.JpegThumbnail,
+	}, &msg.Info)
+}
+
+// HandleLiveLocationMessage sends live location message to webhook.
+func (h *Handler) HandleLiveLocationMessage(msg whatsapp.LiveLocationMessage) {
+	h.handleMessage(&LiveLocationMessagePayload{
+		MessagePayload: newMessagePayload(LiveLocationPayloadType, &msg.Info),
+		Latitude:       msg.DegreesLatitude,
+		Longitude:      msg.DegreesLongitude,
+		Accuracy:       msg.AccuracyInMeters,
+		Speed:          msg.SpeedInMps,
+		Heading:        msg.DegreesClockwiseFromMagneticNorth,
+		Caption:        msg.Caption,
+		SequenceNumber: msg.SequenceNumber,
+		Thumbnail:      msg.JpegThumbnail,
+	}, &msg.Info)
+}
+
+// HandleContactMessage sends contact card message with parsed vCard to webhook.
+func (h *Handler) HandleContactMessage(msg whatsapp.ContactMessage) {
+	h.handleMessage(&ContactMessagePayload{
+		MessagePayload: newMessagePayload(ContactPayloadType, &msg.Info),
+		DisplayName:    msg.DisplayName,
+		VCard:          msg.Vcard,
+		Contact:        model.ParseVCard(msg.Vcard),
+	}, &msg.Info)
+}
+
+// Checks whether message should be handled and sends it to webhook.
+func (h *Handler) handleMessage(payload interface{}, info *whatsapp.MessageInfo) {
+	if !h.isMessageAllowedToHandle(info) {
 		return
 	}
 
-	_, err = h.client.Post(h.SessionWebhookURL(), "application/json", bytes.NewBuffer(requestBody))
+	log.Printf("got msg to handle from `%v`, destination `%v`", info.RemoteJid, h.Session.WhatsAppSession.Wid)
+
+	h.sendToWebhook(payload, info)
+}
+
+// Checks whether media message should be handled and downloads its content.
+func (h *Handler) handleMediaMessage(msg MediaMessage, info *whatsapp.MessageInfo, mimeType string) (MediaPayload, bool) {
+	if !h.isMessageAllowedToHandle(info) {
+		return MediaPayload{}, false
+	}
+
+	log.Printf("got media msg to handle from `%v`, destination `%v`", info.RemoteJid, h.Session.WhatsAppSession.Wid)
+
+	media, err := h.mediaDownloader.Download(msg, info, mimeType)
 	if err != nil {
-		log.Println("error happened getting the response", err)
+		log.Printf("can't handle media of msg `%s`: %v\n", info.Id, err)
+		return media, false
+	}
+	return media, true
+}
+
+func (h *Handler) sendToWebhook(payload interface{}, info *whatsapp.MessageInfo) {
+	if !h.postToWebhook(payload) {
 		return
 	}
 
 	log.Printf("msg sent to `%s`, by session `%s`, login `%s`", h.SessionWebhookURL(), h.Session.SessionID, h.Session.WhatsAppSession.Wid)
 
-	err = h.messageRepo.SaveMessageTime("wapi_sent_message:"+msg.Info.Id, time.Now())
+	err := h.messageRepo.SaveMessageTime("wapi_sent_message:"+info.Id, time.Now())
 	if err != nil {
-		log.Printf("can't store msg id `%s` in redis: %v\n", msg.Info.Id, err)
+		log.Printf("can't store msg id `%s` in redis: %v\n", info.Id, err)
+		return
+	}
+	h.saveLastTimestamp(info.Timestamp)
+}
+
+// saveLastTimestamp stores timestamp of message sent to webhook if it's the latest one,
+// so messages received while session isn't listening are caught up from it.
+func (h *Handler) saveLastTimestamp(timestamp uint64) {
+	h.lastTimestampMutex.Lock()
+	defer h.lastTimestampMutex.Unlock()
+	if timestamp <= h.lastTimestamp {
+		return
+	}
+	if err := h.messageRepo.SaveLastMessageTimestamp(h.Session.SessionID, timestamp); err != nil {
+		log.Printf("can't store last msg timestamp of session `%s`: %v\n", h.Session.SessionID, err)
 		return
 	}
+	h.lastTimestamp = timestamp
+}
+
+// postToWebhook posts payload to webhook of session, false is returned if payload isn't posted.
+func (h *Handler) postToWebhook(payload interface{}) bool {
+	marshal := *h.marshal
+	requestBody, err := marshal(payload)
+	if err != nil {
+		log.Println("error msg marshaling", err)
+		return false
+	}
+
+	response, err := h.client.Post(h.SessionWebhookURL(), "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		log.Println("error happened getting the response", err)
+		return false
+	}
+	if err = response.Body.Close(); err != nil {
+		log.Println("error closing the response body", err)
+	}
+	return true
 }
 
-func (h *Handler) isMessageAllowedToHandle(msg *whatsapp.TextMessage) bool {
-	if h.messageAlreadySent(msg.Info.Id) {
+func (h *Handler) isMessageAllowedToHandle(info *whatsapp.MessageInfo) bool {
+	if h.InitTimestamp == 0 {
+		h.InitTimestamp = uint64(time.Now().Unix())
+	}
+	if h.messageAlreadySent(info.Id) {
 		return false
 	}
-	if msg.Info.Timestamp <= h.InitTimestamp {
+	if info.Timestamp <= h.InitTimestamp {
 		return false
 	}
-	if msg.Info.FromMe {
+	if info.FromMe {
 		return false
 	}
 	return true
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"testing"
+	"time"
 
 	httpInfra "github.com/r-erema/wapi/internal/infrastructure/http"
 	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
@@ -19,6 +21,7 @@ import (
 	"github.com/Rhymen/go-whatsapp"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type msgTestData struct {
@@ -27,6 +30,12 @@ type msgTestData struct {
 	msg          *whatsapp.TextMessage
 }
 
+type imageMsgTestData struct {
+	name         string
+	mocksFactory msgMocksFactory
+	msg          whatsapp.ImageMessage
+}
+
 type msgHandleErrData struct {
 	name         string
 	mocksFactory msgMocksFactory
@@ -37,6 +46,7 @@ type msgMocksFactory func(t *testing.T) (
 	infraWA.Conn,
 	*model.WapiSession,
 	repository.Message,
+	*service.MediaDownloader,
 	service.Connections,
 	repository.Session,
 	httpInfra.Client,
@@ -63,6 +73,7 @@ func TestHandleError(t *testing.T) {
 				infraWA.Conn,
 				*model.WapiSession,
 				repository.Message,
+				*service.MediaDownloader,
 				service.Connections,
 				repository.Session,
 				httpInfra.Client,
@@ -70,7 +81,7 @@ func TestHandleError(t *testing.T) {
 				uint64,
 				string,
 			) {
-				_, sess, msgRepo, connSV, sessRepo, client, marshal, time, wh := msgMocks(t)
+				_, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, time, wh := msgMocks(t)
 
 				c := gomock.NewController(t)
 				conn := mock.NewMockConn(c)
@@ -78,7 +89,7 @@ func TestHandleError(t *testing.T) {
 				conn.EXPECT().AdminTest().Return(true, nil)
 				conn.EXPECT().RestoreWithSession(gomock.Any()).Return(whatsapp.Session{}, errors.New("something went wrong... "))
 
-				return conn, sess, msgRepo, connSV, sessRepo, client, marshal, time, wh
+				return conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, time, wh
 			},
 			err: &whatsapp.ErrConnectionClosed{},
 		},
@@ -88,6 +99,7 @@ func TestHandleError(t *testing.T) {
 				infraWA.Conn,
 				*model.WapiSession,
 				repository.Message,
+				*service.MediaDownloader,
 				service.Connections,
 				repository.Session,
 				httpInfra.Client,
@@ -95,7 +107,7 @@ func TestHandleError(t *testing.T) {
 				uint64,
 				string,
 			) {
-				_, sess, msgRepo, connSV, sessRepo, client, marshal, time, wh := msgMocks(t)
+				_, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, time, wh := msgMocks(t)
 
 				c := gomock.NewController(t)
 				conn := mock.NewMockConn(c)
@@ -103,7 +115,7 @@ func TestHandleError(t *testing.T) {
 				conn.EXPECT().AdminTest().Return(true, nil)
 				conn.EXPECT().RestoreWithSession(gomock.Any()).Return(whatsapp.Session{}, nil)
 
-				return conn, sess, msgRepo, connSV, sessRepo, client, marshal, time, wh
+				return conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, time, wh
 			},
 			err: &whatsapp.ErrConnectionClosed{},
 		},
@@ -145,6 +157,7 @@ func msgHasWrongTimestamp() msgTestData {
 			infraWA.Conn,
 			*model.WapiSession,
 			repository.Message,
+			*service.MediaDownloader,
 			service.Connections,
 			repository.Session,
 			httpInfra.Client,
@@ -152,8 +165,8 @@ func msgHasWrongTimestamp() msgTestData {
 			uint64,
 			string,
 		) {
-			conn, sess, msgRepo, connSV, sessRepo, client, marshal, _, wh := msgMocks(t)
-			return conn, sess, msgRepo, connSV, sessRepo, client, marshal, 15, wh
+			conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, _, wh := msgMocks(t)
+			return conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, 15, wh
 		},
 		msg: &whatsapp.TextMessage{
 			Info: whatsapp.MessageInfo{Timestamp: 111, RemoteJid: "+000000000000"},
@@ -168,6 +181,7 @@ func msgAlreadySent() msgTestData {
 			infraWA.Conn,
 			*model.WapiSession,
 			repository.Message,
+			*service.MediaDownloader,
 			service.Connections,
 			repository.Session,
 			httpInfra.Client,
@@ -175,11 +189,11 @@ func msgAlreadySent() msgTestData {
 			uint64,
 			string,
 		) {
-			conn, sess, _, connSV, sessRepo, client, marshal, _, wh := msgMocks(t)
+			conn, sess, _, mediaDownloader, connSV, sessRepo, client, marshal, _, wh := msgMocks(t)
 			c := gomock.NewController(t)
 			msgRepo := mock.NewMockMessage(c)
 			msgRepo.EXPECT().MessageTime(gomock.Any()).Return(nil, nil)
-			return conn, sess, msgRepo, connSV, sessRepo, client, marshal, 0, wh
+			return conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, 0, wh
 		},
 		msg: &whatsapp.TextMessage{
 			Info: whatsapp.MessageInfo{Timestamp: 112, RemoteJid: "+000000000000"},
@@ -194,6 +208,7 @@ func dontHandleFromMeMsg() msgTestData {
 			infraWA.Conn,
 			*model.WapiSession,
 			repository.Message,
+			*service.MediaDownloader,
 			service.Connections,
 			repository.Session,
 			httpInfra.Client,
@@ -201,8 +216,8 @@ func dontHandleFromMeMsg() msgTestData {
 			uint64,
 			string,
 		) {
-			conn, sess, msgRepo, connSV, sessRepo, client, marshal, _, wh := msgMocks(t)
-			return conn, sess, msgRepo, connSV, sessRepo, client, marshal, 7, wh
+			conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, _, wh := msgMocks(t)
+			return conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, 7, wh
 		},
 		msg: &whatsapp.TextMessage{
 			Info: whatsapp.MessageInfo{Timestamp: 8, RemoteJid: "+000000000000", FromMe: true},
@@ -217,6 +232,7 @@ func msgMarshalingError() msgTestData {
 			infraWA.Conn,
 			*model.WapiSession,
 			repository.Message,
+			*service.MediaDownloader,
 			service.Connections,
 			repository.Session,
 			httpInfra.Client,
@@ -224,11 +240,11 @@ func msgMarshalingError() msgTestData {
 			uint64,
 			string,
 		) {
-			conn, sess, msgRepo, connSV, sessRepo, client, _, _, wh := msgMocks(t)
+			conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, _, _, wh := msgMocks(t)
 			marshal := jsonInfra.MarshallCallback(func(i interface{}) ([]byte, error) {
 				return nil, errors.New("marshaling error")
 			})
-			return conn, sess, msgRepo, connSV, sessRepo, client, &marshal, 1, wh
+			return conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, &marshal, 1, wh
 		},
 		msg: &whatsapp.TextMessage{
 			Info: whatsapp.MessageInfo{Timestamp: 2, RemoteJid: "+000000000000"},
@@ -243,6 +259,7 @@ func msgSendToBebHookError() msgTestData {
 			infraWA.Conn,
 			*model.WapiSession,
 			repository.Message,
+			*service.MediaDownloader,
 			service.Connections,
 			repository.Session,
 			httpInfra.Client,
@@ -250,13 +267,13 @@ func msgSendToBebHookError() msgTestData {
 			uint64,
 			string,
 		) {
-			conn, sess, msgRepo, connSV, sessRepo, _, marshal, _, wh := msgMocks(t)
+			conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, _, marshal, _, wh := msgMocks(t)
 			c := gomock.NewController(t)
 			client := mock.NewMockClient(c)
 			client.EXPECT().
 				Post(gomock.Any(), gomock.Any(), gomock.Any()).
 				Return(nil, errors.New("something went wrong... "))
-			return conn, sess, msgRepo, connSV, sessRepo, client, marshal, 10, wh
+			return conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, 10, wh
 		},
 		msg: &whatsapp.TextMessage{
 			Info: whatsapp.MessageInfo{Timestamp: 22, RemoteJid: "+000000000000"},
@@ -271,6 +288,7 @@ func msgSavingError() msgTestData {
 			infraWA.Conn,
 			*model.WapiSession,
 			repository.Message,
+			*service.MediaDownloader,
 			service.Connections,
 			repository.Session,
 			httpInfra.Client,
@@ -278,12 +296,12 @@ func msgSavingError() msgTestData {
 			uint64,
 			string,
 		) {
-			conn, sess, _, connSV, sessRepo, client, marshal, _, wh := msgMocks(t)
+			conn, sess, _, mediaDownloader, connSV, sessRepo, client, marshal, _, wh := msgMocks(t)
 			c := gomock.NewController(t)
 			msgRepo := mock.NewMockMessage(c)
 			msgRepo.EXPECT().SaveMessageTime(gomock.Any(), gomock.Any()).Return(errors.New("saving error"))
 			msgRepo.EXPECT().MessageTime(gomock.Any()).Return(nil, errors.New("message not found"))
-			return conn, sess, msgRepo, connSV, sessRepo, client, marshal, 100, wh
+			return conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, 100, wh
 		},
 		msg: &whatsapp.TextMessage{
 			Info: whatsapp.MessageInfo{Timestamp: 200, RemoteJid: "+000000000000"},
@@ -311,10 +329,180 @@ func TestHandleTextMessage(t *testing.T) {
 	}
 }
 
+func TestHandleImageMessage(t *testing.T) {
+	tests := []imageMsgTestData{
+		{
+			name:         "Image message has wrong timestamp",
+			mocksFactory: msgHasWrongTimestamp().mocksFactory,
+			msg: whatsapp.ImageMessage{
+				Info: whatsapp.MessageInfo{Timestamp: 11, RemoteJid: "+000000000000"},
+			},
+		},
+		{
+			name:         "Don't handle `from me` image message",
+			mocksFactory: dontHandleFromMeMsg().mocksFactory,
+			msg: whatsapp.ImageMessage{
+				Info: whatsapp.MessageInfo{Timestamp: 8, RemoteJid: "+000000000000", FromMe: true},
+			},
+		},
+		{
+			name:         "Image downloading error",
+			mocksFactory: msgMocks,
+			msg: whatsapp.ImageMessage{
+				Info:    whatsapp.MessageInfo{Timestamp: 22, RemoteJid: "+000000000001"},
+				Caption: "caption",
+				Type:    "image/jpeg",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			h := service.NewMsgHandler(tt.mocksFactory(t))
+			h.HandleImageMessage(tt.msg)
+		})
+	}
+}
+
+func TestHandleMediaMessages(t *testing.T) {
+	tests := []struct {
+		name         string
+		mocksFactory msgMocksFactory
+		handle       func(h *service.Handler)
+	}{
+		{
+			name:         "Document downloading error",
+			mocksFactory: msgMocks,
+			handle: func(h *service.Handler) {
+				h.HandleDocumentMessage(whatsapp.DocumentMessage{
+					Info:     whatsapp.MessageInfo{Timestamp: 22, RemoteJid: "+000000000001"},
+					FileName: "invoice.pdf",
+					Type:     "application/pdf",
+				})
+			},
+		},
+		{
+			name:         "Audio downloading error",
+			mocksFactory: msgMocks,
+			handle: func(h *service.Handler) {
+				h.HandleAudioMessage(whatsapp.AudioMessage{
+					Info: whatsapp.MessageInfo{Timestamp: 22, RemoteJid: "+000000000001"},
+					Ptt:  true,
+					Type: "audio/ogg; codecs=opus",
+				})
+			},
+		},
+		{
+			name:         "Video downloading error",
+			mocksFactory: msgMocks,
+			handle: func(h *service.Handler) {
+				h.HandleVideoMessage(whatsapp.VideoMessage{
+					Info: whatsapp.MessageInfo{Timestamp: 22, RemoteJid: "+000000000001"},
+					Type: "video/mp4",
+				})
+			},
+		},
+		{
+			name:         "Video message has wrong timestamp",
+			mocksFactory: msgHasWrongTimestamp().mocksFactory,
+			handle: func(h *service.Handler) {
+				h.HandleVideoMessage(whatsapp.VideoMessage{
+					Info: whatsapp.MessageInfo{Timestamp: 11, RemoteJid: "+000000000000"},
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.handle(service.NewMsgHandler(tt.mocksFactory(t)))
+		})
+	}
+}
+
+func TestHandleLocationAndContactMessages(t *testing.T) {
+	tests := []struct {
+		name         string
+		mocksFactory msgMocksFactory
+		handle       func(h *service.Handler)
+	}{
+		{
+			name:         "Location message OK",
+			mocksFactory: msgMocks,
+			handle: func(h *service.Handler) {
+				h.HandleLocationMessage(whatsapp.LocationMessage{
+					Info:             whatsapp.MessageInfo{Timestamp: 22, RemoteJid: "+000000000001"},
+					DegreesLatitude:  53.9,
+					DegreesLongitude: 27.56,
+					Name:             "Minsk",
+				})
+			},
+		},
+		{
+			name:         "Live location message OK",
+			mocksFactory: msgMocks,
+			handle: func(h *service.Handler) {
+				h.HandleLiveLocationMessage(whatsapp.LiveLocationMessage{
+					Info:             whatsapp.MessageInfo{Timestamp: 22, RemoteJid: "+000000000001"},
+					DegreesLatitude:  53.9,
+					DegreesLongitude: 27.56,
+					AccuracyInMeters: 10,
+				})
+			},
+		},
+		{
+			name:         "Don't handle `from me` location message",
+			mocksFactory: dontHandleFromMeMsg().mocksFactory,
+			handle: func(h *service.Handler) {
+				h.HandleLocationMessage(whatsapp.LocationMessage{
+					Info: whatsapp.MessageInfo{Timestamp: 8, RemoteJid: "+000000000000", FromMe: true},
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.handle(service.NewMsgHandler(tt.mocksFactory(t)))
+		})
+	}
+}
+
+func TestHandleContactMessage(t *testing.T) {
+	conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, _, marshal, _, wh := msgMocks(t)
+
+	var payload service.ContactMessagePayload
+	c := gomock.NewController(t)
+	client := mock.NewMockClient(c)
+	client.EXPECT().
+		Post(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(url, contentType string, body io.Reader) (*http.Response, error) {
+			require.Nil(t, json.NewDecoder(body).Decode(&payload))
+			return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+		})
+
+	h := service.NewMsgHandler(conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, 0, wh)
+	h.HandleContactMessage(whatsapp.ContactMessage{
+		Info:        whatsapp.MessageInfo{Id: "MSG_ID", Timestamp: uint64(time.Now().Unix() + 1), RemoteJid: "+000000000001"},
+		DisplayName: "John Doe",
+		Vcard:       "BEGIN:VCARD\nVERSION:3.0\nFN:John Doe\nTEL;waid=375447034810:+375 44 703-48-10\nEND:VCARD",
+	})
+
+	assert.Equal(t, service.ContactPayloadType, payload.Type)
+	assert.Equal(t, "MSG_ID", payload.ID)
+	assert.Equal(t, "+000000000001", payload.Sender)
+	assert.Equal(t, "John Doe", payload.Contact.FullName)
+	assert.Equal(t, "375447034810", payload.Contact.Phones[0].WaID)
+}
+
 func msgMocks(t *testing.T) (
 	conn infraWA.Conn,
 	sess *model.WapiSession,
 	msgRepo repository.Message,
+	mediaDownloader *service.MediaDownloader,
 	connSupervisor service.Connections,
 	sessRepo repository.Session,
 	client httpInfra.Client,
@@ -334,8 +522,13 @@ func msgMocks(t *testing.T) (
 	msgRepoMock := mock.NewMockMessage(c)
 	msgRepoMock.EXPECT().MessageTime(gomock.Any()).Return(nil, errors.New("message not found"))
 	msgRepoMock.EXPECT().SaveMessageTime(gomock.Any(), gomock.Any()).Return(nil)
+	msgRepoMock.EXPECT().SaveLastMessageTimestamp(gomock.Any(), gomock.Any()).Return(nil)
 	msgRepo = msgRepoMock
 
+	mediaRepoMock := mock.NewMockMedia(c)
+	mediaRepoMock.EXPECT().SaveMedia(gomock.Any(), gomock.Any()).Return("https://wapi.host/get-media/file.jpg", nil)
+	mediaDownloader = service.NewMediaDownloader(mediaRepoMock, 1024)
+
 	connSupervisorMock := mock.NewMockConnections(c)
 	connSupervisorMock.EXPECT().RemoveConnectionForSession(gomock.Any())
 	connSupervisor = connSupervisorMock
@@ -352,5 +545,5 @@ func msgMocks(t *testing.T) (
 
 	m := jsonInfra.MarshallCallback(json.Marshal)
 	marshal = &m
-	return conn, sess, msgRepo, connSupervisor, sessRepo, client, marshal, 0, "webhook/url"
+	return conn, sess, msgRepo, mediaDownloader, connSupervisor, sessRepo, client, marshal, 0, "webhook/url"
 }
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+
+	"github.com/pkg/errors"
+)
+
+// NumberChecker checks whether phone numbers are registered on WhatsApp.
+type NumberChecker interface {
+	// Check checks phone numbers by connection of session.
+	Check(sessionID string, phones []string) ([]model.NumberCheck, error)
+}
+
+// CachedNumberChecker checks phone numbers by exist queries of session connection,
+// results are cached for ttl, so repeated checks don't query WhatsApp.
+type CachedNumberChecker struct {
+	connectionsSupervisor Connections
+	contactRepo           repository.Contact
+	jidNormalizer         *JidNormalizer
+	ttl                   time.Duration
+}
+
+// NewCachedNumberChecker creates CachedNumberChecker.
+func NewCachedNumberChecker(
+	connectionsSupervisor Connections,
+	contactRepo repository.Contact,
+	jidNormalizer *JidNormalizer,
+	ttl time.Duration,
+) *CachedNumberChecker {
+	return &CachedNumberChecker{
+		connectionsSupervisor: connectionsSupervisor,
+		contactRepo:           contactRepo,
+		jidNormalizer:         jidNormalizer,
+		ttl:                   ttl,
+	}
+}
+
+// Check checks phone numbers by connection of session, invalid numbers and failed checks are reported by errors of results.
+// Connection is resolved only if some numbers aren't cached.
+func (c *CachedNumberChecker) Check(sessionID string, phones []string) ([]model.NumberCheck, error) {
+	var sessConnDTO *SessionConnectionDTO
+	results := make([]model.NumberCheck, 0, len(phones))
+	for _, phone := range phones {
+		result := model.NumberCheck{Phone: phone}
+		jid, err := c.jidNormalizer.PhoneToJid(phone)
+		if err != nil {
+			result.Error = errors.Cause(err).Error()
+			results = append(results, result)
+			continue
+		}
+		result.Jid = jid
+
+		exists, err := c.contactRepo.NumberExists(jid)
+		if err != nil {
+			log.Printf("can't read cached check of number `%s`: %v\n", jid, err)
+		}
+		if exists != nil {
+			result.Exists = *exists
+			results = append(results, result)
+			continue
+		}
+
+		if sessConnDTO == nil {
+			if sessConnDTO, err = c.connectionsSupervisor.AuthenticatedConnectionForSession(sessionID); err != nil {
+				return nil, errors.Wrap(err, "can't find connection of session")
+			}
+		}
+		if result.Exists, err = sessConnDTO.Wac().Exist(jid); err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		if err = c.contactRepo.SaveNumberExists(jid, result.Exists, c.ttl); err != nil {
+			log.Printf("can't cache check of number `%s`: %v\n", jid, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
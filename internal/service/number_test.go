@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedNumberChecker_Check(t *testing.T) {
+	c := gomock.NewController(t)
+	exists := true
+	contactRepo := mock.NewMockContact(c)
+	contactRepo.EXPECT().NumberExists("375000000001@s.whatsapp.net").Return(&exists, nil)
+	contactRepo.EXPECT().NumberExists("375000000002@s.whatsapp.net").Return(nil, nil)
+	contactRepo.EXPECT().NumberExists("375000000003@s.whatsapp.net").Return(nil, nil)
+	contactRepo.EXPECT().SaveNumberExists("375000000002@s.whatsapp.net", false, time.Hour)
+
+	wac := mock.NewMockConn(c)
+	wac.EXPECT().Exist("375000000002@s.whatsapp.net").Return(false, nil)
+	wac.EXPECT().Exist("375000000003@s.whatsapp.net").Return(false, errors.New("timed out"))
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession("_sid_").
+		Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+
+	checker := service.NewCachedNumberChecker(connections, contactRepo, service.NewJidNormalizer(""), time.Hour)
+	results, err := checker.Check("_sid_", []string{"+375000000001", "+375000000002", "+375000000003", "invalid"})
+	require.Nil(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, model.NumberCheck{Phone: "+375000000001", Jid: "375000000001@s.whatsapp.net", Exists: true}, results[0])
+	assert.Equal(t, model.NumberCheck{Phone: "+375000000002", Jid: "375000000002@s.whatsapp.net"}, results[1])
+	assert.Equal(t, "timed out", results[2].Error)
+	assert.Empty(t, results[3].Jid)
+	assert.NotEmpty(t, results[3].Error)
+}
+
+func TestCachedNumberChecker_CheckNotConnected(t *testing.T) {
+	c := gomock.NewController(t)
+	contactRepo := mock.NewMockContact(c)
+	contactRepo.EXPECT().NumberExists("375000000001@s.whatsapp.net").Return(nil, nil)
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession("_sid_").
+		Return(nil, &service.NotFoundError{SessionID: "_sid_"})
+
+	checker := service.NewCachedNumberChecker(connections, contactRepo, service.NewJidNormalizer(""), time.Hour)
+	results, err := checker.Check("_sid_", []string{"+375000000001"})
+	assert.NotNil(t, err)
+	assert.Nil(t, results)
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"github.com/r-erema/wapi/internal/model"
+
+	"github.com/Rhymen/go-whatsapp"
+)
+
+// Types of messages sent to webhook.
+const (
+	ImagePayloadType        = "image"
+	DocumentPayloadType     = "document"
+	AudioPayloadType        = "audio"
+	VideoPayloadType        = "video"
+	LocationPayloadType     = "location"
+	LiveLocationPayloadType = "live_location"
+	ContactPayloadType      = "contact"
+
+	MessageFailedPayloadType  = "message_failed"  // Queued message couldn't be sent.
+	MessageStatusPayloadType  = "message_status"  // Delivery status of sent message changed.
+	MessageRevokedPayloadType = "message_revoked" // Message was deleted for everyone by its sender.
+
+	// Changes of groups.
+	GroupCreatedPayloadType             = "group.created"
+	GroupParticipantAddedPayloadType    = "group.participant_added"
+	GroupParticipantRemovedPayloadType  = "group.participant_removed"
+	GroupParticipantLeftPayloadType     = "group.participant_left"
+	GroupParticipantPromotedPayloadType = "group.participant_promoted"
+	GroupParticipantDemotedPayloadType  = "group.participant_demoted"
+	GroupSubjectChangedPayloadType      = "group.subject_changed"
+	GroupDescriptionChangedPayloadType  = "group.description_changed"
+
+	// Presences of subscribed contacts.
+	PresenceAvailablePayloadType   = "presence.available"
+	PresenceUnavailablePayloadType = "presence.unavailable"
+	PresenceComposingPayloadType   = "presence.composing"
+	PresenceRecordingPayloadType   = "presence.recording"
+	PresencePausedPayloadType      = "presence.paused"
+)
+
+// MessagePayload contains common fields of messages sent to webhook.
+type MessagePayload struct {
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	ChatID    string `json:"chat_id"`
+	Sender    string `json:"sender"`
+	PushName  string `json:"push_name"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// MediaPayload describes media content of message sent to webhook.
+// Content is represented whether by url of stored media or inline in base64,
+// media exceeding max media size is sent without content.
+type MediaPayload struct {
+	MimeType    string `json:"mime_type"`
+	Size        uint64 `json:"size"`
+	MediaURL    string `json:"media_url,omitempty"`
+	MediaBase64 string `json:"media_base64,omitempty"`
+	TooLarge    bool   `json:"too_large,omitempty"`
+}
+
+// ImageMessagePayload is an image message sent to webhook.
+type ImageMessagePayload struct {
+	MessagePayload
+	MediaPayload
+	Caption   string `json:"caption"`
+	Thumbnail []byte `json:"thumbnail,omitempty"`
+}
+
+// DocumentMessagePayload is a document message sent to webhook.
+type DocumentMessagePayload struct {
+	MessagePayload
+	MediaPayload
+	FileName  string `json:"file_name"`
+	Title     string `json:"title"`
+	PageCount uint32 `json:"page_count"`
+	Thumbnail []byte `json:"thumbnail,omitempty"`
+}
+
+// AudioMessagePayload is an audio message sent to webhook, ptt flag marks voice notes.
+type AudioMessagePayload struct {
+	MessagePayload
+	MediaPayload
+	Duration uint32 `json:"duration"`
+	Ptt      bool   `json:"ptt"`
+}
+
+// VideoMessagePayload is a video message sent to webhook.
+type VideoMessagePayload struct {
+	MessagePayload
+	MediaPayload
+	Caption     string `json:"caption"`
+	Duration    uint32 `json:"duration"`
+	GifPlayback bool   `json:"gif_playback"`
+	Thumbnail   []byte `json:"thumbnail,omitempty"`
+}
+
+// LocationMessagePayload is a location message sent to webhook.
+type LocationMessagePayload struct {
+	MessagePayload
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Name      string  `json:"name"`
+	Address   string  `json:"address"`
+	URL       string  `json:"url"`
+	Thumbnail []byte  `json:"thumbnail,omitempty"`
+}
+
+// LiveLocationMessagePayload is a live location message sent to webhook.
+type LiveLocationMessagePayload struct {
+	MessagePayload
+	Latitude       float64 `json:"latitude"`
+	Longitude      float64 `json:"longitude"`
+	Accuracy       uint32  `json:"accuracy"` // Accuracy in meters.
+	Speed          float32 `json:"speed"`    // Speed in meters per second.
+	Heading        uint32  `json:"heading"`  // Degrees clockwise from magnetic north.
+	Caption        string  `json:"caption"`
+	SequenceNumber int64   `json:"sequence_number"`
+	Thumbnail      []byte  `json:"thumbnail,omitempty"`
+}
+
+// ContactMessagePayload is a contact card message sent to webhook.
+type ContactMessagePayload struct {
+	MessagePayload
+	DisplayName string       `json:"display_name"`
+	VCard       string       `json:"vcard"`
+	Contact     *model.VCard `json:"contact"`
+}
+
+// MessageFailedPayload notifies webhook about queued message all attempts of sending which failed.
+type MessageFailedPayload struct {
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	ChatID    string `json:"chat_id"`
+	SessionID string `json:"session_name"`
+	Attempts  int    `json:"attempts"`
+	Error     string `json:"error"`
+}
+
+// MessageStatusPayload notifies webhook about delivery status change of message sent by session,
+// participant is filled for group chats.
+type MessageStatusPayload struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	ChatID      string `json:"chat_id"`
+	Participant string `json:"participant,omitempty"`
+	SessionID   string `json:"session_name"`
+	Status      string `json:"status"`
+	Timestamp   uint64 `json:"timestamp"`
+}
+
+// MessageRevokedPayload notifies webhook about message deleted for everyone by its sender,
+// revoked id is an id of deleted message.
+type MessageRevokedPayload struct {
+	MessagePayload
+	RevokedID string `json:"revoked_id"`
+}
+
+// GroupEventPayload notifies webhook about change of group, author is a participant who made the change,
+// participants are set for events of participants, subject and description are set for events of their changes.
+type GroupEventPayload struct {
+	Type         string   `json:"type"`
+	GroupID      string   `json:"group_id"`
+	SessionID    string   `json:"session_name"`
+	Author       string   `json:"author,omitempty"`
+	Participants []string `json:"participants,omitempty"`
+	Subject      string   `json:"subject,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Timestamp    uint64   `json:"timestamp"`
+}
+
+// PresencePayload notifies webhook about presence of subscribed contact, participant is set for presences in groups,
+// last seen time is set for unavailable contacts which don't hide it.
+type PresencePayload struct {
+	Type        string `json:"type"`
+	Jid         string `json:"jid"`
+	Participant string `json:"participant,omitempty"`
+	SessionID   string `json:"session_name"`
+	LastSeen    uint64 `json:"last_seen,omitempty"`
+	Timestamp   uint64 `json:"timestamp"`
+}
+
+func newMessagePayload(payloadType string, info *whatsapp.MessageInfo) MessagePayload {
+	sender := info.SenderJid
+	if sender == "" {
+		sender = info.RemoteJid
+	}
+	return MessagePayload{
+		Type:      payloadType,
+		ID:        info.Id,
+		ChatID:    info.RemoteJid,
+		Sender:    sender,
+		PushName:  info.PushName,
+		Timestamp: info.Timestamp,
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+
+	"github.com/pkg/errors"
+)
+
+// Limits of number of digits in phone numbers, E.164 numbers have up to 15 digits.
+const (
+	minPhoneDigits = 7
+	maxPhoneDigits = 15
+)
+
+// Suffixes of JIDs of WhatsApp accounts and groups.
+const (
+	UserJidSuffix  = "@s.whatsapp.net"
+	GroupJidSuffix = "@g.us"
+)
+
+// groupIDRegexp matches ids of groups, they consist of phone of creator and creation timestamp or of digits only.
+var groupIDRegexp = regexp.MustCompile(`^\d+(-\d+)?$`)
+
+// JidNormalizer converts chat ids given by clients to JIDs of WhatsApp:
+// phone numbers are converted to user JIDs, JIDs are validated.
+type JidNormalizer struct {
+	defaultCountryCode string
+}
+
+// NewJidNormalizer creates JidNormalizer, defaultCountryCode is a calling code prepended to
+// phone numbers given without international prefix, if it's empty all numbers are considered international.
+func NewJidNormalizer(defaultCountryCode string) *JidNormalizer {
+	return &JidNormalizer{defaultCountryCode: defaultCountryCode}
+}
+
+// Normalize converts chat id to JID, it's either user or group JID, e.g. `375447034810@s.whatsapp.net`,
+// `375447034810-1589212345@g.us` or phone number e.g. `+375 44 703-48-10`.
+func (n *JidNormalizer) Normalize(chatID string) (string, error) {
+	chatID = strings.TrimSpace(chatID)
+	if chatID == "" {
+		return "", errors.New("chat id is empty")
+	}
+	if !strings.Contains(chatID, "@") {
+		return n.PhoneToJid(chatID)
+	}
+
+	chatID = whatsapp.NormalizeUserJid(chatID)
+	switch {
+	case strings.HasSuffix(chatID, GroupJidSuffix):
+		if !groupIDRegexp.MatchString(strings.TrimSuffix(chatID, GroupJidSuffix)) {
+			return "", errors.Errorf("invalid group JID `%s`", chatID)
+		}
+		return chatID, nil
+	case strings.HasSuffix(chatID, UserJidSuffix):
+		user := strings.TrimSuffix(chatID, UserJidSuffix)
+		if !isPhoneDigits(user) {
+			return "", errors.Errorf("invalid user JID `%s`", chatID)
+		}
+		return user + UserJidSuffix, nil
+	}
+	return "", errors.Errorf("JID `%s` must end with `%s` or `%s`", chatID, UserJidSuffix, GroupJidSuffix)
+}
+
+// PhoneToJid converts phone number to JID of WhatsApp account, e.g. `+375 (44) 703-48-10` is converted
+// to `375447034810@s.whatsapp.net`. Numbers starting with `+` or `00` are international, other numbers
+// are national if default country code is set: their trunk prefix `0` is replaced by the country code.
+func (n *JidNormalizer) PhoneToJid(phone string) (string, error) {
+	phone = strings.TrimSpace(phone)
+	digits := strings.Builder{}
+	for i, r := range phone {
+		switch {
+		case unicode.IsDigit(r):
+			digits.WriteRune(r)
+		case r == '+' && i == 0, r == ' ', r == '-', r == '(', r == ')', r == '.':
+		default:
+			return "", errors.Errorf("phone `%s` contains invalid character `%c`", phone, r)
+		}
+	}
+
+	number := digits.String()
+	switch {
+	case strings.HasPrefix(phone, "+"):
+	case strings.HasPrefix(number, "00"):
+		number = strings.TrimPrefix(number, "00")
+	case n.defaultCountryCode != "":
+		number = n.defaultCountryCode + strings.TrimLeft(number, "0")
+	}
+	if !isPhoneDigits(number) {
+		return "", errors.Errorf("phone `%s` must have from %d to %d digits", phone, minPhoneDigits, maxPhoneDigits)
+	}
+	return number + UserJidSuffix, nil
+}
+
+// isPhoneDigits checks whether number consists of valid number of digits only.
+func isPhoneDigits(number string) bool {
+	if len(number) < minPhoneDigits || len(number) > maxPhoneDigits {
+		return false
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/r-erema/wapi/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJidNormalizer_Normalize(t *testing.T) {
+	tests := []struct {
+		name               string
+		defaultCountryCode string
+		chatID             string
+		expectJid          string
+		expectErr          bool
+	}{
+		{name: "User JID", chatID: "375447034810@s.whatsapp.net", expectJid: "375447034810@s.whatsapp.net"},
+		{name: "Legacy user JID", chatID: "375447034810@c.us", expectJid: "375447034810@s.whatsapp.net"},
+		{name: "Group JID", chatID: "375447034810-1589212345@g.us", expectJid: "375447034810-1589212345@g.us"},
+		{name: "Formatted phone", chatID: " +375 (44) 703-48.10 ", expectJid: "375447034810@s.whatsapp.net"},
+		{name: "International prefix", chatID: "00375447034810", expectJid: "375447034810@s.whatsapp.net"},
+		{name: "Digits without default country", chatID: "375447034810", expectJid: "375447034810@s.whatsapp.net"},
+		{
+			name:               "National phone",
+			defaultCountryCode: "375",
+			chatID:             "044 703-48-10",
+			expectJid:          "375447034810@s.whatsapp.net",
+		},
+		{
+			name:               "International phone with default country",
+			defaultCountryCode: "375",
+			chatID:             "+48 500 000 000",
+			expectJid:          "48500000000@s.whatsapp.net",
+		},
+		{name: "Empty", chatID: " ", expectErr: true},
+		{name: "Letters", chatID: "+37544703481O", expectErr: true},
+		{name: "Plus inside", chatID: "375+447034810", expectErr: true},
+		{name: "Too short", chatID: "+123456", expectErr: true},
+		{name: "Too long", chatID: "+1234567890123456", expectErr: true},
+		{name: "Invalid user JID", chatID: "+375447034810@s.whatsapp.net", expectErr: true},
+		{name: "Invalid group JID", chatID: "group@g.us", expectErr: true},
+		{name: "Unknown server", chatID: "375447034810@example.com", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			jid, err := service.NewJidNormalizer(tt.defaultCountryCode).Normalize(tt.chatID)
+			assert.Equal(t, tt.expectErr, err != nil)
+			assert.Equal(t, tt.expectJid, jid)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+)
+
+// Presences of contacts mapped on types of events sent to webhook.
+var presencePayloadTypes = map[string]string{
+	"available":   PresenceAvailablePayloadType,
+	"unavailable": PresenceUnavailablePayloadType,
+	"composing":   PresenceComposingPayloadType,
+	"recording":   PresenceRecordingPayloadType,
+	"paused":      PresencePausedPayloadType,
+}
+
+// Presence event of subscribed contact, `t` is a last seen time of unavailable contact,
+// participant is set for presences in groups, deny flag is set if contact hides last seen time.
+type presenceEvent struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	T           int64  `json:"t"`
+	Participant string `json:"participant"`
+	Deny        bool   `json:"deny"`
+}
+
+// Notifies webhook about presence of contact.
+func (h *Handler) handlePresence(event json.RawMessage) {
+	var presence presenceEvent
+	if err := json.Unmarshal(event, &presence); err != nil || presence.ID == "" {
+		return
+	}
+	payloadType, ok := presencePayloadTypes[presence.Type]
+	if !ok {
+		return
+	}
+
+	payload := &PresencePayload{
+		Type:        payloadType,
+		Jid:         whatsapp.NormalizeUserJid(presence.ID),
+		Participant: whatsapp.NormalizeUserJid(presence.Participant),
+		SessionID:   h.Session.SessionID,
+		Timestamp:   uint64(time.Now().Unix()),
+	}
+	if presence.Type == "unavailable" && presence.T > 0 && !presence.Deny {
+		payload.LastSeen = uint64(presence.T)
+	}
+	if h.postToWebhook(payload) {
+		log.Printf("presence `%s` of `%s` sent by session `%s`", payloadType, presence.ID, h.Session.SessionID)
+	}
+}
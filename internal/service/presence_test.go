@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	jsonInfra "github.com/r-erema/wapi/internal/infrastructure/json"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleJsonMessage_PresenceEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		message       string
+		expectPayload *service.PresencePayload
+	}{
+		{
+			name:    "Available",
+			message: `["Presence",{"id":"375440000001@c.us","type":"available"}]`,
+			expectPayload: &service.PresencePayload{
+				Type:      service.PresenceAvailablePayloadType,
+				Jid:       "375440000001@s.whatsapp.net",
+				SessionID: "_sid_",
+			},
+		},
+		{
+			name:    "Last seen",
+			message: `["Presence",{"id":"375440000001@c.us","type":"unavailable","t":1600000001}]`,
+			expectPayload: &service.PresencePayload{
+				Type:      service.PresenceUnavailablePayloadType,
+				Jid:       "375440000001@s.whatsapp.net",
+				SessionID: "_sid_",
+				LastSeen:  1600000001,
+			},
+		},
+		{
+			name:    "Last seen is hidden",
+			message: `["Presence",{"id":"375440000001@c.us","type":"unavailable","t":1600000001,"deny":true}]`,
+			expectPayload: &service.PresencePayload{
+				Type:      service.PresenceUnavailablePayloadType,
+				Jid:       "375440000001@s.whatsapp.net",
+				SessionID: "_sid_",
+			},
+		},
+		{
+			name:    "Composing in group",
+			message: `["Presence",{"id":"375440000000-1600000000@g.us","type":"composing","participant":"375440000001@c.us"}]`,
+			expectPayload: &service.PresencePayload{
+				Type:        service.PresenceComposingPayloadType,
+				Jid:         "375440000000-1600000000@g.us",
+				Participant: "375440000001@s.whatsapp.net",
+				SessionID:   "_sid_",
+			},
+		},
+		{
+			name:    "Unknown presence",
+			message: `["Presence",{"id":"375440000001@c.us","type":"sleeping"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			expectPosts := 0
+			if tt.expectPayload != nil {
+				expectPosts = 1
+			}
+			var payload service.PresencePayload
+			client := mock.NewMockClient(c)
+			client.EXPECT().
+				Post("webhook/url/_sid_", "application/json", gomock.Any()).
+				DoAndReturn(func(url, contentType string, body io.Reader) (*http.Response, error) {
+					require.Nil(t, json.NewDecoder(body).Decode(&payload))
+					return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+				}).
+				Times(expectPosts)
+
+			m := jsonInfra.MarshallCallback(json.Marshal)
+			sess := &model.WapiSession{SessionID: "_sid_", WhatsAppSession: &whatsapp.Session{Wid: "375440000000@c.us"}}
+			h := service.NewMsgHandler(nil, sess, mock.NewMockMessage(c), nil, nil, nil, client, &m, 0, "webhook/url/")
+			h.HandleJsonMessage(tt.message)
+
+			if tt.expectPayload == nil {
+				return
+			}
+			assert.NotZero(t, payload.Timestamp)
+			payload.Timestamp = 0
+			assert.Equal(t, *tt.expectPayload, payload)
+		})
+	}
+}
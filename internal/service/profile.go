@@ -0,0 +1,62 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+
+	"github.com/pkg/errors"
+)
+
+// ProfileProvider provides profiles of WhatsApp accounts.
+type ProfileProvider interface {
+	// Profile provides profile of account of jid seen by session.
+	Profile(sessionID, jid string) (*model.Profile, error)
+}
+
+// CachedProfileProvider queries profiles by session connection, profiles are cached for ttl,
+// so repeated lookups don't query WhatsApp.
+type CachedProfileProvider struct {
+	connectionsSupervisor Connections
+	contactRepo           repository.Contact
+	ttl                   time.Duration
+}
+
+// NewCachedProfileProvider creates CachedProfileProvider.
+func NewCachedProfileProvider(
+	connectionsSupervisor Connections,
+	contactRepo repository.Contact,
+	ttl time.Duration,
+) *CachedProfileProvider {
+	return &CachedProfileProvider{connectionsSupervisor: connectionsSupervisor, contactRepo: contactRepo, ttl: ttl}
+}
+
+// Profile provides profile of account of jid seen by session, connection is resolved only if profile isn't cached.
+func (p *CachedProfileProvider) Profile(sessionID, jid string) (*model.Profile, error) {
+	profile, err := p.contactRepo.Profile(sessionID, jid)
+	if err != nil {
+		log.Printf("can't read cached profile of `%s`: %v\n", jid, err)
+	}
+	if profile != nil {
+		return profile, nil
+	}
+
+	sessConnDTO, err := p.connectionsSupervisor.AuthenticatedConnectionForSession(sessionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't find connection of session")
+	}
+	wac := sessConnDTO.Wac()
+	profile = &model.Profile{Jid: jid, UpdatedAt: time.Now().UTC()}
+	if profile.PictureURL, err = wac.ProfilePictureURL(jid); err != nil {
+		return nil, errors.Wrap(err, "can't get profile picture")
+	}
+	if profile.Status, err = wac.Status(jid); err != nil {
+		return nil, errors.Wrap(err, "can't get status")
+	}
+	if err = p.contactRepo.SaveProfile(sessionID, profile, p.ttl); err != nil {
+		log.Printf("can't cache profile of `%s`: %v\n", jid, err)
+	}
+	return profile, nil
+}
@@ -0,0 +1,72 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedProfileProvider_Profile(t *testing.T) {
+	c := gomock.NewController(t)
+	contactRepo := mock.NewMockContact(c)
+	contactRepo.EXPECT().Profile("_sid_", "375000000001@s.whatsapp.net").Return(nil, nil)
+	contactRepo.EXPECT().
+		SaveProfile("_sid_", gomock.Any(), time.Hour).
+		DoAndReturn(func(sessionID string, profile *model.Profile, ttl time.Duration) error {
+			assert.Equal(t, "https://pps.whatsapp.net/picture.jpg", profile.PictureURL)
+			return nil
+		})
+
+	wac := mock.NewMockConn(c)
+	wac.EXPECT().ProfilePictureURL("375000000001@s.whatsapp.net").Return("https://pps.whatsapp.net/picture.jpg", nil)
+	wac.EXPECT().Status("375000000001@s.whatsapp.net").Return("Available", nil)
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession("_sid_").
+		Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+
+	provider := service.NewCachedProfileProvider(connections, contactRepo, time.Hour)
+	profile, err := provider.Profile("_sid_", "375000000001@s.whatsapp.net")
+	require.Nil(t, err)
+	assert.Equal(t, "375000000001@s.whatsapp.net", profile.Jid)
+	assert.Equal(t, "https://pps.whatsapp.net/picture.jpg", profile.PictureURL)
+	assert.Equal(t, "Available", profile.Status)
+	assert.False(t, profile.UpdatedAt.IsZero())
+}
+
+func TestCachedProfileProvider_ProfileCached(t *testing.T) {
+	c := gomock.NewController(t)
+	cached := &model.Profile{Jid: "375000000001@s.whatsapp.net", Status: "Busy"}
+	contactRepo := mock.NewMockContact(c)
+	contactRepo.EXPECT().Profile("_sid_", "375000000001@s.whatsapp.net").Return(cached, nil)
+
+	provider := service.NewCachedProfileProvider(mock.NewMockConnections(c), contactRepo, time.Hour)
+	profile, err := provider.Profile("_sid_", "375000000001@s.whatsapp.net")
+	require.Nil(t, err)
+	assert.Equal(t, cached, profile)
+}
+
+func TestCachedProfileProvider_ProfileError(t *testing.T) {
+	c := gomock.NewController(t)
+	contactRepo := mock.NewMockContact(c)
+	contactRepo.EXPECT().Profile("_sid_", "375000000001@s.whatsapp.net").Return(nil, nil)
+	wac := mock.NewMockConn(c)
+	wac.EXPECT().ProfilePictureURL("375000000001@s.whatsapp.net").Return("", errors.New("timed out"))
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession("_sid_").
+		Return(service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil)
+
+	provider := service.NewCachedProfileProvider(connections, contactRepo, time.Hour)
+	profile, err := provider.Profile("_sid_", "375000000001@s.whatsapp.net")
+	assert.NotNil(t, err)
+	assert.Nil(t, profile)
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	httpInfra "github.com/r-erema/wapi/internal/infrastructure/http"
+	"github.com/r-erema/wapi/internal/infrastructure/whatsapp"
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+
+	waProto "github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/getsentry/sentry-go"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// queuePollTimeout is a max time of waiting for queued message, due retries are requeued between polls.
+	queuePollTimeout = time.Second
+	maxRetryDelay    = time.Minute
+)
+
+// Enqueuer queues messages to be sent asynchronously.
+type Enqueuer interface {
+	// Enqueue queues message to be sent by session.
+	Enqueue(sessionID string, message *waProto.WebMessageInfo) (*model.QueuedMessage, error)
+}
+
+// MessageQueue sends queued messages by workers started per session, so messages of session keep their order.
+// Failed sending is retried with exponential backoff: message is put aside until its retry time and requeued,
+// so it doesn't hold up following messages. Webhook is notified when all attempts failed.
+// Sending rate limited by connection and sending while session isn't connected are waited without spending attempts.
+type MessageQueue struct {
+	queueRepo             repository.Queue
+	connectionsSupervisor Connections
+	client                httpInfra.Client
+	webhookURL            string
+	maxAttempts           int
+	retryDelay            time.Duration
+	workers               map[string]bool
+	mu                    sync.Mutex
+}
+
+// NewMessageQueue creates MessageQueue.
+func NewMessageQueue(
+	queueRepo repository.Queue,
+	connectionsSupervisor Connections,
+	client httpInfra.Client,
+	webhookURL string,
+	maxAttempts int,
+	retryDelay time.Duration,
+) *MessageQueue {
+	return &MessageQueue{
+		queueRepo:             queueRepo,
+		connectionsSupervisor: connectionsSupervisor,
+		client:                client,
+		webhookURL:            webhookURL,
+		maxAttempts:           maxAttempts,
+		retryDelay:            retryDelay,
+		workers:               make(map[string]bool),
+	}
+}
+
+// Run restores queues interrupted by previous shutdown and starts their workers.
+func (q *MessageQueue) Run() error {
+	sessionIDs, err := q.queueRepo.RestoreQueues()
+	if err != nil {
+		return errors.Wrap(err, "can't restore queues")
+	}
+	for _, sessionID := range sessionIDs {
+		q.startWorker(sessionID)
+	}
+	return nil
+}
+
+// Enqueue queues message to be sent by session.
+func (q *MessageQueue) Enqueue(sessionID string, message *waProto.WebMessageInfo) (*model.QueuedMessage, error) {
+	content, err := proto.Marshal(message)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't marshal message")
+	}
+	now := time.Now()
+	msg := &model.QueuedMessage{
+		ID:        message.GetKey().GetId(),
+		SessionID: sessionID,
+		ChatID:    message.GetKey().GetRemoteJid(),
+		Status:    model.QueuedStatus,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Message:   content,
+	}
+	if err := q.queueRepo.Enqueue(msg); err != nil {
+		return nil, errors.Wrap(err, "can't enqueue message")
+	}
+	q.startWorker(sessionID)
+	return msg, nil
+}
+
+func (q *MessageQueue) startWorker(sessionID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.workers[sessionID] {
+		return
+	}
+	q.workers[sessionID] = true
+	go q.work(sessionID)
+}
+
+func (q *MessageQueue) work(sessionID string) {
+	for {
+		if err := q.queueRepo.RequeueDue(sessionID, time.Now()); err != nil {
+			log.Printf("can't requeue retried messages of session `%s`: %v\n", sessionID, err)
+		}
+		msg, err := q.queueRepo.Next(sessionID, queuePollTimeout)
+		if err != nil {
+			log.Printf("can't get next message of queue of session `%s`: %v\n", sessionID, err)
+			time.Sleep(q.retryDelay)
+			continue
+		}
+		if msg != nil {
+			q.process(msg)
+		}
+	}
+}
+
+func (q *MessageQueue) process(msg *model.QueuedMessage) {
+	for {
+		// Status is re-read before each attempt, so message cancelled while it was waiting isn't sent.
+		if q.isCancelled(msg) {
+			q.done(msg)
+			return
+		}
+		sessConnDTO, err := q.connectionsSupervisor.AuthenticatedConnectionForSession(msg.SessionID)
+		if err != nil {
+			time.Sleep(q.retryDelay)
+			continue
+		}
+		err = q.send(sessConnDTO.Wac(), msg)
+		if limitErr, ok := errors.Cause(err).(*RateLimitError); ok {
+			time.Sleep(limitErr.RetryAfter)
+			continue
+		}
+		msg.Attempts++
+		msg.UpdatedAt = time.Now()
+		msg.RetryAt = nil
+		switch {
+		case err == nil:
+			msg.Status = model.SentStatus
+			msg.LastError = ""
+			log.Printf("queued message `%s` sent to %s by session %s\n", msg.ID, msg.ChatID, msg.SessionID)
+		case msg.Attempts >= q.maxAttempts:
+			msg.Status = model.FailedStatus
+			msg.LastError = err.Error()
+			log.Printf("queued message `%s` sending failed: %v\n", msg.ID, err)
+		default:
+			msg.Status = model.RetryingStatus
+			msg.LastError = err.Error()
+			retryAt := msg.UpdatedAt.Add(q.backoff(msg.Attempts))
+			msg.RetryAt = &retryAt
+			if err := q.queueRepo.Retry(msg); err != nil {
+				log.Printf("can't retry queued message `%s`: %v\n", msg.ID, err)
+			}
+			return
+		}
+
+		if err := q.queueRepo.SaveQueuedMessage(msg); err != nil {
+			log.Printf("can't save queued message `%s`: %v\n", msg.ID, err)
+		}
+		if msg.Status == model.FailedStatus {
+			q.notifyFailure(msg)
+		}
+		q.done(msg)
+		return
+	}
+}
+
+// isCancelled checks whether stored message is cancelled, message is considered not cancelled if it can't be read.
+func (q *MessageQueue) isCancelled(msg *model.QueuedMessage) bool {
+	stored, err := q.queueRepo.QueuedMessage(msg.ID)
+	if err != nil {
+		log.Printf("can't read status of queued message `%s`: %v\n", msg.ID, err)
+		return false
+	}
+	if stored != nil && stored.Status == model.CancelledStatus {
+		msg.Status = model.CancelledStatus
+		return true
+	}
+	return false
+}
+
+func (q *MessageQueue) done(msg *model.QueuedMessage) {
+	if err := q.queueRepo.Done(msg); err != nil {
+		log.Printf("can't remove message `%s` from queue: %v\n", msg.ID, err)
+	}
+}
+
+func (q *MessageQueue) send(wac whatsapp.Conn, msg *model.QueuedMessage) error {
+	message := &waProto.WebMessageInfo{}
+	if err := proto.Unmarshal(msg.Message, message); err != nil {
+		return errors.Wrap(err, "can't unmarshal message")
+	}
+	if _, err := wac.Send(message); err != nil {
+		return errors.Wrap(err, "sending message error")
+	}
+	return nil
+}
+
+// backoff provides delay before next attempt, it's doubled after each failed attempt.
+func (q *MessageQueue) backoff(attempts int) time.Duration {
+	delay := q.retryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+func (q *MessageQueue) notifyFailure(msg *model.QueuedMessage) {
+	requestBody, err := json.Marshal(MessageFailedPayload{
+		Type:      MessageFailedPayloadType,
+		ID:        msg.ID,
+		ChatID:    msg.ChatID,
+		SessionID: msg.SessionID,
+		Attempts:  msg.Attempts,
+		Error:     msg.LastError,
+	})
+	if err != nil {
+		log.Println("error msg marshaling", err)
+		return
+	}
+
+	response, err := q.client.Post(q.webhookURL+msg.SessionID, "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		sentry.CaptureException(errors.Wrapf(err, "can't notify webhook about failed message `%s`", msg.ID))
+		log.Println("error happened getting the response", err)
+		return
+	}
+	if err = response.Body.Close(); err != nil {
+		log.Println("error closing the response body", err)
+	}
+}
@@ -0,0 +1,232 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	waProto "github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageQueue(t *testing.T) {
+	tests := []struct {
+		name             string
+		connectionErrors int
+		cancelOnWaiting  bool
+		sendErrors       []error
+		expectStatus     string
+		expectAttempts   int
+		expectRetries    int
+	}{
+		{
+			name:           "Sent at first attempt",
+			sendErrors:     []error{nil},
+			expectStatus:   model.SentStatus,
+			expectAttempts: 1,
+		},
+		{
+			name:           "Sent after retry",
+			sendErrors:     []error{errors.New("device is offline"), nil},
+			expectStatus:   model.SentStatus,
+			expectAttempts: 2,
+			expectRetries:  1,
+		},
+		{
+			name:           "Paced by rate limit",
+			sendErrors:     []error{&service.RateLimitError{RetryAfter: time.Millisecond}, nil},
+			expectStatus:   model.SentStatus,
+			expectAttempts: 1,
+		},
+		{
+			name:           "All attempts failed",
+			sendErrors:     []error{errors.New("device is offline"), errors.New("device is offline"), errors.New("device is offline")},
+			expectStatus:   model.FailedStatus,
+			expectAttempts: 3,
+			expectRetries:  2,
+		},
+		{
+			name:             "Waited for connection",
+			connectionErrors: 2,
+			sendErrors:       []error{nil},
+			expectStatus:     model.SentStatus,
+			expectAttempts:   1,
+		},
+		{
+			name:             "Cancelled while waiting for connection",
+			connectionErrors: 1,
+			cancelOnWaiting:  true,
+			expectStatus:     model.CancelledStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+
+			wac := mock.NewMockConn(c)
+			attempt := 0
+			wac.EXPECT().Send(gomock.Any()).Times(len(tt.sendErrors)).DoAndReturn(func(msg interface{}) (string, error) {
+				assert.Equal(t, "MSG_ID", msg.(*waProto.WebMessageInfo).GetKey().GetId())
+				err := tt.sendErrors[attempt]
+				attempt++
+				return "MSG_ID", err
+			})
+			var (
+				mu                      sync.Mutex
+				queued, retried, stored *model.QueuedMessage
+			)
+			connectionErrors := tt.connectionErrors
+			connections := mock.NewMockConnections(c)
+			connections.EXPECT().
+				AuthenticatedConnectionForSession("_sid_").
+				DoAndReturn(func(sessionID string) (*service.SessionConnectionDTO, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					if connectionErrors > 0 {
+						connectionErrors--
+						if tt.cancelOnWaiting {
+							stored = &model.QueuedMessage{ID: stored.ID, Status: model.CancelledStatus}
+						}
+						return nil, errors.New("session isn't connected")
+					}
+					return service.NewDTO(wac, &model.WapiSession{}, make(chan string)), nil
+				}).
+				AnyTimes()
+
+			done := make(chan *model.QueuedMessage)
+			queueRepo := mock.NewMockQueue(c)
+			queueRepo.EXPECT().Enqueue(gomock.Any()).DoAndReturn(func(msg *model.QueuedMessage) error {
+				mu.Lock()
+				defer mu.Unlock()
+				queued, stored = msg, &model.QueuedMessage{ID: msg.ID, Status: msg.Status}
+				return nil
+			})
+			queueRepo.EXPECT().QueuedMessage("MSG_ID").DoAndReturn(func(msgID string) (*model.QueuedMessage, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				return stored, nil
+			}).AnyTimes()
+			queueRepo.EXPECT().RequeueDue("_sid_", gomock.Any()).DoAndReturn(func(sessionID string, until time.Time) error {
+				mu.Lock()
+				defer mu.Unlock()
+				if retried != nil && !until.Before(*retried.RetryAt) {
+					queued, retried = retried, nil
+				}
+				return nil
+			}).AnyTimes()
+			queueRepo.EXPECT().Next("_sid_", gomock.Any()).DoAndReturn(func(sessionID string, timeout time.Duration) (*model.QueuedMessage, error) {
+				mu.Lock()
+				next := queued
+				queued = nil
+				mu.Unlock()
+				if next != nil {
+					return next, nil
+				}
+				time.Sleep(time.Millisecond * 10)
+				return nil, nil
+			}).AnyTimes()
+			queueRepo.EXPECT().Retry(gomock.Any()).Times(tt.expectRetries).DoAndReturn(func(msg *model.QueuedMessage) error {
+				mu.Lock()
+				defer mu.Unlock()
+				assert.Equal(t, model.RetryingStatus, msg.Status)
+				require.NotNil(t, msg.RetryAt)
+				retried = msg
+				return nil
+			})
+			if tt.expectStatus != model.CancelledStatus {
+				queueRepo.EXPECT().SaveQueuedMessage(gomock.Any())
+			}
+			queueRepo.EXPECT().Done(gomock.Any()).DoAndReturn(func(msg *model.QueuedMessage) error {
+				done <- msg
+				return nil
+			})
+
+			client := mock.NewMockClient(c)
+			if tt.expectStatus == model.FailedStatus {
+				client.EXPECT().
+					Post("/wh/_sid_", "application/json", gomock.Any()).
+					DoAndReturn(func(url, contentType string, body *bytes.Buffer) (*http.Response, error) {
+						var payload service.MessageFailedPayload
+						require.Nil(t, json.NewDecoder(body).Decode(&payload))
+						assert.Equal(t, service.MessageFailedPayloadType, payload.Type)
+						assert.Equal(t, "MSG_ID", payload.ID)
+						assert.Equal(t, tt.expectAttempts, payload.Attempts)
+						assert.Equal(t, "sending message error: device is offline", payload.Error)
+						return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+					})
+			}
+
+			queue := service.NewMessageQueue(queueRepo, connections, client, "/wh/", 3, time.Millisecond)
+			id, jid, text := "MSG_ID", "+000000000000", "hello"
+			msg, err := queue.Enqueue("_sid_", &waProto.WebMessageInfo{
+				Key:     &waProto.MessageKey{Id: &id, RemoteJid: &jid},
+				Message: &waProto.Message{Conversation: &text},
+			})
+			require.Nil(t, err)
+			assert.Equal(t, model.QueuedStatus, msg.Status)
+			assert.Equal(t, "+000000000000", msg.ChatID)
+
+			select {
+			case processed := <-done:
+				assert.Equal(t, tt.expectStatus, processed.Status)
+				assert.Equal(t, tt.expectAttempts, processed.Attempts)
+			case <-time.After(time.Second):
+				t.Fatal("queued message wasn't processed")
+			}
+		})
+	}
+}
+
+func TestMessageQueue_Run(t *testing.T) {
+	c := gomock.NewController(t)
+	queueRepo := mock.NewMockQueue(c)
+	queueRepo.EXPECT().RestoreQueues().Return(nil, errors.New("redis is down"))
+	queue := service.NewMessageQueue(queueRepo, mock.NewMockConnections(c), mock.NewMockClient(c), "/wh/", 3, time.Millisecond)
+	assert.NotNil(t, queue.Run())
+}
+
+func TestMessageQueue_SkipCancelled(t *testing.T) {
+	c := gomock.NewController(t)
+	cancelled := &model.QueuedMessage{ID: "MSG_ID", SessionID: "_sid_", Status: model.CancelledStatus}
+	done := make(chan *model.QueuedMessage)
+	queueRepo := mock.NewMockQueue(c)
+	queueRepo.EXPECT().RestoreQueues().Return([]string{"_sid_"}, nil)
+	queueRepo.EXPECT().RequeueDue("_sid_", gomock.Any()).AnyTimes()
+	queueRepo.EXPECT().QueuedMessage("MSG_ID").Return(cancelled, nil)
+	queueRepo.EXPECT().Next("_sid_", gomock.Any()).DoAndReturn(func(sessionID string, timeout time.Duration) (*model.QueuedMessage, error) {
+		if next := cancelled; next != nil {
+			cancelled = nil
+			return next, nil
+		}
+		time.Sleep(time.Millisecond * 10)
+		return nil, nil
+	}).AnyTimes()
+	queueRepo.EXPECT().Done(gomock.Any()).DoAndReturn(func(msg *model.QueuedMessage) error {
+		done <- msg
+		return nil
+	})
+
+	queue := service.NewMessageQueue(queueRepo, mock.NewMockConnections(c), mock.NewMockClient(c), "/wh/", 3, time.Millisecond)
+	require.Nil(t, queue.Run())
+
+	select {
+	case processed := <-done:
+		assert.Equal(t, model.CancelledStatus, processed.Status)
+		assert.Equal(t, 0, processed.Attempts)
+	case <-time.After(time.Second):
+		t.Fatal("cancelled message wasn't removed from queue")
+	}
+}
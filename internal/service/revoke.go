@@ -0,0 +1,29 @@
+package service
+
+import (
+	"github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+)
+
+// HandleRawMessage sends notifications about messages revoked by their senders to webhook,
+// they aren't dispatched to any other handler of github.com/Rhymen/go-whatsapp package.
+func (h *Handler) HandleRawMessage(msg *proto.WebMessageInfo) {
+	protocolMsg := msg.GetMessage().GetProtocolMessage()
+	if protocolMsg == nil || protocolMsg.GetType() != proto.ProtocolMessage_REVOKE {
+		return
+	}
+
+	info := &whatsapp.MessageInfo{
+		Id:        msg.GetKey().GetId(),
+		RemoteJid: msg.GetKey().GetRemoteJid(),
+		SenderJid: msg.GetKey().GetParticipant(),
+		FromMe:    msg.GetKey().GetFromMe(),
+		Timestamp: msg.GetMessageTimestamp(),
+		PushName:  msg.GetPushName(),
+		Source:    msg,
+	}
+	h.handleMessage(&MessageRevokedPayload{
+		MessagePayload: newMessagePayload(MessageRevokedPayloadType, info),
+		RevokedID:      protocolMsg.GetKey().GetId(),
+	}, info)
+}
@@ -0,0 +1,71 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleRawMessage(t *testing.T) {
+	revokeType, ephemeralType := proto.ProtocolMessage_REVOKE, proto.ProtocolMessage_EPHEMERAL_SETTING
+	tests := []struct {
+		name          string
+		msgType       *proto.ProtocolMessage_PROTOCOL_MESSAGE_TYPE
+		expectPayload bool
+	}{
+		{name: "Revoke", msgType: &revokeType, expectPayload: true},
+		{name: "Not a revoke", msgType: &ephemeralType},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, _, marshal, _, wh := msgMocks(t)
+
+			var payload service.MessageRevokedPayload
+			c := gomock.NewController(t)
+			client := mock.NewMockClient(c)
+			if tt.expectPayload {
+				client.EXPECT().
+					Post(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(url, contentType string, body io.Reader) (*http.Response, error) {
+						require.Nil(t, json.NewDecoder(body).Decode(&payload))
+						return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+					})
+			}
+
+			id, revokedID, chatID, participant := "MSG_ID", "REVOKED_MSG_ID", "375000000001-1589212345@g.us", "375000000002@s.whatsapp.net"
+			timestamp := uint64(time.Now().Unix() + 1)
+			h := service.NewMsgHandler(conn, sess, msgRepo, mediaDownloader, connSV, sessRepo, client, marshal, 0, wh)
+			h.HandleRawMessage(&proto.WebMessageInfo{
+				Key:              &proto.MessageKey{Id: &id, RemoteJid: &chatID, Participant: &participant},
+				MessageTimestamp: &timestamp,
+				Message: &proto.Message{ProtocolMessage: &proto.ProtocolMessage{
+					Key:  &proto.MessageKey{Id: &revokedID, RemoteJid: &chatID},
+					Type: tt.msgType,
+				}},
+			})
+			c.Finish()
+
+			if tt.expectPayload {
+				assert.Equal(t, service.MessageRevokedPayloadType, payload.Type)
+				assert.Equal(t, id, payload.ID)
+				assert.Equal(t, revokedID, payload.RevokedID)
+				assert.Equal(t, chatID, payload.ChatID)
+				assert.Equal(t, participant, payload.Sender)
+			}
+		})
+	}
+}
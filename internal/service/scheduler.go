@@ -0,0 +1,169 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/repository"
+
+	waProto "github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+const schedulePollInterval = time.Second
+
+// ErrNotScheduled is returned on cancellation of message which is already queued or cancelled.
+var ErrNotScheduled = errors.New("message isn't scheduled")
+
+// Scheduler schedules messages to be sent at specified time.
+type Scheduler interface {
+	// Schedule schedules message to be sent by session at specified time.
+	Schedule(sessionID string, message *waProto.WebMessageInfo, sendAt time.Time) (*model.ScheduledMessage, error)
+	// ScheduledMessage provides scheduled message, nil is returned if message isn't found.
+	ScheduledMessage(msgID string) (*model.ScheduledMessage, error)
+	// Cancel cancels scheduled message, nil is returned if message isn't found,
+	// ErrNotScheduled is returned if message is already queued or cancelled.
+	Cancel(msgID string) (*model.ScheduledMessage, error)
+}
+
+// MessageScheduler queues scheduled messages when their time comes and their session is connected,
+// messages of disconnected sessions wait for connection. Schedule is persisted, so it survives restart.
+type MessageScheduler struct {
+	scheduleRepo          repository.Schedule
+	connectionsSupervisor Connections
+	queue                 Enqueuer
+}
+
+// NewMessageScheduler creates MessageScheduler.
+func NewMessageScheduler(scheduleRepo repository.Schedule, connectionsSupervisor Connections, queue Enqueuer) *MessageScheduler {
+	return &MessageScheduler{
+		scheduleRepo:          scheduleRepo,
+		connectionsSupervisor: connectionsSupervisor,
+		queue:                 queue,
+	}
+}
+
+// Run starts checking schedule for due messages.
+func (s *MessageScheduler) Run() {
+	go func() {
+		for {
+			s.fireDue(time.Now())
+			time.Sleep(schedulePollInterval)
+		}
+	}()
+}
+
+// Schedule schedules message to be sent by session at specified time.
+func (s *MessageScheduler) Schedule(
+	sessionID string,
+	message *waProto.WebMessageInfo,
+	sendAt time.Time,
+) (*model.ScheduledMessage, error) {
+	content, err := proto.Marshal(message)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't marshal message")
+	}
+	now := time.Now()
+	msg := &model.ScheduledMessage{
+		ID:        message.GetKey().GetId(),
+		SessionID: sessionID,
+		ChatID:    message.GetKey().GetRemoteJid(),
+		SendAt:    sendAt,
+		Status:    model.ScheduledStatus,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Message:   content,
+	}
+	if err := s.scheduleRepo.Schedule(msg); err != nil {
+		return nil, errors.Wrap(err, "can't schedule message")
+	}
+	return msg, nil
+}
+
+// ScheduledMessage provides scheduled message, nil is returned if message isn't found.
+func (s *MessageScheduler) ScheduledMessage(msgID string) (*model.ScheduledMessage, error) {
+	msg, err := s.scheduleRepo.ScheduledMessage(msgID)
+	return msg, errors.Wrap(err, "can't read scheduled message")
+}
+
+// Cancel cancels scheduled message, nil is returned if message isn't found,
+// ErrNotScheduled is returned if message is already queued or cancelled.
+func (s *MessageScheduler) Cancel(msgID string) (*model.ScheduledMessage, error) {
+	msg, err := s.scheduleRepo.ScheduledMessage(msgID)
+	if err != nil || msg == nil {
+		return nil, errors.Wrap(err, "can't read scheduled message")
+	}
+	unscheduled, err := s.scheduleRepo.Unschedule(msgID)
+	if err != nil {
+		return nil, err
+	}
+	if !unscheduled {
+		return msg, ErrNotScheduled
+	}
+	msg.Status = model.CancelledStatus
+	msg.UpdatedAt = time.Now()
+	if err := s.scheduleRepo.SaveScheduledMessage(msg); err != nil {
+		return nil, errors.Wrap(err, "can't save cancelled message")
+	}
+	return msg, nil
+}
+
+func (s *MessageScheduler) fireDue(now time.Time) {
+	ids, err := s.scheduleRepo.DueMessageIDs(now)
+	if err != nil {
+		log.Printf("can't read schedule: %v\n", err)
+		return
+	}
+	connected := make(map[string]bool) // Connection of each session is checked once per poll.
+	for _, id := range ids {
+		if err := s.fire(id, connected); err != nil {
+			log.Printf("can't queue scheduled message `%s`: %v\n", id, err)
+		}
+	}
+}
+
+// Queues message if its session is connected, message is taken from schedule before queueing,
+// so it isn't queued twice by concurrent schedulers.
+func (s *MessageScheduler) fire(msgID string, connected map[string]bool) error {
+	msg, err := s.scheduleRepo.ScheduledMessage(msgID)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		_, err = s.scheduleRepo.Unschedule(msgID)
+		return err
+	}
+	isConnected, checked := connected[msg.SessionID]
+	if !checked {
+		_, err = s.connectionsSupervisor.AuthenticatedConnectionForSession(msg.SessionID)
+		isConnected = err == nil
+		connected[msg.SessionID] = isConnected
+	}
+	if !isConnected {
+		return nil
+	}
+
+	unscheduled, err := s.scheduleRepo.Unschedule(msgID)
+	if err != nil || !unscheduled {
+		return err
+	}
+
+	message := &waProto.WebMessageInfo{}
+	if err := proto.Unmarshal(msg.Message, message); err != nil {
+		return errors.Wrap(err, "can't unmarshal message")
+	}
+	timestamp := uint64(time.Now().Unix())
+	message.MessageTimestamp = &timestamp
+	if _, err := s.queue.Enqueue(msg.SessionID, message); err != nil {
+		if scheduleErr := s.scheduleRepo.Schedule(msg); scheduleErr != nil {
+			log.Printf("can't return message `%s` to schedule: %v\n", msgID, scheduleErr)
+		}
+		return err
+	}
+
+	msg.Status = model.QueuedStatus
+	msg.UpdatedAt = time.Now()
+	return s.scheduleRepo.SaveScheduledMessage(msg)
+}
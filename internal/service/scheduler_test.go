@@ -0,0 +1,164 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r-erema/wapi/internal/model"
+	"github.com/r-erema/wapi/internal/service"
+	"github.com/r-erema/wapi/internal/testutil/mock"
+
+	waProto "github.com/Rhymen/go-whatsapp/binary/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageScheduler_Schedule(t *testing.T) {
+	c := gomock.NewController(t)
+	scheduleRepo := mock.NewMockSchedule(c)
+	scheduleRepo.EXPECT().Schedule(gomock.Any())
+
+	sendAt := time.Now().Add(time.Hour)
+	scheduler := service.NewMessageScheduler(scheduleRepo, mock.NewMockConnections(c), mock.NewMockEnqueuer(c))
+	msg, err := scheduler.Schedule("_sid_", bulkMessage("MSG_ID", "+000000000000"), sendAt)
+	require.Nil(t, err)
+	assert.Equal(t, "MSG_ID", msg.ID)
+	assert.Equal(t, "+000000000000", msg.ChatID)
+	assert.Equal(t, model.ScheduledStatus, msg.Status)
+	assert.Equal(t, sendAt, msg.SendAt)
+	assert.NotEmpty(t, msg.Message)
+}
+
+func TestMessageScheduler_Cancel(t *testing.T) {
+	tests := []struct {
+		name         string
+		msg          *model.ScheduledMessage
+		unscheduled  bool
+		expectErr    error
+		expectStatus string
+	}{
+		{
+			name:         "OK",
+			msg:          &model.ScheduledMessage{ID: "MSG_ID", Status: model.ScheduledStatus},
+			unscheduled:  true,
+			expectStatus: model.CancelledStatus,
+		},
+		{
+			name:         "Already queued",
+			msg:          &model.ScheduledMessage{ID: "MSG_ID", Status: model.QueuedStatus},
+			expectErr:    service.ErrNotScheduled,
+			expectStatus: model.QueuedStatus,
+		},
+		{
+			name: "Not found",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			scheduleRepo := mock.NewMockSchedule(c)
+			scheduleRepo.EXPECT().ScheduledMessage("MSG_ID").Return(tt.msg, nil)
+			if tt.msg != nil {
+				scheduleRepo.EXPECT().Unschedule("MSG_ID").Return(tt.unscheduled, nil)
+			}
+			if tt.unscheduled {
+				scheduleRepo.EXPECT().SaveScheduledMessage(tt.msg)
+			}
+
+			scheduler := service.NewMessageScheduler(scheduleRepo, mock.NewMockConnections(c), mock.NewMockEnqueuer(c))
+			msg, err := scheduler.Cancel("MSG_ID")
+			assert.Equal(t, tt.expectErr, err)
+			if tt.msg == nil {
+				assert.Nil(t, msg)
+				return
+			}
+			assert.Equal(t, tt.expectStatus, msg.Status)
+		})
+	}
+}
+
+func TestMessageScheduler_Run(t *testing.T) {
+	c := gomock.NewController(t)
+	content, err := proto.Marshal(bulkMessage("MSG_ID", "+000000000000"))
+	require.Nil(t, err)
+	due := &model.ScheduledMessage{ID: "MSG_ID", SessionID: "_sid_", Status: model.ScheduledStatus, Message: content}
+	waiting := &model.ScheduledMessage{ID: "MSG_OFFLINE", SessionID: "_offline_sid_", Status: model.ScheduledStatus}
+
+	scheduleRepo := mock.NewMockSchedule(c)
+	scheduleRepo.EXPECT().DueMessageIDs(gomock.Any()).Return([]string{"MSG_OFFLINE", "MSG_ID"}, nil)
+	scheduleRepo.EXPECT().DueMessageIDs(gomock.Any()).Return(nil, nil).AnyTimes()
+	scheduleRepo.EXPECT().ScheduledMessage("MSG_OFFLINE").Return(waiting, nil)
+	scheduleRepo.EXPECT().ScheduledMessage("MSG_ID").Return(due, nil)
+	scheduleRepo.EXPECT().Unschedule("MSG_ID").Return(true, nil)
+	saved := make(chan *model.ScheduledMessage)
+	scheduleRepo.EXPECT().SaveScheduledMessage(due).DoAndReturn(func(msg *model.ScheduledMessage) error {
+		saved <- msg
+		return nil
+	})
+
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession("_offline_sid_").
+		Return(nil, &service.NotFoundError{SessionID: "_offline_sid_"})
+	connections.EXPECT().
+		AuthenticatedConnectionForSession("_sid_").
+		Return(service.NewDTO(mock.NewMockConn(c), &model.WapiSession{}, make(chan string)), nil)
+
+	queue := mock.NewMockEnqueuer(c)
+	queue.EXPECT().
+		Enqueue("_sid_", gomock.Any()).
+		DoAndReturn(func(sessionID string, message *waProto.WebMessageInfo) (*model.QueuedMessage, error) {
+			assert.Equal(t, "MSG_ID", message.GetKey().GetId())
+			assert.NotZero(t, message.GetMessageTimestamp())
+			return &model.QueuedMessage{ID: "MSG_ID", Status: model.QueuedStatus}, nil
+		})
+
+	service.NewMessageScheduler(scheduleRepo, connections, queue).Run()
+
+	select {
+	case msg := <-saved:
+		assert.Equal(t, model.QueuedStatus, msg.Status)
+		assert.Equal(t, model.ScheduledStatus, waiting.Status)
+	case <-time.After(time.Second):
+		t.Fatal("scheduled message wasn't queued")
+	}
+}
+
+func TestMessageScheduler_RunEnqueueError(t *testing.T) {
+	c := gomock.NewController(t)
+	content, err := proto.Marshal(bulkMessage("MSG_ID", "+000000000000"))
+	require.Nil(t, err)
+	due := &model.ScheduledMessage{ID: "MSG_ID", SessionID: "_sid_", Status: model.ScheduledStatus, Message: content}
+
+	scheduleRepo := mock.NewMockSchedule(c)
+	scheduleRepo.EXPECT().DueMessageIDs(gomock.Any()).Return([]string{"MSG_ID"}, nil)
+	scheduleRepo.EXPECT().DueMessageIDs(gomock.Any()).Return(nil, nil).AnyTimes()
+	scheduleRepo.EXPECT().ScheduledMessage("MSG_ID").Return(due, nil)
+	scheduleRepo.EXPECT().Unschedule("MSG_ID").Return(true, nil)
+	rescheduled := make(chan *model.ScheduledMessage)
+	scheduleRepo.EXPECT().Schedule(due).DoAndReturn(func(msg *model.ScheduledMessage) error {
+		rescheduled <- msg
+		return nil
+	})
+
+	connections := mock.NewMockConnections(c)
+	connections.EXPECT().
+		AuthenticatedConnectionForSession("_sid_").
+		Return(service.NewDTO(mock.NewMockConn(c), &model.WapiSession{}, make(chan string)), nil)
+	queue := mock.NewMockEnqueuer(c)
+	queue.EXPECT().Enqueue("_sid_", gomock.Any()).Return(nil, errors.New("redis is down"))
+
+	service.NewMessageScheduler(scheduleRepo, connections, queue).Run()
+
+	select {
+	case msg := <-rescheduled:
+		assert.Equal(t, model.ScheduledStatus, msg.Status)
+	case <-time.After(time.Second):
+		t.Fatal("scheduled message wasn't returned to schedule")
+	}
+}
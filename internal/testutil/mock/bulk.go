@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/bulk.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	proto "github.com/Rhymen/go-whatsapp/binary/proto"
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/r-erema/wapi/internal/model"
+	reflect "reflect"
+)
+
+// MockBulkSender is a mock of BulkSender interface
+type MockBulkSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockBulkSenderMockRecorder
+}
+
+// MockBulkSenderMockRecorder is the mock recorder for MockBulkSender
+type MockBulkSenderMockRecorder struct {
+	mock *MockBulkSender
+}
+
+// NewMockBulkSender creates a new mock instance
+func NewMockBulkSender(ctrl *gomock.Controller) *MockBulkSender {
+	mock := &MockBulkSender{ctrl: ctrl}
+	mock.recorder = &MockBulkSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBulkSender) EXPECT() *MockBulkSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method
+func (m *MockBulkSender) Send(sessionID string, messages []*proto.WebMessageInfo) (*model.BulkJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", sessionID, messages)
+	ret0, _ := ret[0].(*model.BulkJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Send indicates an expected call of Send
+func (mr *MockBulkSenderMockRecorder) Send(sessionID, messages interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockBulkSender)(nil).Send), sessionID, messages)
+}
+
+// Job mocks base method
+func (m *MockBulkSender) Job(jobID string) (*model.BulkJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Job", jobID)
+	ret0, _ := ret[0].(*model.BulkJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Job indicates an expected call of Job
+func (mr *MockBulkSenderMockRecorder) Job(jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Job", reflect.TypeOf((*MockBulkSender)(nil).Job), jobID)
+}
+
+// Cancel mocks base method
+func (m *MockBulkSender) Cancel(jobID string) (*model.BulkJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", jobID)
+	ret0, _ := ret[0].(*model.BulkJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel
+func (mr *MockBulkSenderMockRecorder) Cancel(jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockBulkSender)(nil).Cancel), jobID)
+}
@@ -6,7 +6,9 @@ package mock
 
 import (
 	whatsapp "github.com/Rhymen/go-whatsapp"
+	proto "github.com/Rhymen/go-whatsapp/binary/proto"
 	gomock "github.com/golang/mock/gomock"
+	whatsapp0 "github.com/r-erema/wapi/internal/infrastructure/whatsapp"
 	reflect "reflect"
 )
 
@@ -133,3 +135,267 @@ func (mr *MockConnMockRecorder) AddHandler(handler interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHandler", reflect.TypeOf((*MockConn)(nil).AddHandler), handler)
 }
+
+// Exist mocks base method
+func (m *MockConn) Exist(jid string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exist", jid)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exist indicates an expected call of Exist
+func (mr *MockConnMockRecorder) Exist(jid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exist", reflect.TypeOf((*MockConn)(nil).Exist), jid)
+}
+
+// Read mocks base method
+func (m *MockConn) Read(jid, messageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", jid, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Read indicates an expected call of Read
+func (mr *MockConnMockRecorder) Read(jid, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockConn)(nil).Read), jid, messageID)
+}
+
+// Presence mocks base method
+func (m *MockConn) Presence(jid string, presence whatsapp.Presence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Presence", jid, presence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Presence indicates an expected call of Presence
+func (mr *MockConnMockRecorder) Presence(jid, presence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Presence", reflect.TypeOf((*MockConn)(nil).Presence), jid, presence)
+}
+
+// SubscribePresence mocks base method
+func (m *MockConn) SubscribePresence(jid string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribePresence", jid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribePresence indicates an expected call of SubscribePresence
+func (mr *MockConnMockRecorder) SubscribePresence(jid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribePresence", reflect.TypeOf((*MockConn)(nil).SubscribePresence), jid)
+}
+
+// ProfilePictureURL mocks base method
+func (m *MockConn) ProfilePictureURL(jid string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProfilePictureURL", jid)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProfilePictureURL indicates an expected call of ProfilePictureURL
+func (mr *MockConnMockRecorder) ProfilePictureURL(jid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProfilePictureURL", reflect.TypeOf((*MockConn)(nil).ProfilePictureURL), jid)
+}
+
+// Status mocks base method
+func (m *MockConn) Status(jid string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", jid)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status
+func (mr *MockConnMockRecorder) Status(jid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockConn)(nil).Status), jid)
+}
+
+// LoadMessages mocks base method
+func (m *MockConn) LoadMessages(jid string, count int) ([]*proto.WebMessageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadMessages", jid, count)
+	ret0, _ := ret[0].([]*proto.WebMessageInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadMessages indicates an expected call of LoadMessages
+func (mr *MockConnMockRecorder) LoadMessages(jid, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadMessages", reflect.TypeOf((*MockConn)(nil).LoadMessages), jid, count)
+}
+
+// Revoke mocks base method
+func (m *MockConn) Revoke(jid, messageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", jid, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke
+func (mr *MockConnMockRecorder) Revoke(jid, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockConn)(nil).Revoke), jid, messageID)
+}
+
+// CreateGroup mocks base method
+func (m *MockConn) CreateGroup(subject string, participants []string) (string, []whatsapp0.GroupParticipantStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", subject, participants)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].([]whatsapp0.GroupParticipantStatus)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateGroup indicates an expected call of CreateGroup
+func (mr *MockConnMockRecorder) CreateGroup(subject, participants interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockConn)(nil).CreateGroup), subject, participants)
+}
+
+// GroupMetadata mocks base method
+func (m *MockConn) GroupMetadata(jid string) (*whatsapp0.GroupMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GroupMetadata", jid)
+	ret0, _ := ret[0].(*whatsapp0.GroupMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GroupMetadata indicates an expected call of GroupMetadata
+func (mr *MockConnMockRecorder) GroupMetadata(jid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GroupMetadata", reflect.TypeOf((*MockConn)(nil).GroupMetadata), jid)
+}
+
+// AddGroupParticipants mocks base method
+func (m *MockConn) AddGroupParticipants(jid string, participants []string) ([]whatsapp0.GroupParticipantStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddGroupParticipants", jid, participants)
+	ret0, _ := ret[0].([]whatsapp0.GroupParticipantStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddGroupParticipants indicates an expected call of AddGroupParticipants
+func (mr *MockConnMockRecorder) AddGroupParticipants(jid, participants interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGroupParticipants", reflect.TypeOf((*MockConn)(nil).AddGroupParticipants), jid, participants)
+}
+
+// RemoveGroupParticipants mocks base method
+func (m *MockConn) RemoveGroupParticipants(jid string, participants []string) ([]whatsapp0.GroupParticipantStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveGroupParticipants", jid, participants)
+	ret0, _ := ret[0].([]whatsapp0.GroupParticipantStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveGroupParticipants indicates an expected call of RemoveGroupParticipants
+func (mr *MockConnMockRecorder) RemoveGroupParticipants(jid, participants interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveGroupParticipants", reflect.TypeOf((*MockConn)(nil).RemoveGroupParticipants), jid, participants)
+}
+
+// PromoteGroupParticipants mocks base method
+func (m *MockConn) PromoteGroupParticipants(jid string, participants []string) ([]whatsapp0.GroupParticipantStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromoteGroupParticipants", jid, participants)
+	ret0, _ := ret[0].([]whatsapp0.GroupParticipantStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PromoteGroupParticipants indicates an expected call of PromoteGroupParticipants
+func (mr *MockConnMockRecorder) PromoteGroupParticipants(jid, participants interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteGroupParticipants", reflect.TypeOf((*MockConn)(nil).PromoteGroupParticipants), jid, participants)
+}
+
+// DemoteGroupParticipants mocks base method
+func (m *MockConn) DemoteGroupParticipants(jid string, participants []string) ([]whatsapp0.GroupParticipantStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DemoteGroupParticipants", jid, participants)
+	ret0, _ := ret[0].([]whatsapp0.GroupParticipantStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DemoteGroupParticipants indicates an expected call of DemoteGroupParticipants
+func (mr *MockConnMockRecorder) DemoteGroupParticipants(jid, participants interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DemoteGroupParticipants", reflect.TypeOf((*MockConn)(nil).DemoteGroupParticipants), jid, participants)
+}
+
+// SetGroupSubject mocks base method
+func (m *MockConn) SetGroupSubject(jid, subject string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGroupSubject", jid, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetGroupSubject indicates an expected call of SetGroupSubject
+func (mr *MockConnMockRecorder) SetGroupSubject(jid, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGroupSubject", reflect.TypeOf((*MockConn)(nil).SetGroupSubject), jid, subject)
+}
+
+// SetGroupDescription mocks base method
+func (m *MockConn) SetGroupDescription(jid, description string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGroupDescription", jid, description)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetGroupDescription indicates an expected call of SetGroupDescription
+func (mr *MockConnMockRecorder) SetGroupDescription(jid, description interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGroupDescription", reflect.TypeOf((*MockConn)(nil).SetGroupDescription), jid, description)
+}
+
+// LeaveGroup mocks base method
+func (m *MockConn) LeaveGroup(jid string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveGroup", jid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LeaveGroup indicates an expected call of LeaveGroup
+func (mr *MockConnMockRecorder) LeaveGroup(jid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveGroup", reflect.TypeOf((*MockConn)(nil).LeaveGroup), jid)
+}
+
+// GroupInviteLink mocks base method
+func (m *MockConn) GroupInviteLink(jid string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GroupInviteLink", jid)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GroupInviteLink indicates an expected call of GroupInviteLink
+func (mr *MockConnMockRecorder) GroupInviteLink(jid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GroupInviteLink", reflect.TypeOf((*MockConn)(nil).GroupInviteLink), jid)
+}
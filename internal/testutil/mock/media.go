@@ -0,0 +1,12 @@
+package mock
+
+// MediaMessage is mock of message containing media content.
+type MediaMessage struct {
+	Content []byte
+	Err     error
+}
+
+// Download returns predefined content or error.
+func (m *MediaMessage) Download() ([]byte, error) {
+	return m.Content, m.Err
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/number.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/r-erema/wapi/internal/model"
+	reflect "reflect"
+)
+
+// MockNumberChecker is a mock of NumberChecker interface
+type MockNumberChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockNumberCheckerMockRecorder
+}
+
+// MockNumberCheckerMockRecorder is the mock recorder for MockNumberChecker
+type MockNumberCheckerMockRecorder struct {
+	mock *MockNumberChecker
+}
+
+// NewMockNumberChecker creates a new mock instance
+func NewMockNumberChecker(ctrl *gomock.Controller) *MockNumberChecker {
+	mock := &MockNumberChecker{ctrl: ctrl}
+	mock.recorder = &MockNumberCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockNumberChecker) EXPECT() *MockNumberCheckerMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method
+func (m *MockNumberChecker) Check(sessionID string, phones []string) ([]model.NumberCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", sessionID, phones)
+	ret0, _ := ret[0].([]model.NumberCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check
+func (mr *MockNumberCheckerMockRecorder) Check(sessionID, phones interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockNumberChecker)(nil).Check), sessionID, phones)
+}
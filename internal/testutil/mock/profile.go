@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/profile.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/r-erema/wapi/internal/model"
+	reflect "reflect"
+)
+
+// MockProfileProvider is a mock of ProfileProvider interface
+type MockProfileProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileProviderMockRecorder
+}
+
+// MockProfileProviderMockRecorder is the mock recorder for MockProfileProvider
+type MockProfileProviderMockRecorder struct {
+	mock *MockProfileProvider
+}
+
+// NewMockProfileProvider creates a new mock instance
+func NewMockProfileProvider(ctrl *gomock.Controller) *MockProfileProvider {
+	mock := &MockProfileProvider{ctrl: ctrl}
+	mock.recorder = &MockProfileProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockProfileProvider) EXPECT() *MockProfileProviderMockRecorder {
+	return m.recorder
+}
+
+// Profile mocks base method
+func (m *MockProfileProvider) Profile(sessionID, jid string) (*model.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Profile", sessionID, jid)
+	ret0, _ := ret[0].(*model.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Profile indicates an expected call of Profile
+func (mr *MockProfileProviderMockRecorder) Profile(sessionID, jid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Profile", reflect.TypeOf((*MockProfileProvider)(nil).Profile), sessionID, jid)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/queue.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	proto "github.com/Rhymen/go-whatsapp/binary/proto"
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/r-erema/wapi/internal/model"
+	reflect "reflect"
+)
+
+// MockEnqueuer is a mock of Enqueuer interface
+type MockEnqueuer struct {
+	ctrl     *gomock.Controller
+	recorder *MockEnqueuerMockRecorder
+}
+
+// MockEnqueuerMockRecorder is the mock recorder for MockEnqueuer
+type MockEnqueuerMockRecorder struct {
+	mock *MockEnqueuer
+}
+
+// NewMockEnqueuer creates a new mock instance
+func NewMockEnqueuer(ctrl *gomock.Controller) *MockEnqueuer {
+	mock := &MockEnqueuer{ctrl: ctrl}
+	mock.recorder = &MockEnqueuerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockEnqueuer) EXPECT() *MockEnqueuerMockRecorder {
+	return m.recorder
+}
+
+// Enqueue mocks base method
+func (m *MockEnqueuer) Enqueue(sessionID string, message *proto.WebMessageInfo) (*model.QueuedMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", sessionID, message)
+	ret0, _ := ret[0].(*model.QueuedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enqueue indicates an expected call of Enqueue
+func (mr *MockEnqueuerMockRecorder) Enqueue(sessionID, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockEnqueuer)(nil).Enqueue), sessionID, message)
+}
@@ -63,6 +63,123 @@ func (mr *MockMessageMockRecorder) MessageTime(msgID interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessageTime", reflect.TypeOf((*MockMessage)(nil).MessageTime), msgID)
 }
 
+// LockIdempotencyKey mocks base method
+func (m *MockMessage) LockIdempotencyKey(key string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockIdempotencyKey", key, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockIdempotencyKey indicates an expected call of LockIdempotencyKey
+func (mr *MockMessageMockRecorder) LockIdempotencyKey(key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockIdempotencyKey", reflect.TypeOf((*MockMessage)(nil).LockIdempotencyKey), key, ttl)
+}
+
+// UnlockIdempotencyKey mocks base method
+func (m *MockMessage) UnlockIdempotencyKey(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockIdempotencyKey", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockIdempotencyKey indicates an expected call of UnlockIdempotencyKey
+func (mr *MockMessageMockRecorder) UnlockIdempotencyKey(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockIdempotencyKey", reflect.TypeOf((*MockMessage)(nil).UnlockIdempotencyKey), key)
+}
+
+// SaveIdempotentResponse mocks base method
+func (m *MockMessage) SaveIdempotentResponse(key string, response *model.IdempotentResponse, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotentResponse", key, response, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotentResponse indicates an expected call of SaveIdempotentResponse
+func (mr *MockMessageMockRecorder) SaveIdempotentResponse(key, response, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotentResponse", reflect.TypeOf((*MockMessage)(nil).SaveIdempotentResponse), key, response, ttl)
+}
+
+// IdempotentResponse mocks base method
+func (m *MockMessage) IdempotentResponse(key string) (*model.IdempotentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IdempotentResponse", key)
+	ret0, _ := ret[0].(*model.IdempotentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IdempotentResponse indicates an expected call of IdempotentResponse
+func (mr *MockMessageMockRecorder) IdempotentResponse(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IdempotentResponse", reflect.TypeOf((*MockMessage)(nil).IdempotentResponse), key)
+}
+
+// UpdateMessageStatus mocks base method
+func (m *MockMessage) UpdateMessageStatus(sessionID, msgID string, update func(*model.MessageStatus) *model.MessageStatus) (*model.MessageStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessageStatus", sessionID, msgID, update)
+	ret0, _ := ret[0].(*model.MessageStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMessageStatus indicates an expected call of UpdateMessageStatus
+func (mr *MockMessageMockRecorder) UpdateMessageStatus(sessionID, msgID, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessageStatus", reflect.TypeOf((*MockMessage)(nil).UpdateMessageStatus), sessionID, msgID, update)
+}
+
+// MessageStatus mocks base method
+func (m *MockMessage) MessageStatus(sessionID, msgID string) (*model.MessageStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MessageStatus", sessionID, msgID)
+	ret0, _ := ret[0].(*model.MessageStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MessageStatus indicates an expected call of MessageStatus
+func (mr *MockMessageMockRecorder) MessageStatus(sessionID, msgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessageStatus", reflect.TypeOf((*MockMessage)(nil).MessageStatus), sessionID, msgID)
+}
+
+// SaveLastMessageTimestamp mocks base method
+func (m *MockMessage) SaveLastMessageTimestamp(sessionID string, timestamp uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLastMessageTimestamp", sessionID, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLastMessageTimestamp indicates an expected call of SaveLastMessageTimestamp
+func (mr *MockMessageMockRecorder) SaveLastMessageTimestamp(sessionID, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLastMessageTimestamp", reflect.TypeOf((*MockMessage)(nil).SaveLastMessageTimestamp), sessionID, timestamp)
+}
+
+// LastMessageTimestamp mocks base method
+func (m *MockMessage) LastMessageTimestamp(sessionID string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastMessageTimestamp", sessionID)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastMessageTimestamp indicates an expected call of LastMessageTimestamp
+func (mr *MockMessageMockRecorder) LastMessageTimestamp(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastMessageTimestamp", reflect.TypeOf((*MockMessage)(nil).LastMessageTimestamp), sessionID)
+}
+
 // MockSession is a mock of Session interface
 type MockSession struct {
 	ctrl     *gomock.Controller
@@ -143,3 +260,547 @@ func (mr *MockSessionMockRecorder) RemoveSession(sessionID interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSession", reflect.TypeOf((*MockSession)(nil).RemoveSession), sessionID)
 }
+
+// MockMedia is a mock of Media interface
+type MockMedia struct {
+	ctrl     *gomock.Controller
+	recorder *MockMediaMockRecorder
+}
+
+// MockMediaMockRecorder is the mock recorder for MockMedia
+type MockMediaMockRecorder struct {
+	mock *MockMedia
+}
+
+// NewMockMedia creates a new mock instance
+func NewMockMedia(ctrl *gomock.Controller) *MockMedia {
+	mock := &MockMedia{ctrl: ctrl}
+	mock.recorder = &MockMediaMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMedia) EXPECT() *MockMediaMockRecorder {
+	return m.recorder
+}
+
+// SaveMedia mocks base method
+func (m *MockMedia) SaveMedia(fileName string, content []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMedia", fileName, content)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveMedia indicates an expected call of SaveMedia
+func (mr *MockMediaMockRecorder) SaveMedia(fileName, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMedia", reflect.TypeOf((*MockMedia)(nil).SaveMedia), fileName, content)
+}
+
+// MockQueue is a mock of Queue interface
+type MockQueue struct {
+	ctrl     *gomock.Controller
+	recorder *MockQueueMockRecorder
+}
+
+// MockQueueMockRecorder is the mock recorder for MockQueue
+type MockQueueMockRecorder struct {
+	mock *MockQueue
+}
+
+// NewMockQueue creates a new mock instance
+func NewMockQueue(ctrl *gomock.Controller) *MockQueue {
+	mock := &MockQueue{ctrl: ctrl}
+	mock.recorder = &MockQueueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockQueue) EXPECT() *MockQueueMockRecorder {
+	return m.recorder
+}
+
+// Enqueue mocks base method
+func (m *MockQueue) Enqueue(msg *model.QueuedMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue
+func (mr *MockQueueMockRecorder) Enqueue(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockQueue)(nil).Enqueue), msg)
+}
+
+// Next mocks base method
+func (m *MockQueue) Next(sessionID string, timeout time.Duration) (*model.QueuedMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next", sessionID, timeout)
+	ret0, _ := ret[0].(*model.QueuedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Next indicates an expected call of Next
+func (mr *MockQueueMockRecorder) Next(sessionID, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockQueue)(nil).Next), sessionID, timeout)
+}
+
+// Done mocks base method
+func (m *MockQueue) Done(msg *model.QueuedMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Done", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Done indicates an expected call of Done
+func (mr *MockQueueMockRecorder) Done(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Done", reflect.TypeOf((*MockQueue)(nil).Done), msg)
+}
+
+// Retry mocks base method
+func (m *MockQueue) Retry(msg *model.QueuedMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Retry indicates an expected call of Retry
+func (mr *MockQueueMockRecorder) Retry(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockQueue)(nil).Retry), msg)
+}
+
+// RequeueDue mocks base method
+func (m *MockQueue) RequeueDue(sessionID string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueDue", sessionID, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueDue indicates an expected call of RequeueDue
+func (mr *MockQueueMockRecorder) RequeueDue(sessionID, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueDue", reflect.TypeOf((*MockQueue)(nil).RequeueDue), sessionID, until)
+}
+
+// SaveQueuedMessage mocks base method
+func (m *MockQueue) SaveQueuedMessage(msg *model.QueuedMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveQueuedMessage", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveQueuedMessage indicates an expected call of SaveQueuedMessage
+func (mr *MockQueueMockRecorder) SaveQueuedMessage(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveQueuedMessage", reflect.TypeOf((*MockQueue)(nil).SaveQueuedMessage), msg)
+}
+
+// QueuedMessage mocks base method
+func (m *MockQueue) QueuedMessage(msgID string) (*model.QueuedMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueuedMessage", msgID)
+	ret0, _ := ret[0].(*model.QueuedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueuedMessage indicates an expected call of QueuedMessage
+func (mr *MockQueueMockRecorder) QueuedMessage(msgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueuedMessage", reflect.TypeOf((*MockQueue)(nil).QueuedMessage), msgID)
+}
+
+// RestoreQueues mocks base method
+func (m *MockQueue) RestoreQueues() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreQueues")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreQueues indicates an expected call of RestoreQueues
+func (mr *MockQueueMockRecorder) RestoreQueues() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreQueues", reflect.TypeOf((*MockQueue)(nil).RestoreQueues))
+}
+
+// MockBulkJob is a mock of BulkJob interface
+type MockBulkJob struct {
+	ctrl     *gomock.Controller
+	recorder *MockBulkJobMockRecorder
+}
+
+// MockBulkJobMockRecorder is the mock recorder for MockBulkJob
+type MockBulkJobMockRecorder struct {
+	mock *MockBulkJob
+}
+
+// NewMockBulkJob creates a new mock instance
+func NewMockBulkJob(ctrl *gomock.Controller) *MockBulkJob {
+	mock := &MockBulkJob{ctrl: ctrl}
+	mock.recorder = &MockBulkJobMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBulkJob) EXPECT() *MockBulkJobMockRecorder {
+	return m.recorder
+}
+
+// SaveBulkJob mocks base method
+func (m *MockBulkJob) SaveBulkJob(job *model.BulkJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBulkJob", job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBulkJob indicates an expected call of SaveBulkJob
+func (mr *MockBulkJobMockRecorder) SaveBulkJob(job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBulkJob", reflect.TypeOf((*MockBulkJob)(nil).SaveBulkJob), job)
+}
+
+// BulkJob mocks base method
+func (m *MockBulkJob) BulkJob(jobID string) (*model.BulkJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkJob", jobID)
+	ret0, _ := ret[0].(*model.BulkJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkJob indicates an expected call of BulkJob
+func (mr *MockBulkJobMockRecorder) BulkJob(jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkJob", reflect.TypeOf((*MockBulkJob)(nil).BulkJob), jobID)
+}
+
+// MockSchedule is a mock of Schedule interface
+type MockSchedule struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleMockRecorder
+}
+
+// MockScheduleMockRecorder is the mock recorder for MockSchedule
+type MockScheduleMockRecorder struct {
+	mock *MockSchedule
+}
+
+// NewMockSchedule creates a new mock instance
+func NewMockSchedule(ctrl *gomock.Controller) *MockSchedule {
+	mock := &MockSchedule{ctrl: ctrl}
+	mock.recorder = &MockScheduleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSchedule) EXPECT() *MockScheduleMockRecorder {
+	return m.recorder
+}
+
+// Schedule mocks base method
+func (m *MockSchedule) Schedule(msg *model.ScheduledMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Schedule indicates an expected call of Schedule
+func (mr *MockScheduleMockRecorder) Schedule(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockSchedule)(nil).Schedule), msg)
+}
+
+// Unschedule mocks base method
+func (m *MockSchedule) Unschedule(msgID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unschedule", msgID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unschedule indicates an expected call of Unschedule
+func (mr *MockScheduleMockRecorder) Unschedule(msgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unschedule", reflect.TypeOf((*MockSchedule)(nil).Unschedule), msgID)
+}
+
+// DueMessageIDs mocks base method
+func (m *MockSchedule) DueMessageIDs(until time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DueMessageIDs", until)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DueMessageIDs indicates an expected call of DueMessageIDs
+func (mr *MockScheduleMockRecorder) DueMessageIDs(until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DueMessageIDs", reflect.TypeOf((*MockSchedule)(nil).DueMessageIDs), until)
+}
+
+// SaveScheduledMessage mocks base method
+func (m *MockSchedule) SaveScheduledMessage(msg *model.ScheduledMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveScheduledMessage", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveScheduledMessage indicates an expected call of SaveScheduledMessage
+func (mr *MockScheduleMockRecorder) SaveScheduledMessage(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveScheduledMessage", reflect.TypeOf((*MockSchedule)(nil).SaveScheduledMessage), msg)
+}
+
+// ScheduledMessage mocks base method
+func (m *MockSchedule) ScheduledMessage(msgID string) (*model.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduledMessage", msgID)
+	ret0, _ := ret[0].(*model.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduledMessage indicates an expected call of ScheduledMessage
+func (mr *MockScheduleMockRecorder) ScheduledMessage(msgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduledMessage", reflect.TypeOf((*MockSchedule)(nil).ScheduledMessage), msgID)
+}
+
+// MockTemplate is a mock of Template interface
+type MockTemplate struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateMockRecorder
+}
+
+// MockTemplateMockRecorder is the mock recorder for MockTemplate
+type MockTemplateMockRecorder struct {
+	mock *MockTemplate
+}
+
+// NewMockTemplate creates a new mock instance
+func NewMockTemplate(ctrl *gomock.Controller) *MockTemplate {
+	mock := &MockTemplate{ctrl: ctrl}
+	mock.recorder = &MockTemplateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTemplate) EXPECT() *MockTemplateMockRecorder {
+	return m.recorder
+}
+
+// SaveTemplate mocks base method
+func (m *MockTemplate) SaveTemplate(template *model.Template) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTemplate", template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTemplate indicates an expected call of SaveTemplate
+func (mr *MockTemplateMockRecorder) SaveTemplate(template interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTemplate", reflect.TypeOf((*MockTemplate)(nil).SaveTemplate), template)
+}
+
+// Template mocks base method
+func (m *MockTemplate) Template(templateID string) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Template", templateID)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Template indicates an expected call of Template
+func (mr *MockTemplateMockRecorder) Template(templateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Template", reflect.TypeOf((*MockTemplate)(nil).Template), templateID)
+}
+
+// AllTemplates mocks base method
+func (m *MockTemplate) AllTemplates() ([]*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllTemplates")
+	ret0, _ := ret[0].([]*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllTemplates indicates an expected call of AllTemplates
+func (mr *MockTemplateMockRecorder) AllTemplates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllTemplates", reflect.TypeOf((*MockTemplate)(nil).AllTemplates))
+}
+
+// RemoveTemplate mocks base method
+func (m *MockTemplate) RemoveTemplate(templateID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTemplate", templateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTemplate indicates an expected call of RemoveTemplate
+func (mr *MockTemplateMockRecorder) RemoveTemplate(templateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTemplate", reflect.TypeOf((*MockTemplate)(nil).RemoveTemplate), templateID)
+}
+
+// MockContact is a mock of Contact interface
+type MockContact struct {
+	ctrl     *gomock.Controller
+	recorder *MockContactMockRecorder
+}
+
+// MockContactMockRecorder is the mock recorder for MockContact
+type MockContactMockRecorder struct {
+	mock *MockContact
+}
+
+// NewMockContact creates a new mock instance
+func NewMockContact(ctrl *gomock.Controller) *MockContact {
+	mock := &MockContact{ctrl: ctrl}
+	mock.recorder = &MockContactMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockContact) EXPECT() *MockContactMockRecorder {
+	return m.recorder
+}
+
+// SaveNumberExists mocks base method
+func (m *MockContact) SaveNumberExists(jid string, exists bool, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveNumberExists", jid, exists, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveNumberExists indicates an expected call of SaveNumberExists
+func (mr *MockContactMockRecorder) SaveNumberExists(jid, exists, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveNumberExists", reflect.TypeOf((*MockContact)(nil).SaveNumberExists), jid, exists, ttl)
+}
+
+// NumberExists mocks base method
+func (m *MockContact) NumberExists(jid string) (*bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NumberExists", jid)
+	ret0, _ := ret[0].(*bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NumberExists indicates an expected call of NumberExists
+func (mr *MockContactMockRecorder) NumberExists(jid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NumberExists", reflect.TypeOf((*MockContact)(nil).NumberExists), jid)
+}
+
+// SaveContacts mocks base method
+func (m *MockContact) SaveContacts(sessionID string, contacts []model.Contact) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveContacts", sessionID, contacts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveContacts indicates an expected call of SaveContacts
+func (mr *MockContactMockRecorder) SaveContacts(sessionID, contacts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveContacts", reflect.TypeOf((*MockContact)(nil).SaveContacts), sessionID, contacts)
+}
+
+// Contacts mocks base method
+func (m *MockContact) Contacts(sessionID string) ([]model.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Contacts", sessionID)
+	ret0, _ := ret[0].([]model.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Contacts indicates an expected call of Contacts
+func (mr *MockContactMockRecorder) Contacts(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Contacts", reflect.TypeOf((*MockContact)(nil).Contacts), sessionID)
+}
+
+// SaveChats mocks base method
+func (m *MockContact) SaveChats(sessionID string, chats []model.Chat) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveChats", sessionID, chats)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveChats indicates an expected call of SaveChats
+func (mr *MockContactMockRecorder) SaveChats(sessionID, chats interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveChats", reflect.TypeOf((*MockContact)(nil).SaveChats), sessionID, chats)
+}
+
+// Chats mocks base method
+func (m *MockContact) Chats(sessionID string) ([]model.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Chats", sessionID)
+	ret0, _ := ret[0].([]model.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Chats indicates an expected call of Chats
+func (mr *MockContactMockRecorder) Chats(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Chats", reflect.TypeOf((*MockContact)(nil).Chats), sessionID)
+}
+
+// SaveProfile mocks base method
+func (m *MockContact) SaveProfile(sessionID string, profile *model.Profile, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveProfile", sessionID, profile, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveProfile indicates an expected call of SaveProfile
+func (mr *MockContactMockRecorder) SaveProfile(sessionID, profile, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProfile", reflect.TypeOf((*MockContact)(nil).SaveProfile), sessionID, profile, ttl)
+}
+
+// Profile mocks base method
+func (m *MockContact) Profile(sessionID, jid string) (*model.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Profile", sessionID, jid)
+	ret0, _ := ret[0].(*model.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Profile indicates an expected call of Profile
+func (mr *MockContactMockRecorder) Profile(sessionID, jid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Profile", reflect.TypeOf((*MockContact)(nil).Profile), sessionID, jid)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/scheduler.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	proto "github.com/Rhymen/go-whatsapp/binary/proto"
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/r-erema/wapi/internal/model"
+	reflect "reflect"
+	time "time"
+)
+
+// MockScheduler is a mock of Scheduler interface
+type MockScheduler struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulerMockRecorder
+}
+
+// MockSchedulerMockRecorder is the mock recorder for MockScheduler
+type MockSchedulerMockRecorder struct {
+	mock *MockScheduler
+}
+
+// NewMockScheduler creates a new mock instance
+func NewMockScheduler(ctrl *gomock.Controller) *MockScheduler {
+	mock := &MockScheduler{ctrl: ctrl}
+	mock.recorder = &MockSchedulerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockScheduler) EXPECT() *MockSchedulerMockRecorder {
+	return m.recorder
+}
+
+// Schedule mocks base method
+func (m *MockScheduler) Schedule(sessionID string, message *proto.WebMessageInfo, sendAt time.Time) (*model.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", sessionID, message, sendAt)
+	ret0, _ := ret[0].(*model.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Schedule indicates an expected call of Schedule
+func (mr *MockSchedulerMockRecorder) Schedule(sessionID, message, sendAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockScheduler)(nil).Schedule), sessionID, message, sendAt)
+}
+
+// ScheduledMessage mocks base method
+func (m *MockScheduler) ScheduledMessage(msgID string) (*model.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduledMessage", msgID)
+	ret0, _ := ret[0].(*model.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduledMessage indicates an expected call of ScheduledMessage
+func (mr *MockSchedulerMockRecorder) ScheduledMessage(msgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduledMessage", reflect.TypeOf((*MockScheduler)(nil).ScheduledMessage), msgID)
+}
+
+// Cancel mocks base method
+func (m *MockScheduler) Cancel(msgID string) (*model.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", msgID)
+	ret0, _ := ret[0].(*model.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel
+func (mr *MockSchedulerMockRecorder) Cancel(msgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockScheduler)(nil).Cancel), msgID)
+}
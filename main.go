@@ -10,8 +10,13 @@ import (
 	httpInternal "github.com/r-erema/wapi/internal/http"
 	osInfra "github.com/r-erema/wapi/internal/infrastructure/os"
 	"github.com/r-erema/wapi/internal/repository"
+	contactRepository "github.com/r-erema/wapi/internal/repository/contact"
+	mediaRepository "github.com/r-erema/wapi/internal/repository/media"
 	messageRepo "github.com/r-erema/wapi/internal/repository/message"
+	queueRepository "github.com/r-erema/wapi/internal/repository/queue"
+	scheduleRepository "github.com/r-erema/wapi/internal/repository/schedule"
 	sessionRepo "github.com/r-erema/wapi/internal/repository/session"
+	templateRepository "github.com/r-erema/wapi/internal/repository/template"
 	"github.com/r-erema/wapi/internal/service"
 
 	_ "github.com/Rhymen/go-whatsapp"
@@ -30,12 +35,66 @@ func main() {
 
 	msgRepo := msgRepo(conf)
 	sessRepo := sessRepo(conf)
+	contactRepo := contactRepo(conf)
+	mediaDownloader := service.NewMediaDownloader(mediaRepo(conf), uint64(conf.MaxMediaSize))
 	connSupervisor := connSupervisor(conf)
 	resolver := qrFileResolver(conf, fs)
 	authorizer := authorizer(conf, sessRepo, connSupervisor, resolver)
-	listener := service.NewWebHook(sessRepo, connSupervisor, authorizer, conf.WebHookURL, msgRepo, &http.Client{}, make(chan os.Signal))
+	listener := service.NewWebHook(
+		sessRepo,
+		connSupervisor,
+		authorizer,
+		conf.WebHookURL,
+		msgRepo,
+		contactRepo,
+		mediaDownloader,
+		&http.Client{},
+		conf.BackfillMessages,
+		make(chan os.Signal),
+	)
+
+	queueRepo := queueRepo(conf)
+	queue := service.NewMessageQueue(
+		queueRepo,
+		connSupervisor,
+		&http.Client{},
+		conf.WebHookURL,
+		conf.QueueMaxAttempts,
+		time.Duration(conf.QueueRetryDelay)*time.Millisecond,
+	)
+	if err = queue.Run(); err != nil {
+		log.Fatalf("run message queue error: %+v", err)
+	}
+
+	bulkSender := service.NewBulkQueue(queue, queueRepo, queueRepo)
+	scheduler := service.NewMessageScheduler(scheduleRepo(conf), connSupervisor, queue)
+	scheduler.Run()
+
+	numberChecker := service.NewCachedNumberChecker(
+		connSupervisor,
+		contactRepo,
+		service.NewJidNormalizer(conf.DefaultCountryCode),
+		time.Duration(conf.NumberCheckTTL)*time.Second,
+	)
 
-	router, err := httpInternal.Router(conf, sessRepo, connSupervisor, authorizer, resolver, listener, fs)
+	router, err := httpInternal.Router(
+		conf,
+		sessRepo,
+		msgRepo,
+		connSupervisor,
+		authorizer,
+		resolver,
+		listener,
+		queueRepo,
+		queue,
+		bulkSender,
+		scheduler,
+		templateRepo(conf),
+		numberChecker,
+		contactRepo,
+		service.NewCachedProfileProvider(connSupervisor, contactRepo, time.Duration(conf.ProfileTTL)*time.Second),
+		fs,
+	)
 	if err != nil {
 		log.Fatalf("init router error: %+v", err)
 	}
@@ -68,6 +127,38 @@ func msgRepo(conf *config.Config) repository.Message {
 	return msgRepo
 }
 
+func queueRepo(conf *config.Config) *queueRepository.RedisRepository {
+	queueRepo, err := queueRepository.NewRedis(conf.RedisHost)
+	if err != nil {
+		log.Fatalf("error of init redis queue repo: %+v\n", err)
+	}
+	return queueRepo
+}
+
+func scheduleRepo(conf *config.Config) repository.Schedule {
+	scheduleRepo, err := scheduleRepository.NewRedis(conf.RedisHost)
+	if err != nil {
+		log.Fatalf("error of init redis schedule repo: %+v\n", err)
+	}
+	return scheduleRepo
+}
+
+func contactRepo(conf *config.Config) repository.Contact {
+	contactRepo, err := contactRepository.NewRedis(conf.RedisHost)
+	if err != nil {
+		log.Fatalf("error of init redis contact repo: %+v\n", err)
+	}
+	return contactRepo
+}
+
+func templateRepo(conf *config.Config) repository.Template {
+	templateRepo, err := templateRepository.NewFileSystem(conf.FileSystemRootPath + "/templates")
+	if err != nil {
+		log.Fatalf("can't create template repository: %+v\n", err)
+	}
+	return templateRepo
+}
+
 func sessRepo(conf *config.Config) repository.Session {
 	sessRepo, err := sessionRepo.NewFileSystem(conf.FileSystemRootPath + "/sessions")
 	if err != nil {
@@ -76,6 +167,18 @@ func sessRepo(conf *config.Config) repository.Session {
 	return sessRepo
 }
 
+func mediaRepo(conf *config.Config) repository.Media {
+	if conf.MediaBaseURL == "" {
+		log.Print("media base url not set, media will be sent to webhook in base64")
+		return nil
+	}
+	mediaRepo, err := mediaRepository.NewFileSystem(conf.FileSystemRootPath+"/media", conf.MediaBaseURL)
+	if err != nil {
+		log.Fatalf("can't create service `media`: %+v\n", err)
+	}
+	return mediaRepo
+}
+
 func connSupervisor(conf *config.Config) service.Connections {
 	return service.NewSV(time.Duration(conf.ConnectionsCheckoutDuration))
 }
@@ -99,7 +202,12 @@ func authorizer(
 		sessRepo,
 		connSupervisor,
 		resolver,
-		service.RhymenConnector{},
+		service.RateLimitedConnector{
+			Connector:     service.RhymenConnector{},
+			RatePerMinute: conf.SendRate,
+			Burst:         conf.SendBurst,
+			Jitter:        time.Duration(conf.SendJitter) * time.Millisecond,
+		},
 		make(chan string),
 	)
 	return authorizer